@@ -0,0 +1,10 @@
+// Package content embeds the default game data files
+package content
+
+import "embed"
+
+// FS holds the built-in content files. Set TBRPG_CONTENT_DIR to load a
+// directory with the same layout instead.
+//
+//go:embed *.json
+var FS embed.FS
@@ -0,0 +1,163 @@
+package game
+
+import (
+	"fmt"
+	"time"
+)
+
+// AchievementKind is what an achievement counts
+type AchievementKind string
+
+const (
+	AchievementGather AchievementKind = "gather" // Items obtained by gathering, crafting, processing or loot
+	AchievementDefeat AchievementKind = "defeat" // Fights won
+	AchievementReach  AchievementKind = "reach"  // Arrivals at a location
+	AchievementLevel  AchievementKind = "level"  // Level reached in a skill, or combat
+	AchievementQuests AchievementKind = "quests" // Quests completed
+)
+
+// CombatTarget names the combat level in level achievements
+const CombatTarget = "combat"
+
+// Achievement is a milestone earned by playing. Achievements are loaded
+// from content files and tracked from game events.
+type Achievement struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Kind        AchievementKind `json:"kind"`
+	Target      string          `json:"target,omitempty"` // Item, monster, location, skill or quest ID; empty matches any
+	Count       int             `json:"count"`            // Amount needed, or the level for level achievements
+}
+
+// AchievementProgress tracks an achievement the player has made progress on
+type AchievementProgress struct {
+	Count    int       `json:"count"`
+	Unlocked time.Time `json:"unlocked,omitzero"` // Zero until earned
+}
+
+// HasAchievement reports whether the player has earned an achievement
+func (s *State) HasAchievement(id string) bool {
+	progress, ok := s.Achievements[id]
+	return ok && !progress.Unlocked.IsZero()
+}
+
+// AchievementCount returns the player's progress toward an achievement
+func (s *State) AchievementCount(id string) int {
+	if progress, ok := s.Achievements[id]; ok {
+		return progress.Count
+	}
+	return 0
+}
+
+// trackAchievements subscribes achievement progress to the events it counts
+func (s *State) trackAchievements() {
+	On(s.Events, func(e ItemAdded) {
+		if e.Source.Obtained() {
+			s.advanceAchievements(AchievementGather, e.Item, e.Quantity)
+		}
+	})
+	On(s.Events, func(e MonsterDefeated) {
+		s.advanceAchievements(AchievementDefeat, e.Monster, 1)
+	})
+	On(s.Events, func(e LocationChanged) {
+		s.advanceAchievements(AchievementReach, e.To, 1)
+	})
+	On(s.Events, func(e QuestFinished) {
+		s.advanceAchievements(AchievementQuests, e.Quest, 1)
+	})
+	On(s.Events, func(e LevelUp) {
+		target := string(e.Skill)
+		if e.Skill == "" {
+			target = CombatTarget
+		}
+		s.reachLevel(target, e.Level)
+	})
+}
+
+// advanceAchievements adds progress to every locked achievement of a kind
+// whose target matches
+func (s *State) advanceAchievements(kind AchievementKind, target string, n int) {
+	for _, achievement := range s.Catalog.Achievements() {
+		if achievement.Kind != kind || (achievement.Target != "" && achievement.Target != target) {
+			continue
+		}
+		if s.HasAchievement(achievement.ID) {
+			continue
+		}
+		progress := s.achievementProgress(achievement.ID)
+		progress.Count = min(achievement.Count, progress.Count+n)
+		if progress.Count == achievement.Count {
+			s.unlockAchievement(achievement)
+		}
+	}
+}
+
+// reachLevel records a new level for level achievements. Progress is the
+// highest level reached rather than a running total.
+func (s *State) reachLevel(target string, level int) {
+	for _, achievement := range s.Catalog.Achievements() {
+		if achievement.Kind != AchievementLevel || achievement.Target != target {
+			continue
+		}
+		if s.HasAchievement(achievement.ID) {
+			continue
+		}
+		progress := s.achievementProgress(achievement.ID)
+		progress.Count = min(achievement.Count, max(progress.Count, level))
+		if progress.Count == achievement.Count {
+			s.unlockAchievement(achievement)
+		}
+	}
+}
+
+// achievementProgress returns the progress record for an achievement,
+// creating it if needed
+func (s *State) achievementProgress(id string) *AchievementProgress {
+	progress, ok := s.Achievements[id]
+	if !ok {
+		progress = &AchievementProgress{}
+		s.Achievements[id] = progress
+	}
+	return progress
+}
+
+// unlockAchievement marks an achievement earned and announces it
+func (s *State) unlockAchievement(achievement *Achievement) {
+	s.Achievements[achievement.ID].Unlocked = time.Now()
+	s.Events.Publish(AchievementUnlocked{Achievement: achievement.ID})
+}
+
+// DescribeAchievement renders an achievement's goal like "Defeat 10 Giant
+// Rat" or "Reach Woodcutting level 10"
+func (s *State) DescribeAchievement(achievement *Achievement) string {
+	switch achievement.Kind {
+	case AchievementGather:
+		if achievement.Target == "" {
+			return fmt.Sprintf("Gather %d items", achievement.Count)
+		}
+		return fmt.Sprintf("Gather %d %s", achievement.Count, s.Catalog.ItemName(achievement.Target))
+	case AchievementDefeat:
+		if achievement.Target == "" {
+			return fmt.Sprintf("Win %d fights", achievement.Count)
+		}
+		return fmt.Sprintf("Defeat %d %s", achievement.Count, s.Catalog.MonsterName(achievement.Target))
+	case AchievementReach:
+		if achievement.Target == "" {
+			return fmt.Sprintf("Travel %d times", achievement.Count)
+		}
+		return "Travel to " + s.Catalog.LocationName(achievement.Target)
+	case AchievementLevel:
+		if achievement.Target == CombatTarget {
+			return fmt.Sprintf("Reach combat level %d", achievement.Count)
+		}
+		return fmt.Sprintf("Reach %s level %d", achievement.Target, achievement.Count)
+	case AchievementQuests:
+		if achievement.Target == "" {
+			return fmt.Sprintf("Complete %d quests", achievement.Count)
+		}
+		quest, _ := s.Catalog.Quest(achievement.Target)
+		return "Complete " + quest.Name
+	}
+	return string(achievement.Kind)
+}
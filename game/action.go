@@ -0,0 +1,265 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ActionKind identifies what a timed action does when it completes
+type ActionKind string
+
+const (
+	ActionGather  ActionKind = "gather"
+	ActionCraft   ActionKind = "craft"
+	ActionProcess ActionKind = "process" // Runs in the processing queue
+	ActionTravel  ActionKind = "travel"  // Walks one route; the target is the next location
+)
+
+// RepeatForever makes an action repeat until cancelled
+const RepeatForever = -1
+
+// Action is a timed activity in the action queue. Actions are plain data so
+// they can be saved and resumed.
+type Action struct {
+	Kind    ActionKind    `json:"kind"`
+	Target  string        `json:"target"`  // Node, recipe, location, etc. depending on Kind
+	Elapsed time.Duration `json:"elapsed"` // Progress through the current cycle
+	Repeat  int           `json:"repeat"`  // Cycles left after this one, or RepeatForever
+}
+
+// NewAction creates an action that runs count times (0 or less repeats forever)
+func NewAction(kind ActionKind, target string, count int) Action {
+	repeat := RepeatForever
+	if count > 0 {
+		repeat = count - 1
+	}
+	return Action{Kind: kind, Target: target, Repeat: repeat}
+}
+
+// CurrentAction returns the action in progress, if any
+func (s *State) CurrentAction() (Action, bool) {
+	if len(s.Actions) == 0 {
+		return Action{}, false
+	}
+	return s.Actions[0], true
+}
+
+// StartAction replaces the action queue with a single action
+func (s *State) StartAction(action Action) error {
+	if action.Kind == ActionProcess {
+		return errors.New("processing runs at a station; use the processing queue")
+	}
+	if err := s.validateAction(action, s.Location); err != nil {
+		return err
+	}
+	s.Actions = []Action{action}
+	return nil
+}
+
+// QueueAction adds an action to the end of the queue. The action is
+// checked against where queued travel will have taken the player.
+func (s *State) QueueAction(action Action) error {
+	if action.Kind == ActionProcess {
+		return errors.New("processing runs at a station; use the processing queue")
+	}
+	if err := s.validateAction(action, s.Destination()); err != nil {
+		return err
+	}
+	s.Actions = append(s.Actions, action)
+	return nil
+}
+
+// CancelAction stops the current action and starts the next queued one
+func (s *State) CancelAction() (Action, bool) {
+	current, ok := s.CurrentAction()
+	if !ok {
+		return Action{}, false
+	}
+	s.Actions = s.Actions[1:]
+	return current, true
+}
+
+// ClearActions empties the action queue
+func (s *State) ClearActions() {
+	s.Actions = nil
+}
+
+// ActionDuration returns how long one cycle of an action takes
+func (s *State) ActionDuration(action Action) time.Duration {
+	switch action.Kind {
+	case ActionGather:
+		if node, ok := s.Catalog.ResourceNode(action.Target); ok {
+			return s.GatherDuration(node)
+		}
+	case ActionCraft:
+		if recipe, ok := s.Catalog.Recipe(action.Target); ok {
+			return s.CraftDuration(recipe)
+		}
+	case ActionProcess:
+		if conv, ok := s.Catalog.Conversion(action.Target); ok {
+			return s.ProcessDuration(conv)
+		}
+	case ActionTravel:
+		// The leg leaves from wherever the previous one ended
+		if route, ok := s.route(s.Location, action.Target); ok {
+			return route.Duration()
+		}
+	}
+	return TickInterval
+}
+
+// ActionLabel describes an action for display
+func (s *State) ActionLabel(action Action) string {
+	switch action.Kind {
+	case ActionGather:
+		if node, ok := s.Catalog.ResourceNode(action.Target); ok {
+			return fmt.Sprintf("%s: %s", node.Skill, node.Name)
+		}
+	case ActionCraft:
+		if recipe, ok := s.Catalog.Recipe(action.Target); ok {
+			return fmt.Sprintf("%s: %s", recipe.Skill, recipe.Name)
+		}
+	case ActionProcess:
+		if conv, ok := s.Catalog.Conversion(action.Target); ok {
+			return fmt.Sprintf("%s: %s", s.ProcessingSkill(conv), conv.Name)
+		}
+	case ActionTravel:
+		if location, ok := s.Catalog.Location(action.Target); ok {
+			return "Traveling: " + location.Name
+		}
+	}
+	return fmt.Sprintf("%s %s", action.Kind, action.Target)
+}
+
+// ActionProgress returns the fraction of the current cycle completed
+func (s *State) ActionProgress() float64 {
+	current, ok := s.CurrentAction()
+	if !ok {
+		return 0
+	}
+	return min(1, float64(current.Elapsed)/float64(s.ActionDuration(current)))
+}
+
+// Tick advances the game by the elapsed wall-clock time and returns the
+// number of action cycles completed
+func (s *State) Tick(elapsed time.Duration) int {
+	return s.advance(s.Clock.Advance(elapsed))
+}
+
+// advance simulates a number of fixed steps and returns the action cycles
+// completed. Live play and offline progress both run through here.
+func (s *State) advance(steps int) int {
+	completed, done := 0, 0
+	for ; done < steps && len(s.Actions)+len(s.Processing) > 0; done++ {
+		completed += s.step(TickInterval)
+	}
+	// Buffs and cooldowns keep running down once the queues are empty
+	s.stepEffects(time.Duration(steps-done) * TickInterval)
+	s.recover(time.Duration(steps) * TickInterval)
+	s.Market.step(s.Catalog, time.Duration(steps)*TickInterval)
+	return completed
+}
+
+// step advances the current action and the current conversion by one
+// fixed step. The action queue waits while the player is in a fight, so
+// queued travel never arrives mid-fight; stations keep working.
+func (s *State) step(dt time.Duration) int {
+	s.stepEffects(dt)
+	completed := 0
+	if !s.InCombat() {
+		completed += s.stepQueue(&s.Actions, dt)
+	}
+	return completed + s.stepQueue(&s.Processing, dt)
+}
+
+// stepQueue advances the first action of a queue by one fixed step
+func (s *State) stepQueue(queue *[]Action, dt time.Duration) int {
+	if len(*queue) == 0 {
+		return 0
+	}
+
+	current := &(*queue)[0]
+	current.Elapsed += dt
+
+	duration := s.ActionDuration(*current)
+	if current.Elapsed < duration {
+		return 0
+	}
+	current.Elapsed -= duration
+
+	if err := s.completeAction(*current); err != nil {
+		s.Log("System", "Stopped "+s.ActionLabel(*current)+":", err.Error())
+		*queue = (*queue)[1:]
+		return 0
+	}
+
+	switch {
+	case current.Repeat == RepeatForever:
+	case current.Repeat > 0:
+		current.Repeat--
+	default:
+		if current.Kind != ActionTravel { // Arrival is already logged
+			s.Log("System", "Finished "+s.ActionLabel(*current), "")
+		}
+		*queue = (*queue)[1:]
+	}
+	return 1
+}
+
+// validateAction checks that an action can start at a location
+func (s *State) validateAction(action Action, at string) error {
+	switch action.Kind {
+	case ActionGather:
+		node, ok := s.Catalog.ResourceNode(action.Target)
+		if !ok {
+			return fmt.Errorf("unknown resource node %q", action.Target)
+		}
+		return s.canGatherAt(node, at)
+	case ActionCraft:
+		recipe, ok := s.Catalog.Recipe(action.Target)
+		if !ok {
+			return fmt.Errorf("unknown recipe %q", action.Target)
+		}
+		return s.CanCraft(recipe)
+	case ActionProcess:
+		conv, ok := s.Catalog.Conversion(action.Target)
+		if !ok {
+			return fmt.Errorf("unknown conversion %q", action.Target)
+		}
+		location, ok := s.Catalog.Location(at)
+		if !ok {
+			return fmt.Errorf("unknown location %q", at)
+		}
+		// Conversions keep running after the player leaves the station
+		if !slices.Contains(location.Stations, conv.Station) {
+			station, _ := s.Catalog.Station(conv.Station)
+			return fmt.Errorf("there is no %s in %s", strings.ToLower(station.Name), location.Name)
+		}
+		return s.CanProcess(conv)
+	case ActionTravel:
+		if action.Repeat != 0 {
+			return errors.New("travel cannot repeat")
+		}
+		return s.canTravel(action.Target, at)
+	}
+	return fmt.Errorf("unknown action %q", action.Kind)
+}
+
+// completeAction applies the result of one finished cycle
+func (s *State) completeAction(action Action) error {
+	switch action.Kind {
+	case ActionGather:
+		_, err := s.Gather(action.Target)
+		return err
+	case ActionCraft:
+		return s.Craft(action.Target)
+	case ActionProcess:
+		return s.Process(action.Target)
+	case ActionTravel:
+		return s.arrive(action.Target)
+	}
+	return fmt.Errorf("unknown action %q", action.Kind)
+}
@@ -0,0 +1,89 @@
+package game
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// LogQuery selects activity log entries. Zero fields match everything.
+type LogQuery struct {
+	Categories []string  // Only entries in these categories
+	Exclude    []string  // No entries in these categories
+	Since      time.Time // Only entries at or after this time
+	Until      time.Time // Only entries before this time
+	Text       string    // Case-insensitive text in the category, action or details
+}
+
+// Matches reports whether an entry is selected by the query
+func (q LogQuery) Matches(entry LogEntry) bool {
+	if len(q.Categories) > 0 && !slices.Contains(q.Categories, entry.Category) {
+		return false
+	}
+	if slices.Contains(q.Exclude, entry.Category) {
+		return false
+	}
+	if !q.Since.IsZero() && entry.Timestamp.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !entry.Timestamp.Before(q.Until) {
+		return false
+	}
+	if q.Text != "" {
+		text := strings.ToLower(entry.Category + " " + entry.Action + " " + entry.Details)
+		return strings.Contains(text, strings.ToLower(q.Text))
+	}
+	return true
+}
+
+// Query returns the entries selected by q, oldest first
+func (al *ActivityLog) Query(q LogQuery) []LogEntry {
+	var matched []LogEntry
+	for _, entry := range al.entries {
+		if q.Matches(entry) {
+			matched = append(matched, entry)
+		}
+	}
+	return matched
+}
+
+// Categories returns the categories of the log's entries, sorted
+func (al *ActivityLog) Categories() []string {
+	var categories []string
+	for _, entry := range al.entries {
+		if !slices.Contains(categories, entry.Category) {
+			categories = append(categories, entry.Category)
+		}
+	}
+	slices.Sort(categories)
+	return categories
+}
+
+// LogCategories returns the categories the log view can show or hide:
+// those in the log and any hidden ones, sorted
+func (s *State) LogCategories() []string {
+	categories := s.ActivityLog.Categories()
+	for _, category := range s.LogHidden {
+		if !slices.Contains(categories, category) {
+			categories = append(categories, category)
+		}
+	}
+	slices.Sort(categories)
+	return categories
+}
+
+// LogCategoryHidden reports whether the log view hides a category
+func (s *State) LogCategoryHidden(category string) bool {
+	return slices.Contains(s.LogHidden, category)
+}
+
+// SetLogCategoryHidden hides or shows a category in the log view
+func (s *State) SetLogCategoryHidden(category string, hidden bool) {
+	i := slices.Index(s.LogHidden, category)
+	switch {
+	case hidden && i < 0:
+		s.LogHidden = append(s.LogHidden, category)
+	case !hidden && i >= 0:
+		s.LogHidden = slices.Delete(s.LogHidden, i, i+1)
+	}
+}
@@ -0,0 +1,47 @@
+package game
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestActivityLogKeepsRecentEntries(t *testing.T) {
+	log := NewActivityLog()
+	for i := range MaxLogEntries + 10 {
+		log.AddEntry("Test", strconv.Itoa(i), "")
+	}
+	entries := log.GetEntries()
+	if len(entries) != MaxLogEntries {
+		t.Fatalf("kept %d entries, want %d", len(entries), MaxLogEntries)
+	}
+	if first := entries[0].Action; first != "10" {
+		t.Errorf("oldest entry is %s, want 10", first)
+	}
+	if version := log.Version(); version != MaxLogEntries+10 {
+		t.Errorf("version %d, want %d", version, MaxLogEntries+10)
+	}
+}
+
+func TestSnapshotKeepsRecentLog(t *testing.T) {
+	s := newTestState(t)
+	s.ActivityLog.Clear()
+	for i := range savedLogEntries + 10 {
+		s.ActivityLog.AddEntry("Test", strconv.Itoa(i), "")
+	}
+	snap := s.snapshot()
+	if len(snap.ActivityLog) != savedLogEntries {
+		t.Fatalf("saved %d entries, want %d", len(snap.ActivityLog), savedLogEntries)
+	}
+
+	// Older saves may hold more than the log keeps
+	for i := range MaxLogEntries {
+		snap.ActivityLog = append(snap.ActivityLog, LogEntry{Action: "old " + strconv.Itoa(i)})
+	}
+	loaded, err := stateFromSnapshot(snap, s.Catalog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(loaded.ActivityLog.GetEntries()); n > MaxLogEntries {
+		t.Errorf("loaded %d entries, want at most %d", n, MaxLogEntries)
+	}
+}
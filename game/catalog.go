@@ -0,0 +1,877 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Content file names within a content directory
+const (
+	itemsFile        = "items.json"
+	categoriesFile   = "categories.json"
+	tagsFile         = "tags.json"
+	startFile        = "start.json"
+	recipesFile      = "recipes.json"
+	processingFile   = "processing.json"
+	questsFile       = "quests.json"
+	worldFile        = "world.json"
+	shopsFile        = "shops.json"
+	achievementsFile = "achievements.json"
+	nodesFile        = "nodes.json"
+	monstersFile     = "monsters.json"
+)
+
+// Catalog holds the item definitions and categories loaded from content
+// files
+type Catalog struct {
+	items        map[string]*ItemDef
+	order        []*ItemDef
+	categories   []ItemCategory
+	catTags      map[string][]string // Category name to tags required, including ancestors
+	tags         map[string]bool
+	start        []startingItem
+	recipes      []*Recipe
+	recipeByID   map[string]*Recipe
+	processing   processingContent
+	stations     map[string]*Station
+	convByID     map[string]*Conversion
+	quests       []*Quest
+	questByID    map[string]*Quest
+	world        worldContent
+	locByID      map[string]*Location
+	routesFrom   map[string][]Route // Both directions of every route
+	shops        []*Shop
+	shopByID     map[string]*Shop
+	achievements []*Achievement
+	achByID      map[string]*Achievement
+	nodes        []*ResourceNode
+	nodeByID     map[string]*ResourceNode
+	monsters     []*Monster
+	monsterByID  map[string]*Monster
+}
+
+// startingItem is an entry in the new game inventory
+type startingItem struct {
+	Item     string `json:"item"`
+	Quantity int    `json:"quantity"`
+	Equipped bool   `json:"equipped,omitempty"`
+}
+
+// CatalogError lists every problem found while loading content
+type CatalogError struct {
+	Problems []string
+}
+
+func (e *CatalogError) Error() string {
+	return "invalid game content:\n  " + strings.Join(e.Problems, "\n  ")
+}
+
+// ContentFS returns the directory named by TBRPG_CONTENT_DIR, or fallback
+// when it is unset
+func ContentFS(fallback fs.FS) fs.FS {
+	if dir := os.Getenv("TBRPG_CONTENT_DIR"); dir != "" {
+		return os.DirFS(dir)
+	}
+	return fallback
+}
+
+// LoadCatalog reads and validates the content files in fsys
+func LoadCatalog(fsys fs.FS) (*Catalog, error) {
+	c := &Catalog{
+		items:       make(map[string]*ItemDef),
+		catTags:     make(map[string][]string),
+		tags:        make(map[string]bool),
+		recipeByID:  make(map[string]*Recipe),
+		stations:    make(map[string]*Station),
+		convByID:    make(map[string]*Conversion),
+		questByID:   make(map[string]*Quest),
+		locByID:     make(map[string]*Location),
+		routesFrom:  make(map[string][]Route),
+		shopByID:    make(map[string]*Shop),
+		achByID:     make(map[string]*Achievement),
+		nodeByID:    make(map[string]*ResourceNode),
+		monsterByID: make(map[string]*Monster),
+	}
+	var problems []string
+	report := func(file, format string, args ...any) {
+		problems = append(problems, file+": "+fmt.Sprintf(format, args...))
+	}
+
+	var tags []string
+	var defs []*ItemDef
+	for _, f := range []struct {
+		name string
+		dest any
+	}{
+		{tagsFile, &tags},
+		{categoriesFile, &c.categories},
+		{itemsFile, &defs},
+		{startFile, &c.start},
+		{recipesFile, &c.recipes},
+		{processingFile, &c.processing},
+		{questsFile, &c.quests},
+		{worldFile, &c.world},
+		{shopsFile, &c.shops},
+		{achievementsFile, &c.achievements},
+		{nodesFile, &c.nodes},
+		{monstersFile, &c.monsters},
+	} {
+		if err := decodeContentFile(fsys, f.name, f.dest); err != nil {
+			report(f.name, "%v", err)
+		}
+	}
+	if len(problems) > 0 {
+		// Later checks would only repeat the missing-file errors
+		return nil, &CatalogError{Problems: problems}
+	}
+
+	for _, tag := range tags {
+		if c.tags[tag] {
+			report(tagsFile, "duplicate tag %q", tag)
+		}
+		c.tags[tag] = true
+	}
+
+	var walk func(cats []ItemCategory, inherited []string)
+	walk = func(cats []ItemCategory, inherited []string) {
+		for _, cat := range cats {
+			if cat.Name == "" {
+				report(categoriesFile, "category with no name")
+				continue
+			}
+			if _, dup := c.catTags[cat.Name]; dup || cat.Name == AllItemsCategory {
+				report(categoriesFile, "duplicate category %q", cat.Name)
+			}
+			if len(cat.Tags) == 0 {
+				report(categoriesFile, "category %q: no tags", cat.Name)
+			}
+			for _, tag := range cat.Tags {
+				if !c.tags[tag] {
+					report(categoriesFile, "category %q: unknown tag %q", cat.Name, tag)
+				}
+			}
+
+			tags := append(append([]string{}, inherited...), cat.Tags...)
+			c.catTags[cat.Name] = tags
+			walk(cat.Children, tags)
+		}
+	}
+	walk(c.categories, nil)
+
+	for i, def := range defs {
+		where := fmt.Sprintf("item %q", def.ID)
+		if def.ID == "" {
+			where = fmt.Sprintf("item #%d", i+1)
+			report(itemsFile, "%s: missing id", where)
+		} else if _, dup := c.items[def.ID]; dup {
+			report(itemsFile, "%s: duplicate id", where)
+			continue
+		}
+		if def.Name == "" {
+			report(itemsFile, "%s: missing name", where)
+		}
+		if def.Value < 0 {
+			report(itemsFile, "%s: negative value", where)
+		}
+		if def.StackLimit < 0 {
+			report(itemsFile, "%s: negative stack_limit", where)
+		}
+		if def.Weight < 0 {
+			report(itemsFile, "%s: negative weight", where)
+		}
+		if _, ok := c.catTags[def.Category]; !ok {
+			report(itemsFile, "%s: unknown category %q", where, def.Category)
+		}
+		for _, tag := range def.Tags {
+			if !c.tags[tag] {
+				report(itemsFile, "%s: unknown tag %q", where, tag)
+			}
+		}
+		if def.Slot != "" && !isSlotID(def.Slot) {
+			report(itemsFile, "%s: unknown slot %q", where, def.Slot)
+		}
+		if def.Stats == nil {
+			def.Stats = ParseStatModifiers(def.Description)
+		}
+		// Consumables without effects heal by the HP and MP they list
+		if def.Effects == nil && def.Slot == "" {
+			def.Effects = statEffects(def.Stats)
+		}
+		for _, effect := range def.Effects {
+			if err := checkEffect(effect); err != nil {
+				report(itemsFile, "%s: %v", where, err)
+			}
+		}
+		if def.Cooldown < 0 {
+			report(itemsFile, "%s: negative cooldown", where)
+		}
+
+		if def.ID != "" {
+			c.items[def.ID] = def
+			c.order = append(c.order, def)
+		}
+	}
+
+	for _, entry := range c.start {
+		if _, ok := c.items[entry.Item]; !ok {
+			report(startFile, "unknown item %q", entry.Item)
+		}
+		if entry.Quantity < 1 {
+			report(startFile, "item %q: quantity must be positive", entry.Item)
+		}
+		if def, ok := c.items[entry.Item]; ok && entry.Equipped && canEquip(def) != nil {
+			report(startFile, "item %q: cannot be equipped", entry.Item)
+		}
+	}
+
+	for i, recipe := range c.recipes {
+		where := fmt.Sprintf("recipe %q", recipe.ID)
+		if recipe.ID == "" {
+			where = fmt.Sprintf("recipe #%d", i+1)
+			report(recipesFile, "%s: missing id", where)
+		} else if _, dup := c.recipeByID[recipe.ID]; dup {
+			report(recipesFile, "%s: duplicate id", where)
+		} else {
+			c.recipeByID[recipe.ID] = recipe
+		}
+		if !isSkill(recipe.Skill) {
+			report(recipesFile, "%s: unknown skill %q", where, recipe.Skill)
+		}
+		if recipe.Level < 1 || recipe.Level > MaxSkillLevel {
+			report(recipesFile, "%s: level must be 1-%d", where, MaxSkillLevel)
+		}
+		if recipe.XP < 0 {
+			report(recipesFile, "%s: negative xp", where)
+		}
+		if recipe.Seconds <= 0 {
+			report(recipesFile, "%s: seconds must be positive", where)
+		}
+		if recipe.Tool != "" && !c.tags[recipe.Tool] {
+			report(recipesFile, "%s: unknown tool tag %q", where, recipe.Tool)
+		}
+		c.checkAmounts(report, recipesFile, where, "inputs", recipe.Inputs, true)
+		c.checkAmounts(report, recipesFile, where, "outputs", recipe.Outputs, true)
+		if recipe.Name == "" && len(recipe.Outputs) > 0 {
+			recipe.Name = c.ItemName(recipe.Outputs[0].Item)
+		}
+	}
+
+	for i, station := range c.processing.Stations {
+		where := fmt.Sprintf("station %q", station.ID)
+		if station.ID == "" {
+			where = fmt.Sprintf("station #%d", i+1)
+			report(processingFile, "%s: missing id", where)
+		} else if _, dup := c.stations[station.ID]; dup {
+			report(processingFile, "%s: duplicate id", where)
+		} else {
+			c.stations[station.ID] = station
+		}
+		if station.Name == "" {
+			report(processingFile, "%s: missing name", where)
+		}
+		if !isSkill(station.Skill) {
+			report(processingFile, "%s: unknown skill %q", where, station.Skill)
+		}
+		c.checkAmounts(report, processingFile, where, "fuel", station.Fuel, false)
+	}
+
+	for i, conv := range c.processing.Conversions {
+		where := fmt.Sprintf("conversion %q", conv.ID)
+		if conv.ID == "" {
+			where = fmt.Sprintf("conversion #%d", i+1)
+			report(processingFile, "%s: missing id", where)
+		} else if _, dup := c.convByID[conv.ID]; dup {
+			report(processingFile, "%s: duplicate id", where)
+		} else {
+			c.convByID[conv.ID] = conv
+		}
+		if _, ok := c.stations[conv.Station]; !ok {
+			report(processingFile, "%s: unknown station %q", where, conv.Station)
+		}
+		if conv.Level < 1 || conv.Level > MaxSkillLevel {
+			report(processingFile, "%s: level must be 1-%d", where, MaxSkillLevel)
+		}
+		if conv.XP < 0 {
+			report(processingFile, "%s: negative xp", where)
+		}
+		if conv.Seconds <= 0 {
+			report(processingFile, "%s: seconds must be positive", where)
+		}
+		if conv.FailRate < 0 || conv.FailRate > 100 {
+			report(processingFile, "%s: fail_rate must be 0-100", where)
+		}
+		if conv.FailRate > 0 && conv.SafeAt <= conv.Level {
+			report(processingFile, "%s: safe_at must be above level", where)
+		}
+		c.checkAmounts(report, processingFile, where, "inputs", conv.Inputs, true)
+		c.checkAmounts(report, processingFile, where, "outputs", conv.Outputs, true)
+		c.checkAmounts(report, processingFile, where, "failed", conv.Failed, false)
+		if conv.Name == "" && len(conv.Outputs) > 0 {
+			conv.Name = c.ItemName(conv.Outputs[0].Item)
+		}
+	}
+
+	for i, shop := range c.shops {
+		where := fmt.Sprintf("shop %q", shop.ID)
+		if shop.ID == "" {
+			where = fmt.Sprintf("shop #%d", i+1)
+			report(shopsFile, "%s: missing id", where)
+		} else if _, dup := c.shopByID[shop.ID]; dup {
+			report(shopsFile, "%s: duplicate id", where)
+		} else {
+			c.shopByID[shop.ID] = shop
+		}
+		if shop.Name == "" {
+			report(shopsFile, "%s: missing name", where)
+		}
+		if shop.Markup < 0 {
+			report(shopsFile, "%s: negative markup", where)
+		}
+		if shop.Payout < 0 || shop.Payout > 100 {
+			report(shopsFile, "%s: payout must be 0-100", where)
+		}
+		if shop.Restock < 0 {
+			report(shopsFile, "%s: negative restock", where)
+		}
+		for _, tag := range shop.Buys {
+			if !c.tags[tag] {
+				report(shopsFile, "%s: unknown tag %q in buys", where, tag)
+			}
+		}
+		c.checkAmounts(report, shopsFile, where, "stock", shop.Stock, false)
+	}
+
+	for i, node := range c.nodes {
+		where := fmt.Sprintf("node %q", node.ID)
+		if node.ID == "" {
+			where = fmt.Sprintf("node #%d", i+1)
+			report(nodesFile, "%s: missing id", where)
+		} else if _, dup := c.nodeByID[node.ID]; dup {
+			report(nodesFile, "%s: duplicate id", where)
+		} else {
+			c.nodeByID[node.ID] = node
+		}
+		if node.Name == "" {
+			report(nodesFile, "%s: missing name", where)
+		}
+		if !slices.Contains(GatheringSkills, node.Skill) {
+			report(nodesFile, "%s: unknown gathering skill %q", where, node.Skill)
+		}
+		if node.Level < 1 || node.Level > MaxSkillLevel {
+			report(nodesFile, "%s: level must be 1-%d", where, MaxSkillLevel)
+		}
+		if _, ok := c.items[node.ItemID]; !ok {
+			report(nodesFile, "%s: unknown item %q", where, node.ItemID)
+		}
+		if node.XP < 0 {
+			report(nodesFile, "%s: negative xp", where)
+		}
+		if node.Seconds <= 0 {
+			report(nodesFile, "%s: seconds must be positive", where)
+		}
+	}
+
+	for i, monster := range c.monsters {
+		where := fmt.Sprintf("monster %q", monster.ID)
+		if monster.ID == "" {
+			where = fmt.Sprintf("monster #%d", i+1)
+			report(monstersFile, "%s: missing id", where)
+		} else if _, dup := c.monsterByID[monster.ID]; dup {
+			report(monstersFile, "%s: duplicate id", where)
+		} else {
+			c.monsterByID[monster.ID] = monster
+		}
+		if monster.Name == "" {
+			report(monstersFile, "%s: missing name", where)
+		}
+		if monster.Level < 1 {
+			report(monstersFile, "%s: level must be positive", where)
+		}
+		if monster.HP < 1 {
+			report(monstersFile, "%s: hp must be positive", where)
+		}
+		if monster.Attack < 0 || monster.Defense < 0 || monster.Speed < 0 || monster.XP < 0 {
+			report(monstersFile, "%s: negative stat", where)
+		}
+		if monster.Gold[0] < 0 || monster.Gold[1] < monster.Gold[0] {
+			report(monstersFile, "%s: gold must be a range from 0 up", where)
+		}
+		for _, drop := range monster.Loot {
+			if _, ok := c.items[drop.ItemID]; !ok {
+				report(monstersFile, "%s: unknown item %q in loot", where, drop.ItemID)
+			}
+			if drop.Chance <= 0 || drop.Chance > 1 {
+				report(monstersFile, "%s: item %q: chance must be above 0 and at most 1", where, drop.ItemID)
+			}
+			if drop.Min < 1 || drop.Max < drop.Min {
+				report(monstersFile, "%s: item %q: min must be positive and max at least min", where, drop.ItemID)
+			}
+		}
+	}
+
+	c.checkWorld(report)
+
+	for i, quest := range c.quests {
+		where := fmt.Sprintf("quest %q", quest.ID)
+		if quest.ID == "" {
+			where = fmt.Sprintf("quest #%d", i+1)
+			report(questsFile, "%s: missing id", where)
+		} else if _, dup := c.questByID[quest.ID]; dup {
+			report(questsFile, "%s: duplicate id", where)
+		} else {
+			c.questByID[quest.ID] = quest
+		}
+		if quest.Name == "" {
+			report(questsFile, "%s: missing name", where)
+		}
+		if len(quest.Objectives) == 0 {
+			report(questsFile, "%s: no objectives", where)
+		}
+		for j := range quest.Objectives {
+			c.checkObjective(report, where, j, &quest.Objectives[j])
+		}
+		if quest.Reward.Gold < 0 || quest.Reward.XP < 0 {
+			report(questsFile, "%s: negative reward", where)
+		}
+		for skill, xp := range quest.Reward.Skills {
+			if !isSkill(skill) {
+				report(questsFile, "%s: unknown reward skill %q", where, skill)
+			}
+			if xp < 0 {
+				report(questsFile, "%s: negative %s xp", where, skill)
+			}
+		}
+		c.checkAmounts(report, questsFile, where, "reward items", quest.Reward.Items, false)
+	}
+	// Prerequisites may name quests later in the file, so check them once
+	// every ID is known
+	for _, quest := range c.quests {
+		for _, id := range quest.Requires {
+			if _, ok := c.questByID[id]; !ok || id == quest.ID {
+				report(questsFile, "quest %q: unknown prerequisite %q", quest.ID, id)
+			}
+		}
+	}
+
+	for i, achievement := range c.achievements {
+		where := fmt.Sprintf("achievement %q", achievement.ID)
+		if achievement.ID == "" {
+			where = fmt.Sprintf("achievement #%d", i+1)
+			report(achievementsFile, "%s: missing id", where)
+		} else if _, dup := c.achByID[achievement.ID]; dup {
+			report(achievementsFile, "%s: duplicate id", where)
+		} else {
+			c.achByID[achievement.ID] = achievement
+		}
+		if achievement.Name == "" {
+			report(achievementsFile, "%s: missing name", where)
+		}
+		c.checkAchievement(report, where, achievement)
+	}
+
+	if len(problems) > 0 {
+		return nil, &CatalogError{Problems: problems}
+	}
+	return c, nil
+}
+
+// checkWorld validates the world map and indexes its locations and routes.
+// Every location must be reachable from the start.
+func (c *Catalog) checkWorld(report func(file, format string, args ...any)) {
+	for i, location := range c.world.Locations {
+		where := fmt.Sprintf("location %q", location.ID)
+		if location.ID == "" {
+			where = fmt.Sprintf("location #%d", i+1)
+			report(worldFile, "%s: missing id", where)
+		} else if _, dup := c.locByID[location.ID]; dup {
+			report(worldFile, "%s: duplicate id", where)
+		} else {
+			c.locByID[location.ID] = location
+		}
+		if location.Name == "" {
+			report(worldFile, "%s: missing name", where)
+		}
+		if !slices.Contains(locationKinds, location.Kind) {
+			report(worldFile, "%s: unknown kind %q", where, location.Kind)
+		}
+		for _, id := range location.Nodes {
+			if _, ok := c.nodeByID[id]; !ok {
+				report(worldFile, "%s: unknown resource node %q", where, id)
+			}
+		}
+		for _, id := range location.Stations {
+			if _, ok := c.stations[id]; !ok {
+				report(worldFile, "%s: unknown station %q", where, id)
+			}
+		}
+		for _, id := range location.Monsters {
+			if _, ok := c.monsterByID[id]; !ok {
+				report(worldFile, "%s: unknown monster %q", where, id)
+			}
+		}
+		for _, id := range location.Shops {
+			if _, ok := c.shopByID[id]; !ok {
+				report(worldFile, "%s: unknown shop %q", where, id)
+			}
+		}
+	}
+
+	for _, route := range c.world.Routes {
+		where := fmt.Sprintf("route %s-%s", route.From, route.To)
+		_, fromOK := c.locByID[route.From]
+		_, toOK := c.locByID[route.To]
+		if !fromOK || !toOK || route.From == route.To {
+			report(worldFile, "%s: must join two different known locations", where)
+			continue
+		}
+		if route.Seconds <= 0 {
+			report(worldFile, "%s: seconds must be positive", where)
+		}
+		back := Route{From: route.To, To: route.From, Seconds: route.Seconds}
+		c.routesFrom[route.From] = append(c.routesFrom[route.From], route)
+		c.routesFrom[route.To] = append(c.routesFrom[route.To], back)
+	}
+
+	if _, ok := c.locByID[c.world.Start]; !ok {
+		report(worldFile, "unknown start location %q", c.world.Start)
+		return
+	}
+	reached := map[string]bool{c.world.Start: true}
+	pending := []string{c.world.Start}
+	for len(pending) > 0 {
+		id := pending[0]
+		pending = pending[1:]
+		for _, route := range c.routesFrom[id] {
+			if !reached[route.To] {
+				reached[route.To] = true
+				pending = append(pending, route.To)
+			}
+		}
+	}
+	for _, location := range c.world.Locations {
+		if location.ID != "" && !reached[location.ID] {
+			report(worldFile, "location %q: unreachable from %q", location.ID, c.world.Start)
+		}
+	}
+}
+
+// checkObjective validates one quest objective. Reach objectives default
+// to a count of one.
+func (c *Catalog) checkObjective(report func(file, format string, args ...any), where string, index int, objective *Objective) {
+	where = fmt.Sprintf("%s objective %d", where, index+1)
+	switch objective.Kind {
+	case ObjectiveGather, ObjectiveDeliver:
+		if _, ok := c.items[objective.Target]; !ok {
+			report(questsFile, "%s: unknown item %q", where, objective.Target)
+		}
+	case ObjectiveDefeat:
+		if _, ok := c.monsterByID[objective.Target]; !ok {
+			report(questsFile, "%s: unknown monster %q", where, objective.Target)
+		}
+	case ObjectiveReach:
+		if _, ok := c.locByID[objective.Target]; !ok {
+			report(questsFile, "%s: unknown location %q", where, objective.Target)
+		}
+		if objective.Count == 0 {
+			objective.Count = 1
+		}
+	default:
+		report(questsFile, "%s: unknown kind %q", where, objective.Kind)
+	}
+	if objective.Count < 1 {
+		report(questsFile, "%s: count must be positive", where)
+	}
+}
+
+// checkAchievement validates an achievement's kind and target. Only level
+// achievements need a target; the others match anything without one.
+func (c *Catalog) checkAchievement(report func(file, format string, args ...any), where string, achievement *Achievement) {
+	target := achievement.Target
+	switch achievement.Kind {
+	case AchievementGather:
+		if _, ok := c.items[target]; target != "" && !ok {
+			report(achievementsFile, "%s: unknown item %q", where, target)
+		}
+	case AchievementDefeat:
+		if _, ok := c.monsterByID[target]; target != "" && !ok {
+			report(achievementsFile, "%s: unknown monster %q", where, target)
+		}
+	case AchievementReach:
+		if _, ok := c.locByID[target]; target != "" && !ok {
+			report(achievementsFile, "%s: unknown location %q", where, target)
+		}
+	case AchievementQuests:
+		if _, ok := c.questByID[target]; target != "" && !ok {
+			report(achievementsFile, "%s: unknown quest %q", where, target)
+		}
+	case AchievementLevel:
+		if target != CombatTarget && !isSkill(SkillType(target)) {
+			report(achievementsFile, "%s: unknown skill %q", where, target)
+		}
+	default:
+		report(achievementsFile, "%s: unknown kind %q", where, achievement.Kind)
+	}
+	if achievement.Count < 1 {
+		report(achievementsFile, "%s: count must be positive", where)
+	}
+}
+
+// checkAmounts validates a list of item amounts in a recipe or conversion
+func (c *Catalog) checkAmounts(report func(file, format string, args ...any), file, where, name string, amounts []ItemAmount, required bool) {
+	if required && len(amounts) == 0 {
+		report(file, "%s: no %s", where, name)
+	}
+	seen := make(map[string]bool)
+	for _, amount := range amounts {
+		if _, ok := c.items[amount.Item]; !ok {
+			report(file, "%s: unknown item %q in %s", where, amount.Item, name)
+		}
+		if seen[amount.Item] {
+			report(file, "%s: item %q listed twice in %s", where, amount.Item, name)
+		}
+		seen[amount.Item] = true
+		if amount.Quantity < 1 {
+			report(file, "%s: item %q: quantity must be positive", where, amount.Item)
+		}
+	}
+}
+
+// decodeContentFile strictly decodes one JSON content file
+func decodeContentFile(fsys fs.FS, name string, dest any) error {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return errors.New("file not found")
+		}
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dest); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			line := bytes.Count(data[:syntaxErr.Offset], []byte("\n")) + 1
+			return fmt.Errorf("line %d: %v", line, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// Item returns the definition with the given ID
+func (c *Catalog) Item(id string) (*ItemDef, bool) {
+	def, ok := c.items[id]
+	return def, ok
+}
+
+// Items returns all definitions in file order
+func (c *Catalog) Items() []*ItemDef {
+	return c.order
+}
+
+// Categories returns the top-level storage categories
+func (c *Catalog) Categories() []ItemCategory {
+	return c.categories
+}
+
+// CategoryNames returns every category name including AllItemsCategory,
+// in tree order
+func (c *Catalog) CategoryNames() []string {
+	names := []string{AllItemsCategory}
+	var walk func(cats []ItemCategory)
+	walk = func(cats []ItemCategory) {
+		for _, cat := range cats {
+			names = append(names, cat.Name)
+			walk(cat.Children)
+		}
+	}
+	walk(c.categories)
+	return names
+}
+
+// InCategory reports whether an item belongs to the named category
+func (c *Catalog) InCategory(item Item, category string) bool {
+	if category == "" || category == AllItemsCategory {
+		return true
+	}
+	tags, ok := c.catTags[category]
+	if !ok {
+		return false
+	}
+	for _, tag := range tags {
+		if !hasAnyTag(item, []string{tag}) {
+			return false
+		}
+	}
+	return true
+}
+
+// NewItem creates a stack of the item with the given ID
+func (c *Catalog) NewItem(id string, quantity int) (Item, error) {
+	def, ok := c.items[id]
+	if !ok {
+		return Item{}, fmt.Errorf("unknown item %q", id)
+	}
+	return Item{ItemDef: def, Quantity: quantity}, nil
+}
+
+// ItemName returns the display name for an item ID, or the ID itself
+func (c *Catalog) ItemName(id string) string {
+	if def, ok := c.items[id]; ok {
+		return def.Name
+	}
+	return id
+}
+
+// Recipes returns all recipes in file order
+func (c *Catalog) Recipes() []*Recipe {
+	return c.recipes
+}
+
+// Recipe returns the recipe with the given ID
+func (c *Catalog) Recipe(id string) (*Recipe, bool) {
+	recipe, ok := c.recipeByID[id]
+	return recipe, ok
+}
+
+// Stations returns all processing stations in file order
+func (c *Catalog) Stations() []*Station {
+	return c.processing.Stations
+}
+
+// Station returns the station with the given ID
+func (c *Catalog) Station(id string) (*Station, bool) {
+	station, ok := c.stations[id]
+	return station, ok
+}
+
+// Conversions returns the conversions a station can run, in file order
+func (c *Catalog) Conversions(stationID string) []*Conversion {
+	var convs []*Conversion
+	for _, conv := range c.processing.Conversions {
+		if conv.Station == stationID {
+			convs = append(convs, conv)
+		}
+	}
+	return convs
+}
+
+// Conversion returns the conversion with the given ID
+func (c *Catalog) Conversion(id string) (*Conversion, bool) {
+	conv, ok := c.convByID[id]
+	return conv, ok
+}
+
+// Quests returns all quests in file order
+func (c *Catalog) Quests() []*Quest {
+	return c.quests
+}
+
+// Quest returns the quest with the given ID
+func (c *Catalog) Quest(id string) (*Quest, bool) {
+	quest, ok := c.questByID[id]
+	return quest, ok
+}
+
+// Locations returns all locations in file order
+func (c *Catalog) Locations() []*Location {
+	return c.world.Locations
+}
+
+// Location returns the location with the given ID
+func (c *Catalog) Location(id string) (*Location, bool) {
+	location, ok := c.locByID[id]
+	return location, ok
+}
+
+// LocationName returns the display name for a location ID, or the ID
+// itself if unknown
+func (c *Catalog) LocationName(id string) string {
+	if location, ok := c.locByID[id]; ok {
+		return location.Name
+	}
+	return id
+}
+
+// StartLocation returns where new characters begin
+func (c *Catalog) StartLocation() string {
+	return c.world.Start
+}
+
+// RoutesFrom returns the routes leaving a location, in file order
+func (c *Catalog) RoutesFrom(id string) []Route {
+	return c.routesFrom[id]
+}
+
+// Shops returns all shops in file order
+func (c *Catalog) Shops() []*Shop {
+	return c.shops
+}
+
+// Shop returns the shop with the given ID
+func (c *Catalog) Shop(id string) (*Shop, bool) {
+	shop, ok := c.shopByID[id]
+	return shop, ok
+}
+
+// Achievements returns all achievements in file order
+func (c *Catalog) Achievements() []*Achievement {
+	return c.achievements
+}
+
+// Achievement returns the achievement with the given ID
+func (c *Catalog) Achievement(id string) (*Achievement, bool) {
+	achievement, ok := c.achByID[id]
+	return achievement, ok
+}
+
+// ResourceNodes returns all resource nodes in file order
+func (c *Catalog) ResourceNodes() []*ResourceNode {
+	return c.nodes
+}
+
+// ResourceNode returns the node with the given ID
+func (c *Catalog) ResourceNode(id string) (*ResourceNode, bool) {
+	node, ok := c.nodeByID[id]
+	return node, ok
+}
+
+// Monsters returns all monsters in file order
+func (c *Catalog) Monsters() []*Monster {
+	return c.monsters
+}
+
+// Monster returns the monster with the given ID
+func (c *Catalog) Monster(id string) (*Monster, bool) {
+	monster, ok := c.monsterByID[id]
+	return monster, ok
+}
+
+// MonsterName returns the display name for a monster ID, or the ID itself
+// if unknown
+func (c *Catalog) MonsterName(id string) string {
+	if monster, ok := c.monsterByID[id]; ok {
+		return monster.Name
+	}
+	return id
+}
+
+// StartingItems returns the inventory for a new game
+func (c *Catalog) StartingItems() []Item {
+	items := make([]Item, 0, len(c.start))
+	for _, entry := range c.start {
+		items = append(items, Item{
+			ItemDef:  c.items[entry.Item],
+			Quantity: entry.Quantity,
+			Equipped: entry.Equipped,
+		})
+	}
+	return items
+}
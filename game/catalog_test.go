@@ -0,0 +1,113 @@
+package game
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jexxer/tbrpg/content"
+)
+
+// contentWith copies the built-in content and replaces some files. An
+// empty replacement removes the file.
+func contentWith(t *testing.T, files map[string]string) fs.FS {
+	t.Helper()
+	fsys := fstest.MapFS{}
+	entries, err := fs.ReadDir(content.FS, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		data, err := fs.ReadFile(content.FS, entry.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		fsys[entry.Name()] = &fstest.MapFile{Data: data}
+	}
+	for name, data := range files {
+		if data == "" {
+			delete(fsys, name)
+			continue
+		}
+		fsys[name] = &fstest.MapFile{Data: []byte(data)}
+	}
+	return fsys
+}
+
+func TestLoadCatalogNodesAndMonsters(t *testing.T) {
+	catalog, err := LoadCatalog(content.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, location := range catalog.Locations() {
+		for _, id := range location.Nodes {
+			if _, ok := catalog.ResourceNode(id); !ok {
+				t.Errorf("%s: node %q not loaded", location.ID, id)
+			}
+		}
+		for _, id := range location.Monsters {
+			if _, ok := catalog.Monster(id); !ok {
+				t.Errorf("%s: monster %q not loaded", location.ID, id)
+			}
+		}
+	}
+	if node, _ := catalog.ResourceNode("oak_tree"); node.Duration().Seconds() != 3 {
+		t.Errorf("oak tree takes %s, want 3s", node.Duration())
+	}
+}
+
+func TestLoadCatalogRejectsBadNodesAndMonsters(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			name:  "node item",
+			files: map[string]string{nodesFile: `[{"id": "oak_tree", "name": "Oak", "skill": "Woodcutting", "level": 1, "item": "gold_leaf", "xp": 1, "seconds": 1}]`},
+			want:  `nodes.json: node "oak_tree": unknown item "gold_leaf"`,
+		},
+		{
+			name:  "node skill",
+			files: map[string]string{nodesFile: `[{"id": "oak_tree", "name": "Oak", "skill": "Cooking", "level": 1, "item": "wood_oak", "xp": 1, "seconds": 1}]`},
+			want:  `nodes.json: node "oak_tree": unknown gathering skill "Cooking"`,
+		},
+		{
+			name:  "world node",
+			files: map[string]string{nodesFile: `[]`},
+			want:  `world.json: location "oakwood": unknown resource node "oak_tree"`,
+		},
+		{
+			name:  "monster loot",
+			files: map[string]string{monstersFile: `[{"id": "giant_rat", "name": "Rat", "level": 1, "hp": 5, "gold": [0, 1], "loot": [{"item": "cheese", "chance": 0.5, "min": 1, "max": 1}]}]`},
+			want:  `monsters.json: monster "giant_rat": unknown item "cheese" in loot`,
+		},
+		{
+			name:  "monster gold",
+			files: map[string]string{monstersFile: `[{"id": "giant_rat", "name": "Rat", "level": 1, "hp": 5, "gold": [3, 1]}]`},
+			want:  `monsters.json: monster "giant_rat": gold must be a range from 0 up`,
+		},
+		{
+			name:  "world monster",
+			files: map[string]string{monstersFile: `[]`},
+			want:  `world.json: location "oakwood": unknown monster "goblin"`,
+		},
+		{
+			name:  "missing file",
+			files: map[string]string{monstersFile: ""},
+			want:  "monsters.json: file not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadCatalog(contentWith(t, tt.files))
+			if err == nil {
+				t.Fatal("content loaded")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %q does not mention %q", err, tt.want)
+			}
+		})
+	}
+}
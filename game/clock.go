@@ -0,0 +1,37 @@
+package game
+
+import "time"
+
+// TickInterval is the fixed simulation step. Game rules always advance in
+// whole ticks so results do not depend on how often the UI refreshes.
+const TickInterval = 100 * time.Millisecond
+
+// maxCatchUp caps how much real time a single Advance may simulate, so a
+// stalled UI does not trigger a burst of work
+const maxCatchUp = 5 * time.Second
+
+// Clock converts elapsed wall-clock time into fixed simulation steps
+type Clock struct {
+	Paused  bool
+	Ticks   uint64        // Steps simulated since the game was created
+	pending time.Duration // Elapsed time not yet consumed by a step
+}
+
+// Advance accumulates elapsed time and returns how many steps to simulate
+func (c *Clock) Advance(elapsed time.Duration) int {
+	if c.Paused || elapsed <= 0 {
+		return 0
+	}
+
+	c.pending += min(elapsed, maxCatchUp)
+	steps := int(c.pending / TickInterval)
+	c.pending -= time.Duration(steps) * TickInterval
+	c.Ticks += uint64(steps)
+	return steps
+}
+
+// SetPaused pauses or resumes the clock, discarding partial steps
+func (c *Clock) SetPaused(paused bool) {
+	c.Paused = paused
+	c.pending = 0
+}
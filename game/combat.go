@@ -0,0 +1,350 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Monster is a creature the player can fight
+type Monster struct {
+	ID      string     `json:"id"`
+	Name    string     `json:"name"`
+	Level   int        `json:"level"`
+	HP      int        `json:"hp"`
+	Attack  int        `json:"attack"`
+	Defense int        `json:"defense"`
+	Speed   int        `json:"speed"` // Compared with the player's agility to decide who acts first
+	XP      int        `json:"xp"`
+	Gold    [2]int     `json:"gold"` // Minimum and maximum gold dropped
+	Loot    []LootDrop `json:"loot,omitempty"`
+}
+
+// LootDrop is one entry in a monster's loot table. Each entry is rolled
+// independently.
+type LootDrop struct {
+	ItemID string  `json:"item"`
+	Chance float64 `json:"chance"` // 0 to 1
+	Min    int     `json:"min"`
+	Max    int     `json:"max"`
+}
+
+// CombatActionKind is what the player does on their turn
+type CombatActionKind string
+
+const (
+	CombatAttack  CombatActionKind = "attack"
+	CombatDefend  CombatActionKind = "defend"
+	CombatUseItem CombatActionKind = "use"
+	CombatFlee    CombatActionKind = "flee"
+)
+
+// CombatAction is the player's choice for one round
+type CombatAction struct {
+	Kind   CombatActionKind
+	ItemID string // Consumable to use with CombatUseItem
+}
+
+// EncounterOutcome is how an encounter ended, if it has
+type EncounterOutcome string
+
+const (
+	EncounterOngoing EncounterOutcome = ""
+	EncounterWon     EncounterOutcome = "won"
+	EncounterLost    EncounterOutcome = "lost"
+	EncounterFled    EncounterOutcome = "fled"
+)
+
+// Combat tuning
+const (
+	maxEncounterLog  = 8
+	baseCritChance   = 5  // Percent, before the weapon's crit stat
+	deathGoldPenalty = 10 // Percent of gold lost on death
+	regenInterval    = 5 * time.Second
+)
+
+// Encounter is a fight in progress. Encounters are plain data so a fight
+// survives saving and loading.
+type Encounter struct {
+	MonsterID string           `json:"monster_id"`
+	MonsterHP int              `json:"monster_hp"`
+	Round     int              `json:"round"`
+	Outcome   EncounterOutcome `json:"outcome,omitempty"`
+	Log       []string         `json:"log,omitempty"` // Recent combat messages, oldest first
+}
+
+// IsOver reports whether the encounter has ended
+func (e *Encounter) IsOver() bool {
+	return e.Outcome != EncounterOngoing
+}
+
+func (e *Encounter) say(format string, args ...any) {
+	e.Log = append(e.Log, fmt.Sprintf(format, args...))
+	if len(e.Log) > maxEncounterLog {
+		e.Log = e.Log[len(e.Log)-maxEncounterLog:]
+	}
+}
+
+// CurrentMonster returns the monster being fought, or nil if there is no
+// encounter
+func (s *State) CurrentMonster() *Monster {
+	if s.Encounter == nil {
+		return nil
+	}
+	monster, _ := s.Catalog.Monster(s.Encounter.MonsterID)
+	return monster
+}
+
+// InCombat reports whether a fight is in progress
+func (s *State) InCombat() bool {
+	return s.Encounter != nil && !s.Encounter.IsOver()
+}
+
+// StartEncounter begins a fight with a monster, replacing a finished one
+func (s *State) StartEncounter(monsterID string) error {
+	if s.InCombat() {
+		return fmt.Errorf("already fighting %s", s.CurrentMonster().Name)
+	}
+	monster, ok := s.Catalog.Monster(monsterID)
+	if !ok {
+		return fmt.Errorf("unknown monster %q", monsterID)
+	}
+	if !slices.Contains(s.CurrentLocation().Monsters, monster.ID) {
+		return fmt.Errorf("there is no %s in %s", monster.Name, s.CurrentLocation().Name)
+	}
+	if s.Player.HP <= 0 {
+		return errors.New("you are too weak to fight")
+	}
+
+	s.Encounter = &Encounter{MonsterID: monster.ID, MonsterHP: monster.HP}
+	s.Encounter.say("A level %d %s appears!", monster.Level, monster.Name)
+	s.Log("Combat", "Engaged "+monster.Name, fmt.Sprintf("(level %d)", monster.Level))
+	return nil
+}
+
+// EndEncounter leaves a finished fight
+func (s *State) EndEncounter() error {
+	if s.InCombat() {
+		return errors.New("the fight is not over; flee first")
+	}
+	s.Encounter = nil
+	return nil
+}
+
+// CombatTurn plays one round. The faster combatant acts first; defending
+// halves the monster's damage for the whole round.
+func (s *State) CombatTurn(action CombatAction) error {
+	if !s.InCombat() {
+		return errors.New("not in combat")
+	}
+	if err := s.validateCombatAction(action); err != nil {
+		return err
+	}
+
+	enc := s.Encounter
+	monster := s.CurrentMonster()
+	defending := action.Kind == CombatDefend
+
+	// A failed player turn ends the round early and doesn't count it
+	if s.Player.Attributes.Agility >= monster.Speed {
+		if err := s.playerTurn(action); err != nil {
+			return err
+		}
+		if !enc.IsOver() {
+			s.monsterTurn(defending)
+		}
+	} else {
+		s.monsterTurn(defending)
+		if !enc.IsOver() {
+			if err := s.playerTurn(action); err != nil {
+				return err
+			}
+		}
+	}
+	enc.Round++
+	return nil
+}
+
+func (s *State) validateCombatAction(action CombatAction) error {
+	switch action.Kind {
+	case CombatAttack, CombatDefend, CombatFlee:
+		return nil
+	case CombatUseItem:
+		item, ok := s.Storage.Find(action.ItemID)
+		if !ok {
+			return fmt.Errorf("no item with id %q in storage", action.ItemID)
+		}
+		if !usable(item.ItemDef) {
+			return fmt.Errorf("%s has no use in combat", item.Name)
+		}
+		if left := s.Cooldown(item.ID); left > 0 {
+			return fmt.Errorf("%s is ready in %s", item.Name, FormatDuration(left))
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown combat action %q", action.Kind)
+}
+
+func (s *State) playerTurn(action CombatAction) error {
+	enc := s.Encounter
+	monster := s.CurrentMonster()
+	stats := s.PlayerStats()
+
+	switch action.Kind {
+	case CombatAttack:
+		damage := s.rollDamage(stats.Attack, monster.Defense)
+		crit := s.rng.IntN(100) < baseCritChance+s.Equipment.Stats()["crit"]
+		if crit {
+			damage *= 2
+		}
+		enc.MonsterHP = max(0, enc.MonsterHP-damage)
+		if crit {
+			enc.say("You land a critical hit on the %s for %d!", monster.Name, damage)
+		} else {
+			enc.say("You hit the %s for %d.", monster.Name, damage)
+		}
+		if enc.MonsterHP == 0 {
+			s.winEncounter()
+		}
+
+	case CombatDefend:
+		enc.say("You raise your guard.")
+
+	case CombatUseItem:
+		def, _ := s.Catalog.Item(action.ItemID)
+		if err := s.consume(def, SourceCombat); err != nil {
+			return err
+		}
+		enc.say("You use %s.", describeEffects(def))
+
+	case CombatFlee:
+		chance := max(10, min(90, 50+5*(s.Player.Attributes.Agility-monster.Level)))
+		if s.rng.IntN(100) < chance {
+			enc.Outcome = EncounterFled
+			enc.say("You escape from the %s.", monster.Name)
+			s.Log("Combat", "Fled from "+monster.Name, "")
+		} else {
+			enc.say("You fail to escape!")
+		}
+	}
+	return nil
+}
+
+func (s *State) monsterTurn(defending bool) {
+	enc := s.Encounter
+	monster := s.CurrentMonster()
+	stats := s.PlayerStats()
+
+	damage := s.rollDamage(monster.Attack, stats.Defense)
+	if defending {
+		damage = max(1, damage/2)
+	}
+	s.Player.HP = max(0, s.Player.HP-damage)
+	enc.say("The %s hits you for %d.", monster.Name, damage)
+
+	if s.Player.HP == 0 {
+		s.loseEncounter()
+	}
+}
+
+// rollDamage returns attack varied by ±20%, reduced by half the defense,
+// and never less than 1
+func (s *State) rollDamage(attack, defense int) int {
+	roll := attack * (80 + s.rng.IntN(41)) / 100
+	return max(1, roll-defense/2)
+}
+
+// winEncounter awards XP, gold and loot for the monster
+func (s *State) winEncounter() {
+	enc := s.Encounter
+	monster := s.CurrentMonster()
+	enc.Outcome = EncounterWon
+
+	gold := monster.Gold[0] + s.rng.IntN(monster.Gold[1]-monster.Gold[0]+1)
+	s.Player.Gold += gold
+
+	var gains []string
+	var loot []ItemAmount
+	for _, drop := range monster.Loot {
+		if s.rng.Float64() >= drop.Chance {
+			continue
+		}
+		qty := drop.Min + s.rng.IntN(drop.Max-drop.Min+1)
+		if _, err := s.Storage.Add(drop.ItemID, qty); err != nil {
+			gains = append(gains, fmt.Sprintf("no room for %d %s", qty, s.Catalog.ItemName(drop.ItemID)))
+			continue
+		}
+		gains = append(gains, fmt.Sprintf("+%d %s", qty, s.Catalog.ItemName(drop.ItemID)))
+		loot = append(loot, ItemAmount{Item: drop.ItemID, Quantity: qty})
+	}
+	if gold > 0 {
+		gains = append(gains, fmt.Sprintf("+%d gold", gold))
+	}
+	gains = append(gains, fmt.Sprintf("+%d XP", monster.XP))
+
+	enc.say("The %s is defeated!", monster.Name)
+	s.Log("Combat", monster.Name+" defeated", strings.Join(gains, ", "))
+
+	if level, up := s.addCombatXP(monster.XP); up {
+		enc.say("You reached level %d!", level)
+	}
+	for _, item := range loot {
+		s.Events.Publish(ItemAdded{Item: item.Item, Quantity: item.Quantity, Source: SourceLoot})
+	}
+	s.Events.Publish(MonsterDefeated{Monster: monster.ID})
+}
+
+// addCombatXP awards combat XP. A level-up fully heals the player.
+func (s *State) addCombatXP(xp int) (int, bool) {
+	before := s.Player.Level()
+	s.Player.XP += xp
+	s.Events.Publish(XPGained{Amount: xp})
+	level := s.Player.Level()
+	if level == before {
+		return level, false
+	}
+	stats := s.PlayerStats()
+	s.Player.HP, s.Player.MP = stats.MaxHP, stats.MaxMP
+	s.Events.Publish(LevelUp{Level: level})
+	return level, true
+}
+
+// loseEncounter applies the death penalty: the player loses some gold and
+// respawns at the start location with half health
+func (s *State) loseEncounter() {
+	enc := s.Encounter
+	monster := s.CurrentMonster()
+	enc.Outcome = EncounterLost
+
+	lost := s.Player.Gold * deathGoldPenalty / 100
+	s.Player.Gold -= lost
+	stats := s.PlayerStats()
+	s.Player.HP = max(1, stats.MaxHP/2)
+	// Queued travel and gathering no longer make sense from the respawn point
+	s.Actions = nil
+	s.setLocation(s.Catalog.StartLocation())
+
+	enc.say("You were defeated by the %s.", monster.Name)
+	s.Log("Combat", "Defeated by "+monster.Name,
+		fmt.Sprintf("Respawned in %s, lost %d gold", s.CurrentLocation().Name, lost))
+}
+
+// recover regenerates HP and MP outside combat
+func (s *State) recover(elapsed time.Duration) {
+	if s.InCombat() {
+		s.recovery = 0
+		return
+	}
+	s.recovery += elapsed
+	points := int(s.recovery / regenInterval)
+	if points == 0 {
+		return
+	}
+	s.recovery -= time.Duration(points) * regenInterval
+
+	stats := s.PlayerStats()
+	s.Player.HP = min(stats.MaxHP, s.Player.HP+points)
+	s.Player.MP = min(stats.MaxMP, s.Player.MP+points)
+}
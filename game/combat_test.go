@@ -0,0 +1,32 @@
+package game
+
+import "testing"
+
+func TestCombatPausesActionQueue(t *testing.T) {
+	s := newTestState(t)
+	if err := s.TravelTo("oakwood"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.StartEncounter("giant_rat"); err != nil {
+		t.Fatal(err)
+	}
+
+	trip := s.ActionDuration(s.Actions[0])
+	for range trip/TickInterval + 1 {
+		s.Tick(TickInterval)
+	}
+	if s.Location != "starting_town" {
+		t.Fatalf("arrived in %s during the fight", s.Location)
+	}
+	if s.CurrentMonster().ID != "giant_rat" || !s.InCombat() {
+		t.Fatal("the fight ended")
+	}
+
+	s.Encounter.Outcome = EncounterWon
+	for range trip/TickInterval + 1 {
+		s.Tick(TickInterval)
+	}
+	if s.Location != "oakwood" {
+		t.Errorf("at %s after the fight, want oakwood", s.Location)
+	}
+}
@@ -0,0 +1,121 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ItemAmount is a quantity of one item in a recipe
+type ItemAmount struct {
+	Item     string `json:"item"`
+	Quantity int    `json:"quantity"`
+}
+
+// Recipe turns input items into output items. Recipes are loaded from
+// content files.
+type Recipe struct {
+	ID      string       `json:"id"`
+	Name    string       `json:"name,omitempty"` // Defaults to the first output's name
+	Skill   SkillType    `json:"skill"`
+	Level   int          `json:"level"` // Minimum skill level
+	XP      int          `json:"xp"`    // XP per craft
+	Seconds float64      `json:"seconds"`
+	Tool    string       `json:"tool,omitempty"` // Tag of a tool that must be in storage or worn
+	Inputs  []ItemAmount `json:"inputs"`
+	Outputs []ItemAmount `json:"outputs"`
+}
+
+// Duration returns the recipe's base crafting time
+func (r *Recipe) Duration() time.Duration {
+	return time.Duration(r.Seconds * float64(time.Second))
+}
+
+// CanCraft reports why a recipe cannot be crafted right now, if it cannot
+func (s *State) CanCraft(recipe *Recipe) error {
+	if level := s.SkillLevel(recipe.Skill); level < recipe.Level {
+		return fmt.Errorf("%s requires %s level %d (you are %d)", recipe.Name, recipe.Skill, recipe.Level, level)
+	}
+	if recipe.Tool != "" && !s.HasTool(recipe.Tool) {
+		return fmt.Errorf("%s requires a %s", recipe.Name, recipe.Tool)
+	}
+	var missing []string
+	for _, input := range recipe.Inputs {
+		if have := s.Storage.Quantity(input.Item); have < input.Quantity {
+			missing = append(missing, fmt.Sprintf("%d %s", input.Quantity-have, s.Catalog.ItemName(input.Item)))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("need %s more", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// MaxCrafts returns how many times a recipe can be crafted from the
+// inputs in storage
+func (s *State) MaxCrafts(recipe *Recipe) int {
+	most := -1
+	for _, input := range recipe.Inputs {
+		n := s.Storage.Quantity(input.Item) / input.Quantity
+		if most < 0 || n < most {
+			most = n
+		}
+	}
+	return max(0, most)
+}
+
+// HasTool reports whether a tool with the tag is in storage or worn
+func (s *State) HasTool(tag string) bool {
+	if len(s.Storage.GetByTag(tag)) > 0 {
+		return true
+	}
+	for _, item := range s.Equipment.Items() {
+		if hasAnyTag(item, []string{tag}) {
+			return true
+		}
+	}
+	return false
+}
+
+// CraftDuration returns how long one craft takes. Each level above the
+// requirement speeds crafting up.
+func (s *State) CraftDuration(recipe *Recipe) time.Duration {
+	levelsAbove := max(0, s.SkillLevel(recipe.Skill)-recipe.Level)
+	speed := max(10, 100+2*levelsAbove+s.BuffBonus(skillStat(recipe.Skill)))
+	return recipe.Duration() * 100 / time.Duration(speed)
+}
+
+// Craft performs one craft. Inputs are only consumed when every input is
+// available, so a failed craft leaves storage unchanged.
+func (s *State) Craft(recipeID string) error {
+	recipe, ok := s.Catalog.Recipe(recipeID)
+	if !ok {
+		return fmt.Errorf("unknown recipe %q", recipeID)
+	}
+	if err := s.CanCraft(recipe); err != nil {
+		return err
+	}
+
+	if err := s.Storage.Exchange(recipe.Inputs, recipe.Outputs); err != nil {
+		return err
+	}
+	var made []string
+	for _, output := range recipe.Outputs {
+		made = append(made, fmt.Sprintf("+%d %s", output.Quantity, s.Catalog.ItemName(output.Item)))
+	}
+	s.AddXP(recipe.Skill, recipe.XP)
+
+	var used []string
+	for _, input := range recipe.Inputs {
+		used = append(used, fmt.Sprintf("-%d %s", input.Quantity, s.Catalog.ItemName(input.Item)))
+	}
+	s.Log(string(recipe.Skill), strings.Join(made, ", "),
+		fmt.Sprintf("(%s) +%d XP", strings.Join(used, ", "), recipe.XP))
+	for _, input := range recipe.Inputs {
+		s.Events.Publish(ItemRemoved{Item: input.Item, Quantity: input.Quantity, Source: SourceCraft})
+	}
+	for _, output := range recipe.Outputs {
+		s.Events.Publish(ItemAdded{Item: output.Item, Quantity: output.Quantity, Source: SourceCraft})
+	}
+	return nil
+}
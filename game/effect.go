@@ -0,0 +1,211 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// EffectKind is what using a consumable does
+type EffectKind string
+
+const (
+	EffectHeal    EffectKind = "heal"    // Restores Amount HP
+	EffectRestore EffectKind = "restore" // Restores Amount MP
+	EffectBuff    EffectKind = "buff"    // Raises Stat by Amount for Seconds
+)
+
+// Buffable stats besides skills. A skill's lowercase name buffs its
+// speed by Amount percent, like a tool bonus.
+const (
+	StatAttack  = "atk"
+	StatDefense = "def"
+)
+
+// Effect is one thing an item does when used. Effects are part of item
+// definitions in content files.
+type Effect struct {
+	Kind    EffectKind `json:"kind"`
+	Stat    string     `json:"stat,omitempty"` // Buffs only
+	Amount  int        `json:"amount"`
+	Seconds float64    `json:"seconds,omitempty"` // Buff duration
+}
+
+// Duration returns how long a buff lasts
+func (e Effect) Duration() time.Duration {
+	return time.Duration(e.Seconds * float64(time.Second))
+}
+
+// Buff is a timed bonus from a used item
+type Buff struct {
+	Item      string        `json:"item"` // Item that gave the buff
+	Stat      string        `json:"stat"`
+	Amount    int           `json:"amount"`
+	Remaining time.Duration `json:"remaining"`
+}
+
+// checkEffect reports what is wrong with an effect definition, if anything
+func checkEffect(e Effect) error {
+	switch e.Kind {
+	case EffectHeal, EffectRestore:
+		if e.Amount < 1 {
+			return fmt.Errorf("%s amount must be positive", e.Kind)
+		}
+	case EffectBuff:
+		if !buffable(e.Stat) {
+			return fmt.Errorf("cannot buff %q", e.Stat)
+		}
+		if e.Amount == 0 {
+			return fmt.Errorf("buff to %s has no amount", e.Stat)
+		}
+		if e.Seconds <= 0 {
+			return fmt.Errorf("buff to %s needs a positive duration", e.Stat)
+		}
+	default:
+		return fmt.Errorf("unknown effect kind %q", e.Kind)
+	}
+	return nil
+}
+
+// buffable reports whether a stat can be buffed
+func buffable(stat string) bool {
+	if stat == StatAttack || stat == StatDefense {
+		return true
+	}
+	return slices.ContainsFunc(AllSkills, func(skill SkillType) bool {
+		return skillStat(skill) == stat
+	})
+}
+
+// skillStat returns the stat name that buffs a skill's speed
+func skillStat(skill SkillType) string {
+	return strings.ToLower(string(skill))
+}
+
+// statEffects returns heal and restore effects for the HP and MP in stats
+func statEffects(stats map[string]int) []Effect {
+	var effects []Effect
+	if stats["hp"] > 0 {
+		effects = append(effects, Effect{Kind: EffectHeal, Amount: stats["hp"]})
+	}
+	if stats["mp"] > 0 {
+		effects = append(effects, Effect{Kind: EffectRestore, Amount: stats["mp"]})
+	}
+	return effects
+}
+
+// DescribeEffect renders an effect like "+8 HP" or "+5 ATK for 1m 00s"
+func DescribeEffect(e Effect) string {
+	switch e.Kind {
+	case EffectHeal:
+		return fmt.Sprintf("+%d HP", e.Amount)
+	case EffectRestore:
+		return fmt.Sprintf("+%d MP", e.Amount)
+	case EffectBuff:
+		if e.Stat == StatAttack || e.Stat == StatDefense {
+			return fmt.Sprintf("%+d %s for %s", e.Amount, strings.ToUpper(e.Stat), FormatDuration(e.Duration()))
+		}
+		return fmt.Sprintf("%+d%% %s speed for %s", e.Amount, e.Stat, FormatDuration(e.Duration()))
+	}
+	return string(e.Kind)
+}
+
+// describeEffects renders a used item like "Bread (+8 HP)"
+func describeEffects(def *ItemDef) string {
+	parts := make([]string, len(def.Effects))
+	for i, e := range def.Effects {
+		parts[i] = DescribeEffect(e)
+	}
+	return fmt.Sprintf("%s (%s)", def.Name, strings.Join(parts, ", "))
+}
+
+// cooldownGroup returns the key an item's cooldown is tracked under
+func cooldownGroup(def *ItemDef) string {
+	if def.CooldownGroup != "" {
+		return def.CooldownGroup
+	}
+	return def.ID
+}
+
+// Cooldown returns how long until an item can be used again
+func (s *State) Cooldown(id string) time.Duration {
+	def, ok := s.Catalog.Item(id)
+	if !ok {
+		return 0
+	}
+	return s.Cooldowns[cooldownGroup(def)]
+}
+
+// BuffBonus returns the total of the active buffs to a stat
+func (s *State) BuffBonus(stat string) int {
+	total := 0
+	for _, buff := range s.Buffs {
+		if buff.Stat == stat {
+			total += buff.Amount
+		}
+	}
+	return total
+}
+
+// applyEffects gives the player an item's effects and starts its
+// cooldown. Using an item again refreshes its buffs rather than stacking
+// them.
+func (s *State) applyEffects(def *ItemDef) {
+	for _, e := range def.Effects {
+		switch e.Kind {
+		case EffectHeal:
+			s.Player.HP += e.Amount
+		case EffectRestore:
+			s.Player.MP += e.Amount
+		case EffectBuff:
+			s.Buffs = slices.DeleteFunc(s.Buffs, func(b Buff) bool {
+				return b.Item == def.ID && b.Stat == e.Stat
+			})
+			s.Buffs = append(s.Buffs, Buff{Item: def.ID, Stat: e.Stat, Amount: e.Amount, Remaining: e.Duration()})
+		}
+	}
+	if def.Cooldown > 0 {
+		s.Cooldowns[cooldownGroup(def)] = time.Duration(def.Cooldown * float64(time.Second))
+	}
+	s.clampVitals()
+}
+
+// consume uses up one of an item for its effects
+func (s *State) consume(def *ItemDef, source ItemSource) error {
+	if err := s.Storage.Remove(def.ID, 1); err != nil {
+		return err
+	}
+	s.applyEffects(def)
+	s.Events.Publish(ItemRemoved{Item: def.ID, Quantity: 1, Source: source})
+	return nil
+}
+
+// stepEffects counts down buffs and cooldowns. An item whose buffs run
+// out together is reported once.
+func (s *State) stepEffects(dt time.Duration) {
+	active := s.Buffs[:0]
+	var expired []string
+	for _, buff := range s.Buffs {
+		if buff.Remaining <= dt {
+			if !slices.Contains(expired, buff.Item) {
+				expired = append(expired, buff.Item)
+			}
+			continue
+		}
+		buff.Remaining -= dt
+		active = append(active, buff)
+	}
+	s.Buffs = active
+	for _, id := range expired {
+		s.Log("Character", s.Catalog.ItemName(id)+" wore off", "")
+	}
+
+	for group, left := range s.Cooldowns {
+		if left <= dt {
+			delete(s.Cooldowns, group)
+		} else {
+			s.Cooldowns[group] = left - dt
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package game
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStepEffectsReportsEachItemOnce(t *testing.T) {
+	s := newTestState(t)
+	s.ActivityLog.Clear()
+	s.Buffs = []Buff{
+		{Item: "potion_strength", Stat: StatAttack, Amount: 5, Remaining: time.Second},
+		{Item: "potion_strength", Stat: StatDefense, Amount: 5, Remaining: time.Second},
+		{Item: "potion_lumber", Stat: "woodcutting", Amount: 25, Remaining: time.Second},
+		{Item: "potion_lumber", Stat: "mining", Amount: 25, Remaining: time.Minute},
+	}
+	s.stepEffects(time.Second)
+
+	var got []string
+	for _, entry := range s.ActivityLog.GetEntries() {
+		if strings.HasSuffix(entry.Action, " wore off") {
+			got = append(got, entry.Action)
+		}
+	}
+	want := []string{"Strength Elixir wore off", "Lumberjack's Brew wore off"}
+	if !slices.Equal(got, want) {
+		t.Errorf("logged %q, want %q", got, want)
+	}
+	if len(s.Buffs) != 1 {
+		t.Errorf("%d buffs left, want 1", len(s.Buffs))
+	}
+}
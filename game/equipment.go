@@ -0,0 +1,226 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// EquipSlot identifies a place on the player where gear is worn
+type EquipSlot string
+
+// Equipment slots. The values match the "slot" field in items.json.
+const (
+	SlotMainHand EquipSlot = "main_hand"
+	SlotOffHand  EquipSlot = "off_hand"
+	SlotHead     EquipSlot = "head"
+	SlotBody     EquipSlot = "body"
+	SlotToolBelt EquipSlot = "tool"
+)
+
+// SlotInfo describes an equipment slot
+type SlotInfo struct {
+	Slot     EquipSlot
+	Name     string
+	Capacity int // Items the slot holds at once
+}
+
+// EquipmentSlots lists every slot in display order. The tool belt holds one
+// tool per gathering skill.
+var EquipmentSlots = []SlotInfo{
+	{Slot: SlotMainHand, Name: "Main Hand", Capacity: 1},
+	{Slot: SlotOffHand, Name: "Off Hand", Capacity: 1},
+	{Slot: SlotHead, Name: "Head", Capacity: 1},
+	{Slot: SlotBody, Name: "Body", Capacity: 1},
+	{Slot: SlotToolBelt, Name: "Tool Belt", Capacity: len(GatheringSkills)},
+}
+
+// FindSlot looks up a slot by ID or display name, ignoring case.
+// "tool_belt" and "tool belt" both find the tool belt.
+func FindSlot(name string) (SlotInfo, bool) {
+	key := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "_")
+	for _, info := range EquipmentSlots {
+		if key == string(info.Slot) || key == strings.ReplaceAll(strings.ToLower(info.Name), " ", "_") {
+			return info, true
+		}
+	}
+	return SlotInfo{}, false
+}
+
+// isSlotID reports whether s is the ID of an equipment slot
+func isSlotID(s string) bool {
+	for _, info := range EquipmentSlots {
+		if s == string(info.Slot) {
+			return true
+		}
+	}
+	return false
+}
+
+// Equipment holds the gear the player is wearing. Equipped items are moved
+// out of storage, so each entry is a single item.
+type Equipment struct {
+	slots map[EquipSlot][]*ItemDef
+}
+
+// NewEquipment creates an empty set of equipment slots
+func NewEquipment() *Equipment {
+	return &Equipment{
+		slots: make(map[EquipSlot][]*ItemDef),
+	}
+}
+
+// Slot returns the items worn in a slot
+func (e *Equipment) Slot(slot EquipSlot) []*ItemDef {
+	return e.slots[slot]
+}
+
+// Items returns every worn item in slot order, flagged as equipped
+func (e *Equipment) Items() []Item {
+	var items []Item
+	for _, info := range EquipmentSlots {
+		for _, def := range e.slots[info.Slot] {
+			items = append(items, Item{ItemDef: def, Quantity: 1, Equipped: true})
+		}
+	}
+	return items
+}
+
+// Stats returns the sum of every worn item's stat modifiers
+func (e *Equipment) Stats() map[string]int {
+	stats := make(map[string]int)
+	for _, items := range e.slots {
+		for _, def := range items {
+			for stat, value := range def.Stats {
+				stats[stat] += value
+			}
+		}
+	}
+	return stats
+}
+
+// canEquip reports why an item cannot be worn, if it cannot
+func canEquip(def *ItemDef) error {
+	if def.Slot == "" || !hasAnyTag(Item{ItemDef: def}, []string{"equipment"}) {
+		return fmt.Errorf("%s cannot be equipped", def.Name)
+	}
+	if !isSlotID(def.Slot) {
+		return fmt.Errorf("%s has unknown equipment slot %q", def.Name, def.Slot)
+	}
+	return nil
+}
+
+// put wears an item and returns the items it replaced. A tool replaces the
+// tool of the same kind; otherwise a full slot gives up its oldest item.
+func (e *Equipment) put(def *ItemDef) (replaced []*ItemDef) {
+	info, _ := FindSlot(def.Slot)
+	worn := e.slots[info.Slot]
+
+	kept := worn[:0:0]
+	for _, other := range worn {
+		if info.Slot == SlotToolBelt && sameToolKind(def, other) {
+			replaced = append(replaced, other)
+			continue
+		}
+		kept = append(kept, other)
+	}
+	for len(kept) >= info.Capacity {
+		replaced = append(replaced, kept[0])
+		kept = kept[1:]
+	}
+
+	e.slots[info.Slot] = append(kept, def)
+	return replaced
+}
+
+// remove takes one worn item with the given ID off
+func (e *Equipment) remove(id string) (*ItemDef, bool) {
+	for slot, items := range e.slots {
+		for i, def := range items {
+			if def.ID == id {
+				e.slots[slot] = append(items[:i:i], items[i+1:]...)
+				return def, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// sameToolKind reports whether two tools serve the same gathering skill
+func sameToolKind(a, b *ItemDef) bool {
+	for _, tag := range skillTools {
+		if hasAnyTag(Item{ItemDef: a}, []string{tag}) && hasAnyTag(Item{ItemDef: b}, []string{tag}) {
+			return true
+		}
+	}
+	return false
+}
+
+// EquipItem moves one of the item with the given ID from storage into its
+// equipment slot. Anything it replaces goes back to storage.
+func (s *State) EquipItem(id string) error {
+	item, ok := s.Storage.Find(id)
+	if !ok {
+		return fmt.Errorf("no item with id %q in storage", id)
+	}
+	if err := canEquip(item.ItemDef); err != nil {
+		return err
+	}
+
+	// Swap the item for whatever it replaces, putting the slot back as it
+	// was if the replaced gear does not fit in storage
+	def := item.ItemDef
+	info, _ := FindSlot(def.Slot)
+	worn := slices.Clone(s.Equipment.slots[info.Slot])
+	replaced := s.Equipment.put(def)
+	returned := make([]ItemAmount, len(replaced))
+	for i, old := range replaced {
+		returned[i] = ItemAmount{Item: old.ID, Quantity: 1}
+	}
+	if err := s.Storage.Exchange([]ItemAmount{{Item: id, Quantity: 1}}, returned); err != nil {
+		s.Equipment.slots[info.Slot] = worn
+		return err
+	}
+
+	s.Events.Publish(ItemRemoved{Item: id, Quantity: 1, Source: SourceEquipment})
+	for _, old := range replaced {
+		s.Log("Equipment", "Unequipped "+old.Name, info.Name)
+		s.Events.Publish(ItemAdded{Item: old.ID, Quantity: 1, Source: SourceEquipment})
+	}
+	s.Log("Equipment", "Equipped "+def.Name, info.Name)
+	s.clampVitals()
+	return nil
+}
+
+// UnequipItem returns worn gear to storage. ref is an item ID or a slot
+// name; naming a slot empties it.
+func (s *State) UnequipItem(ref string) error {
+	var defs []*ItemDef
+	if info, ok := FindSlot(ref); ok {
+		defs = append(defs, s.Equipment.Slot(info.Slot)...)
+		if len(defs) == 0 {
+			return fmt.Errorf("nothing is equipped in %s", info.Name)
+		}
+	} else {
+		def, ok := s.Catalog.Item(ref)
+		if !ok {
+			return fmt.Errorf("unknown item or slot %q", ref)
+		}
+		defs = append(defs, def)
+	}
+
+	for _, def := range defs {
+		if _, ok := s.Equipment.remove(def.ID); !ok {
+			return fmt.Errorf("%s is not equipped", def.Name)
+		}
+		if _, err := s.Storage.Add(def.ID, 1); err != nil {
+			// Keep wearing what does not fit in storage
+			s.Equipment.put(def)
+			return err
+		}
+		s.Log("Equipment", "Unequipped "+def.Name, "")
+		s.Events.Publish(ItemAdded{Item: def.ID, Quantity: 1, Source: SourceEquipment})
+	}
+	s.clampVitals()
+	return nil
+}
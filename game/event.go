@@ -0,0 +1,165 @@
+package game
+
+// Event is something that happened in the game. Game logic publishes events
+// to the state's bus, and systems such as the activity log, quests and
+// achievements subscribe to the ones they care about instead of calling
+// each other.
+type Event interface {
+	event()
+}
+
+// ItemSource is how items entered or left storage
+type ItemSource string
+
+const (
+	SourceGather    ItemSource = "gather"
+	SourceCraft     ItemSource = "craft"
+	SourceProcess   ItemSource = "process"
+	SourceLoot      ItemSource = "loot"
+	SourceMarket    ItemSource = "market"
+	SourceReward    ItemSource = "reward"
+	SourceEquipment ItemSource = "equipment"
+	SourceCombat    ItemSource = "combat" // Items used during a fight
+	SourceQuest     ItemSource = "quest"  // Items delivered for a quest
+	SourceUse       ItemSource = "use"    // Items used outside a fight
+	SourceDrop      ItemSource = "drop"
+)
+
+// Obtained reports whether items from the source are newly obtained, as
+// opposed to bought, moved or handed back. Only obtained items count toward
+// gather objectives.
+func (src ItemSource) Obtained() bool {
+	switch src {
+	case SourceGather, SourceCraft, SourceProcess, SourceLoot:
+		return true
+	}
+	return false
+}
+
+// Message is a line for the activity log
+type Message struct {
+	Category string
+	Action   string
+	Details  string
+}
+
+// ItemAdded is published when items are put into storage
+type ItemAdded struct {
+	Item     string
+	Quantity int
+	Source   ItemSource
+}
+
+// ItemRemoved is published when items are taken out of storage
+type ItemRemoved struct {
+	Item     string
+	Quantity int
+	Source   ItemSource
+}
+
+// StacksChanged is published when an item's stacks are split or merged.
+// Quantities are unchanged.
+type StacksChanged struct {
+	Item string
+}
+
+// XPGained is published for every XP award. Skill is empty for combat XP.
+type XPGained struct {
+	Skill  SkillType
+	Amount int
+}
+
+// LevelUp is published when a skill or the combat level goes up. Skill is
+// empty for the combat level.
+type LevelUp struct {
+	Skill SkillType
+	Level int
+}
+
+// LocationChanged is published when the player arrives somewhere, by travel
+// or by respawning
+type LocationChanged struct {
+	From string
+	To   string
+}
+
+// MonsterDefeated is published when the player wins a fight
+type MonsterDefeated struct {
+	Monster string
+}
+
+// QuestProgressed is published when an active quest's current objective
+// advances. Step is the objective the progress counts toward.
+type QuestProgressed struct {
+	Quest string
+	Step  int
+	Count int
+}
+
+// QuestFinished is published when a quest's last objective is done
+type QuestFinished struct {
+	Quest string
+}
+
+// AchievementUnlocked is published when an achievement is earned
+type AchievementUnlocked struct {
+	Achievement string
+}
+
+func (Message) event()             {}
+func (ItemAdded) event()           {}
+func (ItemRemoved) event()         {}
+func (StacksChanged) event()       {}
+func (XPGained) event()            {}
+func (LevelUp) event()             {}
+func (LocationChanged) event()     {}
+func (MonsterDefeated) event()     {}
+func (QuestProgressed) event()     {}
+func (QuestFinished) event()       {}
+func (AchievementUnlocked) event() {}
+
+// Bus delivers events to subscribers in the order they subscribed. Events
+// published while another is being delivered are queued behind it, so every
+// subscriber sees events in the order they were published.
+type Bus struct {
+	handlers   []func(Event)
+	queue      []Event
+	delivering bool
+}
+
+// NewBus creates a bus with no subscribers
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers a handler for every event
+func (b *Bus) Subscribe(handler func(Event)) {
+	b.handlers = append(b.handlers, handler)
+}
+
+// On registers a handler for one type of event
+func On[E Event](b *Bus, handler func(E)) {
+	b.Subscribe(func(e Event) {
+		if e, ok := e.(E); ok {
+			handler(e)
+		}
+	})
+}
+
+// Publish delivers an event to every subscriber
+func (b *Bus) Publish(e Event) {
+	b.queue = append(b.queue, e)
+	if b.delivering {
+		return
+	}
+
+	b.delivering = true
+	defer func() { b.delivering = false }()
+	for len(b.queue) > 0 {
+		next := b.queue[0]
+		b.queue = b.queue[1:]
+		for _, handler := range b.handlers {
+			handler(next)
+		}
+	}
+}
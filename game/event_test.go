@@ -0,0 +1,162 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBusQueuesEventsPublishedDuringDelivery(t *testing.T) {
+	bus := NewBus()
+	var seen []string
+	On(bus, func(e Message) {
+		if e.Action == "first" {
+			bus.Publish(Message{Action: "nested"})
+		}
+	})
+	On(bus, func(e Message) { seen = append(seen, e.Action) })
+
+	bus.Publish(Message{Action: "first"})
+	bus.Publish(Message{Action: "second"})
+
+	if want := []string{"first", "nested", "second"}; !slices.Equal(seen, want) {
+		t.Errorf("delivered %v, want %v", seen, want)
+	}
+}
+
+func TestOnFiltersByType(t *testing.T) {
+	bus := NewBus()
+	added := record[ItemAdded](bus)
+	bus.Publish(ItemRemoved{Item: "wood_oak", Quantity: 1})
+	bus.Publish(ItemAdded{Item: "wood_oak", Quantity: 2})
+
+	if len(*added) != 1 || (*added)[0].Quantity != 2 {
+		t.Errorf("got %v, want one ItemAdded of 2", *added)
+	}
+}
+
+func TestItemAddedAdvancesGatherObjectives(t *testing.T) {
+	tests := []struct {
+		source ItemSource
+		want   int
+	}{
+		{SourceGather, 3},
+		{SourceCraft, 3},
+		{SourceProcess, 3},
+		{SourceLoot, 3},
+		{SourceMarket, 0},
+		{SourceReward, 0},
+		{SourceEquipment, 0},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.source), func(t *testing.T) {
+			s := newTestState(t)
+			if err := s.AcceptQuest("timber"); err != nil {
+				t.Fatal(err)
+			}
+			s.Events.Publish(ItemAdded{Item: "wood_oak", Quantity: 3, Source: tt.source})
+
+			if got := s.Quests["timber"].Count; got != tt.want {
+				t.Errorf("quest count = %d, want %d", got, tt.want)
+			}
+			if got := s.AchievementCount("lumberjack"); got != tt.want {
+				t.Errorf("achievement count = %d, want %d", got, tt.want)
+			}
+			if got := s.HasAchievement("first_log"); got != (tt.want > 0) {
+				t.Errorf("first_log unlocked = %v, want %v", got, tt.want > 0)
+			}
+		})
+	}
+}
+
+func TestDeliverQuestPublishesItemRemovedAndFinishes(t *testing.T) {
+	s := newTestState(t)
+	removed := record[ItemRemoved](s.Events)
+	finished := record[QuestFinished](s.Events)
+	if err := s.AcceptQuest("timber"); err != nil {
+		t.Fatal(err)
+	}
+	s.Events.Publish(ItemAdded{Item: "wood_oak", Quantity: 10, Source: SourceGather})
+	before := s.Storage.Quantity("wood_oak")
+
+	if err := s.DeliverQuest("timber"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := ItemRemoved{Item: "wood_oak", Quantity: 10, Source: SourceQuest}
+	if !slices.Contains(*removed, want) {
+		t.Errorf("ItemRemoved events %v, want %v", *removed, want)
+	}
+	if got := s.Storage.Quantity("wood_oak"); got != before-10 {
+		t.Errorf("wood left = %d, want %d", got, before-10)
+	}
+	if len(*finished) != 1 || (*finished)[0].Quest != "timber" {
+		t.Errorf("QuestFinished events %v, want timber", *finished)
+	}
+	if !s.Quests["timber"].Completed {
+		t.Error("timber not completed")
+	}
+}
+
+func TestDropPublishesItemRemoved(t *testing.T) {
+	s := newTestState(t)
+	removed := record[ItemRemoved](s.Events)
+	if err := s.DropItem("wood_oak", 5); err != nil {
+		t.Fatal(err)
+	}
+	want := []ItemRemoved{{Item: "wood_oak", Quantity: 5, Source: SourceDrop}}
+	if !slices.Equal(*removed, want) {
+		t.Errorf("got %v, want %v", *removed, want)
+	}
+}
+
+func TestAddXPPublishesLevelUp(t *testing.T) {
+	s := newTestState(t)
+	gained := record[XPGained](s.Events)
+	levels := record[LevelUp](s.Events)
+
+	s.AddXP(SkillWoodcutting, XPForLevel(2)-1)
+	if len(*levels) != 0 {
+		t.Fatalf("level up below the threshold: %v", *levels)
+	}
+	s.AddXP(SkillWoodcutting, XPForLevel(10))
+
+	if len(*gained) != 2 {
+		t.Errorf("got %d XPGained events, want 2", len(*gained))
+	}
+	want := []LevelUp{{Skill: SkillWoodcutting, Level: s.SkillLevel(SkillWoodcutting)}}
+	if !slices.Equal(*levels, want) {
+		t.Errorf("got %v, want %v", *levels, want)
+	}
+	if !s.HasAchievement("woodsman") {
+		t.Error("woodsman not unlocked by the level up")
+	}
+}
+
+func TestArrivePublishesLocationChanged(t *testing.T) {
+	s := newTestState(t)
+	changed := record[LocationChanged](s.Events)
+	start := s.Location
+
+	if err := s.arrive("oakwood"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []LocationChanged{{From: start, To: "oakwood"}}
+	if !slices.Equal(*changed, want) {
+		t.Errorf("got %v, want %v", *changed, want)
+	}
+	if got := s.AchievementCount("wanderer"); got != 1 {
+		t.Errorf("wanderer count = %d, want 1", got)
+	}
+}
+
+func TestArriveRejectsUnconnectedLocation(t *testing.T) {
+	s := newTestState(t)
+	changed := record[LocationChanged](s.Events)
+	if err := s.arrive("deep_mine"); err == nil {
+		t.Error("arrived at a location with no road from the start")
+	}
+	if len(*changed) != 0 {
+		t.Errorf("published %v for a failed arrival", *changed)
+	}
+}
@@ -0,0 +1,150 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ResourceNode is a place where a gathering skill can be trained
+type ResourceNode struct {
+	ID      string    `json:"id"`
+	Name    string    `json:"name"`
+	Skill   SkillType `json:"skill"`
+	Level   int       `json:"level"` // Minimum skill level
+	ItemID  string    `json:"item"`  // Item deposited per successful action
+	XP      int       `json:"xp"`    // XP per successful action
+	Seconds float64   `json:"seconds"`
+}
+
+// Duration returns the node's time per action before bonuses
+func (n *ResourceNode) Duration() time.Duration {
+	return time.Duration(n.Seconds * float64(time.Second))
+}
+
+// skillTools maps each gathering skill to the tool tag that boosts it
+var skillTools = map[SkillType]string{
+	SkillWoodcutting: "axe",
+	SkillMining:      "pickaxe",
+	SkillFishing:     "rod",
+}
+
+// GatherResult describes the outcome of one gathering action
+type GatherResult struct {
+	Item     *ItemDef
+	Quantity int
+	Total    int // Quantity now held
+	XP       int
+	Level    int // Skill level after the action
+}
+
+// CanGather reports whether the player may use a node where they are
+func (s *State) CanGather(node *ResourceNode) error {
+	return s.canGatherAt(node, s.Location)
+}
+
+// canGatherAt reports whether the player may use a node from a location
+func (s *State) canGatherAt(node *ResourceNode, at string) error {
+	location, ok := s.Catalog.Location(at)
+	if !ok {
+		return fmt.Errorf("unknown location %q", at)
+	}
+	if !slices.Contains(location.Nodes, node.ID) {
+		return fmt.Errorf("there is no %s in %s", node.Name, location.Name)
+	}
+	if level := s.SkillLevel(node.Skill); level < node.Level {
+		return fmt.Errorf("%s requires %s level %d (you are %d)", node.Name, node.Skill, node.Level, level)
+	}
+	return nil
+}
+
+// ToolBonus returns the best bonus from equipped tools for a skill
+func (s *State) ToolBonus(skill SkillType) int {
+	tag := skillTools[skill]
+	best := 0
+	for _, def := range s.Equipment.Slot(SlotToolBelt) {
+		if !hasAnyTag(Item{ItemDef: def}, []string{tag}) {
+			continue
+		}
+		bonus := def.Stats[strings.ToLower(string(skill))]
+		best = max(best, bonus)
+	}
+	return best
+}
+
+// GatherDuration returns how long one action at the node takes. Each tool
+// bonus point, each level above the requirement and each buff percent
+// speeds gathering up.
+func (s *State) GatherDuration(node *ResourceNode) time.Duration {
+	levelsAbove := max(0, s.SkillLevel(node.Skill)-node.Level)
+	speed := max(10, 100+3*s.ToolBonus(node.Skill)+2*levelsAbove+s.BuffBonus(skillStat(node.Skill)))
+	return node.Duration() * 100 / time.Duration(speed)
+}
+
+// Gather performs one gathering action at a node, depositing the resource
+// into storage and awarding XP
+func (s *State) Gather(nodeID string) (GatherResult, error) {
+	node, ok := s.Catalog.ResourceNode(nodeID)
+	if !ok {
+		return GatherResult{}, fmt.Errorf("unknown resource node %q", nodeID)
+	}
+	if err := s.CanGather(node); err != nil {
+		return GatherResult{}, err
+	}
+
+	total, err := s.Storage.Add(node.ItemID, 1)
+	if err != nil {
+		return GatherResult{}, err
+	}
+	level := s.AddXP(node.Skill, node.XP)
+	def, _ := s.Catalog.Item(node.ItemID)
+
+	s.Log(
+		string(node.Skill),
+		"+1 "+def.Name,
+		fmt.Sprintf("(%s total) +%d XP", formatCount(total), node.XP),
+	)
+	s.Events.Publish(ItemAdded{Item: node.ItemID, Quantity: 1, Source: SourceGather})
+
+	return GatherResult{
+		Item:     def,
+		Quantity: 1,
+		Total:    total,
+		XP:       node.XP,
+		Level:    level,
+	}, nil
+}
+
+// ParseStatModifiers reads modifiers like "+25 ATK" or "+10 Woodcutting"
+// from an item description. Keys are lowercased.
+func ParseStatModifiers(description string) map[string]int {
+	mods := make(map[string]int)
+	fields := strings.Fields(description)
+	for i := 0; i+1 < len(fields); i++ {
+		if !strings.HasPrefix(fields[i], "+") && !strings.HasPrefix(fields[i], "-") {
+			continue
+		}
+		n, err := strconv.Atoi(fields[i])
+		if err != nil {
+			continue
+		}
+		stat := strings.ToLower(strings.Trim(fields[i+1], ",.;"))
+		mods[stat] += n
+		i++
+	}
+	return mods
+}
+
+// formatCount renders an integer with thousands separators
+func formatCount(n int) string {
+	s := strconv.Itoa(n)
+	if n < 0 {
+		return "-" + formatCount(-n)
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
@@ -0,0 +1,24 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/jexxer/tbrpg/content"
+)
+
+// newTestState returns a new game on the built-in content
+func newTestState(t *testing.T) *State {
+	t.Helper()
+	catalog, err := LoadCatalog(content.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewState(catalog)
+}
+
+// record collects every event of one type published on a bus
+func record[E Event](bus *Bus) *[]E {
+	var events []E
+	On(bus, func(e E) { events = append(events, e) })
+	return &events
+}
@@ -0,0 +1,108 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// usable reports whether an item does anything when used
+func usable(def *ItemDef) bool {
+	return len(def.Effects) > 0
+}
+
+// restoresOnly reports whether all an item does is restore HP or MP
+func restoresOnly(def *ItemDef) bool {
+	for _, e := range def.Effects {
+		if e.Kind != EffectHeal && e.Kind != EffectRestore {
+			return false
+		}
+	}
+	return true
+}
+
+// CanUse reports why an item cannot be used, if it cannot
+func (s *State) CanUse(id string) error {
+	item, ok := s.Storage.Find(id)
+	if !ok {
+		return fmt.Errorf("no item with id %q in storage", id)
+	}
+	if !usable(item.ItemDef) {
+		return fmt.Errorf("%s cannot be used", item.Name)
+	}
+	if left := s.Cooldown(id); left > 0 {
+		return fmt.Errorf("%s is ready in %s", item.Name, FormatDuration(left))
+	}
+	return nil
+}
+
+// UseItem consumes one of an item for its effects. During a fight, using
+// an item takes the player's turn.
+func (s *State) UseItem(id string) error {
+	if s.InCombat() {
+		return s.CombatTurn(CombatAction{Kind: CombatUseItem, ItemID: id})
+	}
+	if err := s.CanUse(id); err != nil {
+		return err
+	}
+	item, _ := s.Storage.Find(id)
+	stats := s.PlayerStats()
+	if restoresOnly(item.ItemDef) && s.Player.HP == stats.MaxHP && s.Player.MP == stats.MaxMP {
+		return errors.New("already at full HP and MP")
+	}
+
+	if err := s.consume(item.ItemDef, SourceUse); err != nil {
+		return err
+	}
+	s.Log("Character", "Used "+describeEffects(item.ItemDef), "")
+	return nil
+}
+
+// DropItem throws qty of an item away
+func (s *State) DropItem(id string, qty int) error {
+	if err := s.Storage.Remove(id, qty); err != nil {
+		return err
+	}
+	s.Log("Storage", fmt.Sprintf("Dropped %d %s", qty, s.Catalog.ItemName(id)), "")
+	s.Events.Publish(ItemRemoved{Item: id, Quantity: qty, Source: SourceDrop})
+	return nil
+}
+
+// SplitStack moves half of an item's largest stack into a new stack
+func (s *State) SplitStack(id string) error {
+	largest := 0
+	for _, item := range s.Storage.GetItems() {
+		if item.ID == id {
+			largest = max(largest, item.Quantity)
+		}
+	}
+	if largest < 2 {
+		return fmt.Errorf("no stack of %s to split", s.Catalog.ItemName(id))
+	}
+	if err := s.Storage.Split(id, largest/2); err != nil {
+		return err
+	}
+	s.Log("Storage", fmt.Sprintf("Split %d %s into a new stack", largest/2, s.Catalog.ItemName(id)), "")
+	s.Events.Publish(StacksChanged{Item: id})
+	return nil
+}
+
+// MergeStacks combines an item's stacks
+func (s *State) MergeStacks(id string) error {
+	if err := s.Storage.Merge(id); err != nil {
+		return err
+	}
+	s.Log("Storage", "Merged stacks of "+s.Catalog.ItemName(id), "")
+	s.Events.Publish(StacksChanged{Item: id})
+	return nil
+}
+
+// StackCount returns how many stacks of an item storage holds
+func (s *State) StackCount(id string) int {
+	n := 0
+	for _, item := range s.Storage.GetItems() {
+		if item.ID == id {
+			n++
+		}
+	}
+	return n
+}
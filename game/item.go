@@ -1,48 +1,47 @@
 // Package game contains core game types and logic
 package game
 
-type Item struct {
-	ID          string
-	Name        string
-	Quantity    int
-	Value       int
-	Tags        []string
-	Category    string
-	Description string
-	Equipped    bool
+// ItemDef is the catalog definition of an item. Definitions are loaded from
+// content files and shared by every stack of the item.
+type ItemDef struct {
+	ID            string         `json:"id"`
+	Name          string         `json:"name"`
+	Value         int            `json:"value"`
+	Tags          []string       `json:"tags"`
+	Category      string         `json:"category"`
+	Description   string         `json:"description,omitempty"`
+	StackLimit    int            `json:"stack_limit,omitempty"`    // 0 means unlimited
+	Weight        int            `json:"weight,omitempty"`         // Per unit, counted against storage capacity
+	Slot          string         `json:"slot,omitempty"`           // Equipment slot, empty if not equippable
+	Stats         map[string]int `json:"stats,omitempty"`          // Lowercase stat name to modifier
+	Effects       []Effect       `json:"effects,omitempty"`        // What using the item does; empty if it cannot be used
+	Cooldown      float64        `json:"cooldown,omitempty"`       // Seconds before the item's cooldown group can be used again
+	CooldownGroup string         `json:"cooldown_group,omitempty"` // Items sharing a group share a cooldown; defaults to the item ID
 }
 
-type ItemCategory struct {
-	Name     string
-	Children []string // Sub-categories
-	Filter   []string // Tags to filter by
+// Item is a stack of an item held by the player. The definition's fields
+// (Name, Value, Tags, ...) are promoted for convenience.
+type Item struct {
+	*ItemDef
+	Quantity int
+	Equipped bool // Set on items reported by Equipment; storage holds none
 }
 
-func GetSampleItems() []Item {
-	return []Item{
-		{ID: "wood_oak", Name: "Oak Wood", Quantity: 150, Value: 5, Tags: []string{"resource", "wood"}, Category: "Resources"},
-		{ID: "ore_iron", Name: "Iron Ore", Quantity: 45, Value: 10, Tags: []string{"resource", "ore"}, Category: "Resources"},
-		{ID: "ore_coal", Name: "Coal", Quantity: 23, Value: 8, Tags: []string{"resource", "ore"}, Category: "Resources"},
-		{ID: "fish_trout", Name: "Raw Trout", Quantity: 12, Value: 15, Tags: []string{"resource", "fish"}, Category: "Resources"},
-		{ID: "stone", Name: "Stone", Quantity: 89, Value: 2, Tags: []string{"resource", "stone"}, Category: "Resources"},
+// AllItemsCategory is the implicit root of the category tree
+const AllItemsCategory = "All Items"
 
-		{ID: "sword_steel", Name: "Steel Sword", Quantity: 1, Value: 150, Tags: []string{"equipment", "weapon", "sword"}, Category: "Equipment", Description: "+25 ATK"},
-		{ID: "sword_iron", Name: "Iron Sword", Quantity: 2, Value: 75, Tags: []string{"equipment", "weapon", "sword"}, Category: "Equipment", Description: "+15 ATK", Equipped: true},
-		{ID: "dagger_iron", Name: "Iron Dagger", Quantity: 3, Value: 50, Tags: []string{"equipment", "weapon", "dagger"}, Category: "Equipment", Description: "+15 ATK"},
-		{ID: "axe_bronze", Name: "Bronze Axe", Quantity: 1, Value: 30, Tags: []string{"equipment", "tool", "axe"}, Category: "Equipment", Description: "+10 Woodcutting"},
-		{ID: "axe_steel", Name: "Steel Axe", Quantity: 1, Value: 100, Tags: []string{"equipment", "tool", "axe"}, Category: "Equipment", Description: "+15 Woodcutting"},
-		{ID: "pickaxe_iron", Name: "Iron Pickaxe", Quantity: 1, Value: 60, Tags: []string{"equipment", "tool", "pickaxe"}, Category: "Equipment", Description: "+8 Mining", Equipped: true},
-
-		{ID: "food_bread", Name: "Bread", Quantity: 15, Value: 5, Tags: []string{"consumable", "food"}, Category: "Consumables"},
-		{ID: "potion_hp", Name: "Health Potion", Quantity: 8, Value: 25, Tags: []string{"consumable", "potion"}, Category: "Consumables"},
-	}
+// ItemCategory is a node in the storage category tree. An item belongs to a
+// category when it has every tag of the category and of its ancestors.
+type ItemCategory struct {
+	Name     string         `json:"name"`
+	Tags     []string       `json:"tags"`
+	Children []ItemCategory `json:"children,omitempty"`
 }
 
-func GetCategories() []ItemCategory {
-	return []ItemCategory{
-		{Name: "All Items", Children: nil, Filter: []string{}},
-		{Name: "Resources", Children: []string{"Ores", "Woods", "Fish"}, Filter: []string{"resource"}},
-		{Name: "Equipment", Children: []string{"Weapons", "Tools", "Armor"}, Filter: []string{"equipment"}},
-		{Name: "Consumables", Children: []string{"Food", "Potions"}, Filter: []string{"consumable"}},
-	}
+// itemRecord is the persisted form of an Item; the definition is
+// referenced by ID
+type itemRecord struct {
+	ID       string `json:"id"`
+	Quantity int    `json:"quantity"`
+	Equipped bool   `json:"equipped,omitempty"` // Version 2 saves only
 }
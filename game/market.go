@@ -0,0 +1,270 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxTransactions caps the trade history kept in saves
+const maxTransactions = 100
+
+// Shop sells a limited stock and buys items by tag. Shops are loaded from
+// content files and placed at locations by the world map.
+type Shop struct {
+	ID      string       `json:"id"`
+	Name    string       `json:"name"`
+	Stock   []ItemAmount `json:"stock,omitempty"` // Items for sale and the full stock of each
+	Buys    []string     `json:"buys,omitempty"`  // Tags the shop buys; empty buys anything
+	Markup  int          `json:"markup"`          // Percent over value charged to the player
+	Payout  int          `json:"payout"`          // Percent of value paid to the player
+	Restock float64      `json:"restock"`         // Seconds to restock one unit of each item
+}
+
+// RestockInterval returns how often each sold-out unit comes back
+func (sh *Shop) RestockInterval() time.Duration {
+	return time.Duration(sh.Restock * float64(time.Second))
+}
+
+// TradeKind is the direction of a transaction
+type TradeKind string
+
+const (
+	TradeBuy  TradeKind = "buy"
+	TradeSell TradeKind = "sell"
+)
+
+// Transaction is one completed trade
+type Transaction struct {
+	Timestamp time.Time `json:"timestamp"`
+	Kind      TradeKind `json:"kind"`
+	Shop      string    `json:"shop"`
+	Item      string    `json:"item"`
+	Quantity  int       `json:"quantity"`
+	Gold      int       `json:"gold"` // Total paid or received
+}
+
+// Market tracks shop stock and the player's trade history
+type Market struct {
+	Sold    map[string]map[string]int `json:"sold,omitempty"`    // Units missing from each shop's full stock, by shop and item
+	History []Transaction             `json:"history,omitempty"` // Oldest first
+
+	restock map[string]time.Duration // Time toward each shop's next restock
+}
+
+// NewMarket creates a market with every shop fully stocked
+func NewMarket() *Market {
+	return &Market{
+		Sold:    make(map[string]map[string]int),
+		restock: make(map[string]time.Duration),
+	}
+}
+
+// InStock returns how many of an item a shop has left
+func (m *Market) InStock(shop *Shop, itemID string) int {
+	for _, stock := range shop.Stock {
+		if stock.Item == itemID {
+			return stock.Quantity - m.Sold[shop.ID][itemID]
+		}
+	}
+	return 0
+}
+
+// record appends a transaction, dropping the oldest past the cap
+func (m *Market) record(t Transaction) {
+	m.History = append(m.History, t)
+	if len(m.History) > maxTransactions {
+		m.History = m.History[len(m.History)-maxTransactions:]
+	}
+}
+
+// step restocks shops by one unit per item each interval
+func (m *Market) step(catalog *Catalog, elapsed time.Duration) {
+	for shopID, sold := range m.Sold {
+		shop, ok := catalog.Shop(shopID)
+		if !ok || shop.Restock <= 0 {
+			continue
+		}
+		m.restock[shopID] += elapsed
+		units := int(m.restock[shopID] / shop.RestockInterval())
+		if units == 0 {
+			continue
+		}
+		m.restock[shopID] -= time.Duration(units) * shop.RestockInterval()
+		for itemID, n := range sold {
+			if n <= units {
+				delete(sold, itemID)
+			} else {
+				sold[itemID] = n - units
+			}
+		}
+		if len(sold) == 0 {
+			delete(m.Sold, shopID)
+			delete(m.restock, shopID)
+		}
+	}
+}
+
+// BuyPrice returns what a shop charges for one unit of an item
+func BuyPrice(shop *Shop, def *ItemDef) int {
+	return max(1, (def.Value*(100+shop.Markup)+99)/100)
+}
+
+// SellPrice returns what a shop pays for one unit of an item. Worthless
+// items fetch nothing.
+func SellPrice(shop *Shop, def *ItemDef) int {
+	return def.Value * shop.Payout / 100
+}
+
+// WillBuy reports whether a shop will buy an item
+func (sh *Shop) WillBuy(def *ItemDef) bool {
+	return len(sh.Buys) == 0 || hasAnyTag(Item{ItemDef: def}, sh.Buys)
+}
+
+// ShopsHere returns the shops at the player's location
+func (s *State) ShopsHere() []*Shop {
+	var shops []*Shop
+	for _, id := range s.CurrentLocation().Shops {
+		if shop, ok := s.Catalog.Shop(id); ok {
+			shops = append(shops, shop)
+		}
+	}
+	return shops
+}
+
+// shopHere returns a shop if it is at the player's location
+func (s *State) shopHere(shopID string) (*Shop, error) {
+	shop, ok := s.Catalog.Shop(shopID)
+	if !ok {
+		return nil, fmt.Errorf("unknown shop %q", shopID)
+	}
+	if !slices.Contains(s.CurrentLocation().Shops, shop.ID) {
+		return nil, fmt.Errorf("there is no %s in %s", shop.Name, s.CurrentLocation().Name)
+	}
+	return shop, nil
+}
+
+// Buy purchases items from a shop at the player's location
+func (s *State) Buy(shopID, itemID string, qty int) error {
+	shop, err := s.shopHere(shopID)
+	if err != nil {
+		return err
+	}
+	def, ok := s.Catalog.Item(itemID)
+	if !ok {
+		return fmt.Errorf("unknown item %q", itemID)
+	}
+	if qty < 1 {
+		return errors.New("quantity must be positive")
+	}
+	stock := s.Market.InStock(shop, itemID)
+	if stock == 0 {
+		return fmt.Errorf("%s has no %s for sale", shop.Name, def.Name)
+	}
+	if qty > stock {
+		return fmt.Errorf("%s only has %d %s", shop.Name, stock, def.Name)
+	}
+	cost := BuyPrice(shop, def) * qty
+	if cost > s.Player.Gold {
+		return fmt.Errorf("%d %s costs %d gold (you have %d)", qty, def.Name, cost, s.Player.Gold)
+	}
+
+	if _, err := s.Storage.Add(itemID, qty); err != nil {
+		return err
+	}
+	s.Player.Gold -= cost
+	if s.Market.Sold[shop.ID] == nil {
+		s.Market.Sold[shop.ID] = make(map[string]int)
+	}
+	s.Market.Sold[shop.ID][itemID] += qty
+	s.Market.record(Transaction{Timestamp: time.Now(), Kind: TradeBuy, Shop: shop.ID, Item: itemID, Quantity: qty, Gold: cost})
+	s.Log("Market", fmt.Sprintf("Bought %d %s", qty, def.Name),
+		fmt.Sprintf("-%d gold (%d left)", cost, s.Player.Gold))
+	s.Events.Publish(ItemAdded{Item: itemID, Quantity: qty, Source: SourceMarket})
+	return nil
+}
+
+// Sell sells items from storage to a shop at the player's location. Every
+// item is checked before any is sold, so a failed sale changes nothing.
+func (s *State) Sell(shopID string, items []ItemAmount) error {
+	shop, err := s.shopHere(shopID)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return errors.New("nothing to sell")
+	}
+	for _, item := range items {
+		def, ok := s.Catalog.Item(item.Item)
+		if !ok {
+			return fmt.Errorf("unknown item %q", item.Item)
+		}
+		if item.Quantity < 1 {
+			return errors.New("quantity must be positive")
+		}
+		if have := s.Storage.Quantity(item.Item); have < item.Quantity {
+			return fmt.Errorf("you only have %d %s", have, def.Name)
+		}
+		if !shop.WillBuy(def) || SellPrice(shop, def) == 0 {
+			return fmt.Errorf("%s does not buy %s", shop.Name, def.Name)
+		}
+	}
+
+	if err := s.Storage.Exchange(items, nil); err != nil {
+		return err
+	}
+	total := 0
+	var sold []string
+	for _, item := range items {
+		def, _ := s.Catalog.Item(item.Item)
+		gold := SellPrice(shop, def) * item.Quantity
+		total += gold
+		sold = append(sold, fmt.Sprintf("%d %s", item.Quantity, def.Name))
+		s.Market.record(Transaction{Timestamp: time.Now(), Kind: TradeSell, Shop: shop.ID, Item: item.Item, Quantity: item.Quantity, Gold: gold})
+	}
+	s.Player.Gold += total
+
+	action := "Sold " + sold[0]
+	details := fmt.Sprintf("+%d gold", total)
+	if len(sold) > 1 {
+		action = fmt.Sprintf("Sold %d kinds of item", len(sold))
+		details = fmt.Sprintf("(%s) %s", strings.Join(sold, ", "), details)
+	}
+	s.Log("Market", action, details+" to "+shop.Name)
+	for _, item := range items {
+		s.Events.Publish(ItemRemoved{Item: item.Item, Quantity: item.Quantity, Source: SourceMarket})
+	}
+	return nil
+}
+
+// BestBuyer returns the local shop paying the most for every item, or an
+// error naming an item no shop here will take
+func (s *State) BestBuyer(items []ItemAmount) (*Shop, error) {
+	shops := s.ShopsHere()
+	if len(shops) == 0 {
+		return nil, fmt.Errorf("there is no market in %s", s.CurrentLocation().Name)
+	}
+
+	var best *Shop
+	bestTotal := 0
+	for _, shop := range shops {
+		total := 0
+		for _, item := range items {
+			def, ok := s.Catalog.Item(item.Item)
+			if !ok || !shop.WillBuy(def) || SellPrice(shop, def) == 0 {
+				total = -1
+				break
+			}
+			total += SellPrice(shop, def) * item.Quantity
+		}
+		if total > bestTotal {
+			best, bestTotal = shop, total
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("no shop in %s buys all of that", s.CurrentLocation().Name)
+	}
+	return best, nil
+}
@@ -0,0 +1,142 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxOfflineTime caps how much time away is simulated on load
+const MaxOfflineTime = 12 * time.Hour
+
+// minOfflineTime is the shortest absence worth simulating and reporting
+const minOfflineTime = time.Minute
+
+// OfflineSummary describes progress made while the game was closed
+type OfflineSummary struct {
+	Away      time.Duration // Wall-clock time since the save
+	Simulated time.Duration // Time actually simulated, after the cap
+	Cycles    int           // Action cycles completed
+	Items     []ItemDelta
+	XP        []SkillDelta
+}
+
+// ItemDelta is a change in the quantity of an item
+type ItemDelta struct {
+	ItemID   string
+	Name     string
+	Quantity int
+}
+
+// SkillDelta is XP gained in a skill and the resulting level
+type SkillDelta struct {
+	Skill SkillType
+	XP    int
+	Level int
+}
+
+// IsEmpty reports whether nothing happened while away
+func (o OfflineSummary) IsEmpty() bool {
+	return o.Cycles == 0 && len(o.Items) == 0 && len(o.XP) == 0
+}
+
+// String summarizes the gains on one line
+func (o OfflineSummary) String() string {
+	var parts []string
+	for _, item := range o.Items {
+		parts = append(parts, fmt.Sprintf("%+d %s", item.Quantity, item.Name))
+	}
+	for _, xp := range o.XP {
+		parts = append(parts, fmt.Sprintf("+%d %s XP", xp.XP, xp.Skill))
+	}
+	if len(parts) == 0 {
+		return "nothing gained"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// SimulateOffline runs the action and processing queues for the time
+// between the last save and now, up to MaxOfflineTime, and records a summary
+// in the activity log. The simulation runs the same fixed steps as Tick,
+// including recovery and restocking, and failed conversions are rolled
+// from the saved seed, so the outcome depends only on the state and the
+// elapsed time.
+func (s *State) SimulateOffline(now time.Time) (OfflineSummary, bool) {
+	if s.LastSaved.IsZero() || s.Clock.Paused || len(s.Actions)+len(s.Processing) == 0 {
+		return OfflineSummary{}, false
+	}
+
+	away := now.Sub(s.LastSaved)
+	if away < minOfflineTime {
+		return OfflineSummary{}, false
+	}
+	simulated := min(away, MaxOfflineTime)
+
+	itemsBefore := s.Storage.quantitiesByID()
+	xpBefore := make(map[SkillType]int, len(s.Skills))
+	for skill, xp := range s.Skills {
+		xpBefore[skill] = xp
+	}
+
+	// Individual gains are summarized below rather than flooding the log
+	restoreLog := s.ActivityLog.mute()
+	summary := OfflineSummary{Away: away, Simulated: simulated}
+	steps := int(simulated / TickInterval)
+	summary.Cycles = s.advance(steps)
+	s.Clock.Ticks += uint64(steps)
+	restoreLog()
+
+	itemsAfter := s.Storage.quantitiesByID()
+	for id, qty := range itemsAfter {
+		if delta := qty - itemsBefore[id]; delta != 0 {
+			summary.Items = append(summary.Items, ItemDelta{ItemID: id, Name: s.Catalog.ItemName(id), Quantity: delta})
+		}
+	}
+	for id, qty := range itemsBefore {
+		if _, ok := itemsAfter[id]; !ok {
+			summary.Items = append(summary.Items, ItemDelta{ItemID: id, Name: s.Catalog.ItemName(id), Quantity: -qty})
+		}
+	}
+	sort.Slice(summary.Items, func(i, j int) bool {
+		return summary.Items[i].Name < summary.Items[j].Name
+	})
+
+	for _, skill := range AllSkills {
+		if gained := s.Skills[skill] - xpBefore[skill]; gained > 0 {
+			summary.XP = append(summary.XP, SkillDelta{Skill: skill, XP: gained, Level: s.SkillLevel(skill)})
+		}
+	}
+
+	if summary.IsEmpty() {
+		return summary, false
+	}
+
+	s.Log("System", "While you were away ("+FormatDuration(simulated)+"):", summary.String())
+	return summary, true
+}
+
+// quantitiesByID totals item quantities across stacks
+func (s *Storage) quantitiesByID() map[string]int {
+	totals := make(map[string]int)
+	for _, item := range s.items {
+		totals[item.ID] += item.Quantity
+	}
+	return totals
+}
+
+// FormatDuration renders a duration like "2h 05m" or "45s"
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+	sec := int(d.Seconds()) % 60
+	switch {
+	case h > 0:
+		return fmt.Sprintf("%dh %02dm", h, m)
+	case m > 0:
+		return fmt.Sprintf("%dm %02ds", m, sec)
+	default:
+		return fmt.Sprintf("%ds", sec)
+	}
+}
@@ -0,0 +1,73 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// cloneState copies a state through its save snapshot
+func cloneState(t *testing.T, s *State) *State {
+	t.Helper()
+	data, err := json.Marshal(s.snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var snap stateSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		t.Fatal(err)
+	}
+	clone, err := stateFromSnapshot(snap, s.Catalog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return clone
+}
+
+// progress is the saved state without the log, which offline play mutes
+func progress(t *testing.T, s *State) string {
+	t.Helper()
+	snap := s.snapshot()
+	snap.ActivityLog = nil
+	data, err := json.Marshal(snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestSimulateOfflineMatchesTick(t *testing.T) {
+	s := newTestState(t)
+	s.Seed = 42
+	// Failed smelts roll from the seed, and the queue runs dry partway
+	if err := s.QueueProcess(NewAction(ActionProcess, "smelt_iron", 20)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Buy("general_store", "potion_strength", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UseItem("potion_strength"); err != nil {
+		t.Fatal(err)
+	}
+	s.Player.HP = 1
+
+	live, offline := cloneState(t, s), cloneState(t, s)
+	for range time.Hour / TickInterval {
+		live.Tick(TickInterval)
+	}
+	saved := time.Now()
+	offline.LastSaved = saved
+	if _, ok := offline.SimulateOffline(saved.Add(time.Hour)); !ok {
+		t.Fatal("nothing happened offline")
+	}
+
+	if got, want := progress(t, offline), progress(t, live); got != want {
+		t.Errorf("offline progress differs from live play\noffline %s\n   live %s", got, want)
+	}
+	if offline.Player.HP == 1 {
+		t.Error("offline play did not recover HP")
+	}
+	if len(offline.Market.Sold["general_store"]) != 0 {
+		t.Error("offline play did not restock the shop")
+	}
+}
@@ -0,0 +1,111 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultPlayerName is the name given to new characters
+const DefaultPlayerName = "Adventurer"
+
+// startingGold is the purse of a new character
+const startingGold = 100
+
+// maxPlayerName keeps the name inside the Character Info panel
+const maxPlayerName = 16
+
+// Attributes are the player's base attributes, before equipment
+type Attributes struct {
+	Strength int `json:"strength"` // Attack
+	Agility  int `json:"agility"`  // Defense
+	Vitality int `json:"vitality"` // Max HP
+	Wisdom   int `json:"wisdom"`   // Max MP
+}
+
+// Player is the player character. HP and MP are current values; their
+// maximums are derived, see State.PlayerStats.
+type Player struct {
+	Name       string     `json:"name"`
+	HP         int        `json:"hp"`
+	MP         int        `json:"mp"`
+	XP         int        `json:"xp"` // Combat XP; the level is derived from it
+	Gold       int        `json:"gold"`
+	Attributes Attributes `json:"attributes"`
+}
+
+// PlayerStats are the player's values after attributes, level, equipment
+// and skills are combined
+type PlayerStats struct {
+	Level      int
+	MaxHP      int
+	MaxMP      int
+	Attack     int
+	Defense    int
+	SkillTotal int // Sum of all skill levels
+}
+
+// NewPlayer creates a level 1 character at full health
+func NewPlayer() *Player {
+	p := &Player{
+		Name:       DefaultPlayerName,
+		Gold:       startingGold,
+		Attributes: Attributes{Strength: 5, Agility: 5, Vitality: 5, Wisdom: 5},
+	}
+	stats := p.stats(NewEquipment(), 0)
+	p.HP, p.MP = stats.MaxHP, stats.MaxMP
+	return p
+}
+
+// Level returns the player's combat level
+func (p *Player) Level() int {
+	return LevelForXP(p.XP)
+}
+
+// stats derives the player's stats. Every skill level past the first adds
+// one max HP, so time spent skilling still makes the character sturdier.
+func (p *Player) stats(equipment *Equipment, skillTotal int) PlayerStats {
+	bonus := equipment.Stats()
+	level := p.Level()
+	return PlayerStats{
+		Level:      level,
+		MaxHP:      30 + 4*p.Attributes.Vitality + 2*level + max(0, skillTotal-len(AllSkills)) + bonus["hp"],
+		MaxMP:      10 + 4*p.Attributes.Wisdom + level + bonus["mp"],
+		Attack:     2*p.Attributes.Strength + level + bonus["atk"],
+		Defense:    p.Attributes.Agility + level + bonus["def"],
+		SkillTotal: skillTotal,
+	}
+}
+
+// PlayerStats returns the player's derived stats
+func (s *State) PlayerStats() PlayerStats {
+	total := 0
+	for _, skill := range AllSkills {
+		total += s.SkillLevel(skill)
+	}
+	stats := s.Player.stats(s.Equipment, total)
+	stats.Attack += s.BuffBonus(StatAttack)
+	stats.Defense += s.BuffBonus(StatDefense)
+	return stats
+}
+
+// clampVitals keeps HP and MP within their maximums, which shrink when
+// gear is removed
+func (s *State) clampVitals() {
+	stats := s.PlayerStats()
+	s.Player.HP = min(s.Player.HP, stats.MaxHP)
+	s.Player.MP = min(s.Player.MP, stats.MaxMP)
+}
+
+// RenamePlayer changes the character's name
+func (s *State) RenamePlayer(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("name cannot be empty")
+	}
+	if len([]rune(name)) > maxPlayerName {
+		return fmt.Errorf("name is longer than %d characters", maxPlayerName)
+	}
+	s.Player.Name = name
+	return nil
+}
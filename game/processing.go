@@ -0,0 +1,219 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Station is a workshop that converts items, like a furnace or a range.
+// Stations and their conversions are loaded from content files.
+type Station struct {
+	ID    string       `json:"id"`
+	Name  string       `json:"name"`
+	Skill SkillType    `json:"skill"`
+	Fuel  []ItemAmount `json:"fuel,omitempty"` // Accepted fuels and the amount burned per conversion, in order of preference
+}
+
+// Conversion is one process a station can run. A failed conversion
+// consumes its inputs and fuel and yields Failed instead of Outputs.
+type Conversion struct {
+	ID       string       `json:"id"`
+	Name     string       `json:"name,omitempty"` // Defaults to the first output's name
+	Station  string       `json:"station"`
+	Level    int          `json:"level"`
+	XP       int          `json:"xp"` // Only awarded on success
+	Seconds  float64      `json:"seconds"`
+	Inputs   []ItemAmount `json:"inputs"`
+	Outputs  []ItemAmount `json:"outputs"`
+	Failed   []ItemAmount `json:"failed,omitempty"`    // What a failure leaves behind, e.g. burnt fish
+	FailRate int          `json:"fail_rate,omitempty"` // Percent chance to fail at the required level
+	SafeAt   int          `json:"safe_at,omitempty"`   // Level at which failures stop
+}
+
+// processingContent is the layout of the processing content file
+type processingContent struct {
+	Stations    []*Station    `json:"stations"`
+	Conversions []*Conversion `json:"conversions"`
+}
+
+// Duration returns the conversion's base time
+func (c *Conversion) Duration() time.Duration {
+	return time.Duration(c.Seconds * float64(time.Second))
+}
+
+// FailChance returns the percent chance to fail at a skill level. The
+// chance falls linearly from FailRate at the required level to zero at
+// SafeAt.
+func (c *Conversion) FailChance(level int) int {
+	if c.FailRate == 0 || level >= c.SafeAt {
+		return 0
+	}
+	span := c.SafeAt - c.Level
+	return c.FailRate * (c.SafeAt - max(level, c.Level)) / span
+}
+
+// ProcessingSkill returns the skill a conversion trains
+func (s *State) ProcessingSkill(conv *Conversion) SkillType {
+	station, _ := s.Catalog.Station(conv.Station)
+	return station.Skill
+}
+
+// fuelFor returns the first fuel option with enough in storage. Fuel that
+// is also an input, like coal for steel, must cover both.
+func (s *State) fuelFor(station *Station, conv *Conversion) (ItemAmount, bool) {
+	for _, fuel := range station.Fuel {
+		need := fuel.Quantity
+		for _, input := range conv.Inputs {
+			if input.Item == fuel.Item {
+				need += input.Quantity
+			}
+		}
+		if s.Storage.Quantity(fuel.Item) >= need {
+			return fuel, true
+		}
+	}
+	return ItemAmount{}, false
+}
+
+// CanProcess reports why a conversion cannot run right now, if it cannot
+func (s *State) CanProcess(conv *Conversion) error {
+	station, _ := s.Catalog.Station(conv.Station)
+	if level := s.SkillLevel(station.Skill); level < conv.Level {
+		return fmt.Errorf("%s requires %s level %d (you are %d)", conv.Name, station.Skill, conv.Level, level)
+	}
+
+	var missing []string
+	for _, input := range conv.Inputs {
+		if have := s.Storage.Quantity(input.Item); have < input.Quantity {
+			missing = append(missing, fmt.Sprintf("%d %s", input.Quantity-have, s.Catalog.ItemName(input.Item)))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("need %s more", strings.Join(missing, ", "))
+	}
+
+	if len(station.Fuel) > 0 {
+		if _, ok := s.fuelFor(station, conv); !ok {
+			return fmt.Errorf("the %s is out of fuel", strings.ToLower(station.Name))
+		}
+	}
+	return nil
+}
+
+// MaxProcesses returns how many times a conversion can run from the
+// inputs in storage, ignoring fuel
+func (s *State) MaxProcesses(conv *Conversion) int {
+	most := -1
+	for _, input := range conv.Inputs {
+		n := s.Storage.Quantity(input.Item) / input.Quantity
+		if most < 0 || n < most {
+			most = n
+		}
+	}
+	return max(0, most)
+}
+
+// ProcessDuration returns how long one conversion takes. Each level above
+// the requirement speeds it up.
+func (s *State) ProcessDuration(conv *Conversion) time.Duration {
+	skill := s.ProcessingSkill(conv)
+	levelsAbove := max(0, s.SkillLevel(skill)-conv.Level)
+	speed := max(10, 100+2*levelsAbove+s.BuffBonus(skillStat(skill)))
+	return conv.Duration() * 100 / time.Duration(speed)
+}
+
+// Process runs one conversion. Inputs and fuel are only consumed when all
+// of them are available.
+func (s *State) Process(convID string) error {
+	conv, ok := s.Catalog.Conversion(convID)
+	if !ok {
+		return fmt.Errorf("unknown conversion %q", convID)
+	}
+	if err := s.CanProcess(conv); err != nil {
+		return err
+	}
+	station, _ := s.Catalog.Station(conv.Station)
+
+	used := conv.Inputs
+	if len(station.Fuel) > 0 {
+		fuel, _ := s.fuelFor(station, conv)
+		used = append(append([]ItemAmount{}, used...), fuel)
+	}
+	failed := s.roll(100) < conv.FailChance(s.SkillLevel(station.Skill))
+	outputs := conv.Outputs
+	if failed {
+		outputs = conv.Failed
+	}
+	if err := s.Storage.Exchange(used, outputs); err != nil {
+		return err
+	}
+	var made []string
+	for _, output := range outputs {
+		made = append(made, fmt.Sprintf("+%d %s", output.Quantity, s.Catalog.ItemName(output.Item)))
+	}
+
+	var spent []string
+	for _, item := range used {
+		spent = append(spent, fmt.Sprintf("-%d %s", item.Quantity, s.Catalog.ItemName(item.Item)))
+	}
+	details := "(" + strings.Join(spent, ", ") + ")"
+
+	if failed {
+		if len(made) > 0 {
+			details = strings.Join(made, ", ") + " " + details
+		}
+		s.Log(string(station.Skill), "Failed: "+conv.Name, details)
+	} else {
+		s.AddXP(station.Skill, conv.XP)
+		s.Log(string(station.Skill), strings.Join(made, ", "), fmt.Sprintf("%s +%d XP", details, conv.XP))
+	}
+	for _, item := range used {
+		s.Events.Publish(ItemRemoved{Item: item.Item, Quantity: item.Quantity, Source: SourceProcess})
+	}
+	for _, output := range outputs {
+		s.Events.Publish(ItemAdded{Item: output.Item, Quantity: output.Quantity, Source: SourceProcess})
+	}
+	return nil
+}
+
+// CurrentProcess returns the conversion in progress, if any
+func (s *State) CurrentProcess() (Action, bool) {
+	if len(s.Processing) == 0 {
+		return Action{}, false
+	}
+	return s.Processing[0], true
+}
+
+// QueueProcess adds a conversion to the processing queue. Stations work in
+// parallel with the main action queue.
+func (s *State) QueueProcess(action Action) error {
+	if action.Kind != ActionProcess {
+		return errors.New("only processing actions can be queued at a station")
+	}
+	if err := s.validateAction(action, s.Location); err != nil {
+		return err
+	}
+	s.Processing = append(s.Processing, action)
+	return nil
+}
+
+// CancelProcess stops the current conversion and starts the next one
+func (s *State) CancelProcess() (Action, bool) {
+	current, ok := s.CurrentProcess()
+	if !ok {
+		return Action{}, false
+	}
+	s.Processing = s.Processing[1:]
+	return current, true
+}
+
+// ProcessProgress returns the fraction of the current conversion completed
+func (s *State) ProcessProgress() float64 {
+	current, ok := s.CurrentProcess()
+	if !ok {
+		return 0
+	}
+	return min(1, float64(current.Elapsed)/float64(s.ActionDuration(current)))
+}
@@ -0,0 +1,496 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Query is a compiled storage search expression.
+//
+// Syntax:
+//
+//	iron              name or tag contains "iron"
+//	"iron ore"        quoted phrase
+//	*ore  ir?n*       wildcards match whole values (* any run, ? one character)
+//	tag:weapon        field predicate (tag, name, category/cat, qty, value, total, equipped)
+//	qty:>50           comparison (=, !=, >, >=, <, <=) on numeric fields
+//	-tag:ore  !x      negation (also NOT)
+//	a b  a AND b      both must match (AND is implied between terms)
+//	a OR b  a | b     either may match
+//	( ... )           grouping
+type Query struct {
+	root   queryNode
+	fields map[string]bool
+}
+
+// Match reports whether the item satisfies the query. The empty query
+// matches everything.
+func (q Query) Match(item Item) bool {
+	if q.root == nil {
+		return true
+	}
+	return q.root.match(item)
+}
+
+// IsEmpty reports whether the query has no predicates
+func (q Query) IsEmpty() bool {
+	return q.root == nil
+}
+
+// UsesField reports whether any predicate in the query targets the field
+func (q Query) UsesField(field string) bool {
+	return q.fields[field]
+}
+
+// QueryError describes a problem parsing a search query
+type QueryError struct {
+	Pos int // 1-based column of the offending token
+	Msg string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("col %d: %s", e.Pos, e.Msg)
+}
+
+// ParseQuery compiles a search string into a Query
+func ParseQuery(input string) (Query, error) {
+	tokens, err := tokenizeQuery(input)
+	if err != nil {
+		return Query{}, err
+	}
+
+	p := &queryParser{tokens: tokens, fields: make(map[string]bool)}
+	if len(tokens) == 0 {
+		return Query{fields: p.fields}, nil
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return Query{}, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return Query{}, &QueryError{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %q", tok.text)}
+	}
+
+	return Query{root: root, fields: p.fields}, nil
+}
+
+// Tokenizer
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokTerm
+	tokLParen
+	tokRParen
+	tokAnd
+	tokOr
+	tokNot
+)
+
+type queryToken struct {
+	kind   tokenKind
+	text   string
+	pos    int
+	quoted bool // Term contained a quoted section
+}
+
+func tokenizeQuery(input string) ([]queryToken, error) {
+	var tokens []queryToken
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, queryToken{kind: tokLParen, text: "(", pos: pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, queryToken{kind: tokRParen, text: ")", pos: pos})
+			i++
+		case r == '|':
+			tokens = append(tokens, queryToken{kind: tokOr, text: "|", pos: pos})
+			i++
+		case r == '&':
+			tokens = append(tokens, queryToken{kind: tokAnd, text: "&", pos: pos})
+			i++
+		case (r == '-' || r == '!') && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]):
+			tokens = append(tokens, queryToken{kind: tokNot, text: string(r), pos: pos})
+			i++
+		default:
+			var text strings.Builder
+			quoted := false
+			for i < len(runes) {
+				c := runes[i]
+				if c == '"' {
+					end := i + 1
+					for end < len(runes) && runes[end] != '"' {
+						end++
+					}
+					if end >= len(runes) {
+						return nil, &QueryError{Pos: i + 1, Msg: "unterminated quote"}
+					}
+					text.WriteString(string(runes[i+1 : end]))
+					quoted = true
+					i = end + 1
+					continue
+				}
+				if unicode.IsSpace(c) || c == '(' || c == ')' || c == '|' || c == '&' {
+					break
+				}
+				text.WriteRune(c)
+				i++
+			}
+
+			tok := queryToken{kind: tokTerm, text: text.String(), pos: pos, quoted: quoted}
+			if !quoted {
+				switch tok.text {
+				case "AND":
+					tok.kind = tokAnd
+				case "OR":
+					tok.kind = tokOr
+				case "NOT":
+					tok.kind = tokNot
+				}
+			}
+			tokens = append(tokens, tok)
+		}
+	}
+
+	return tokens, nil
+}
+
+// Parser
+
+type queryParser struct {
+	tokens []queryToken
+	pos    int
+	fields map[string]bool
+}
+
+func (p *queryParser) peek() queryToken {
+	if p.pos >= len(p.tokens) {
+		end := 1
+		if len(p.tokens) > 0 {
+			last := p.tokens[len(p.tokens)-1]
+			end = last.pos + len([]rune(last.text))
+		}
+		return queryToken{kind: tokEOF, text: "end of query", pos: end}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *queryParser) next() queryToken {
+	tok := p.peek()
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *queryParser) parseOr() (queryNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (queryNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch p.peek().kind {
+		case tokAnd:
+			p.next()
+		case tokTerm, tokNot, tokLParen:
+			// Implicit AND
+		default:
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+}
+
+func (p *queryParser) parseUnary() (queryNode, error) {
+	if p.peek().kind == tokNot {
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{inner}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *queryParser) parsePrimary() (queryNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, &QueryError{Pos: closing.pos, Msg: fmt.Sprintf("expected \")\" but found %q", closing.text)}
+		}
+		return inner, nil
+	case tokTerm:
+		return p.parseTerm(tok)
+	case tokEOF:
+		return nil, &QueryError{Pos: tok.pos, Msg: "expected a search term"}
+	default:
+		return nil, &QueryError{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %q", tok.text)}
+	}
+}
+
+// Query fields and their aliases
+var queryFieldAliases = map[string]string{
+	"tag":      "tag",
+	"tags":     "tag",
+	"name":     "name",
+	"category": "category",
+	"cat":      "category",
+	"qty":      "qty",
+	"quantity": "qty",
+	"value":    "value",
+	"total":    "total",
+	"equipped": "equipped",
+}
+
+func (p *queryParser) parseTerm(tok queryToken) (queryNode, error) {
+	field, value, hasField := strings.Cut(tok.text, ":")
+	if !hasField {
+		return textNode{pattern: strings.ToLower(tok.text)}, nil
+	}
+
+	canonical, ok := queryFieldAliases[strings.ToLower(field)]
+	if !ok {
+		return nil, &QueryError{Pos: tok.pos, Msg: fmt.Sprintf("unknown field %q", field)}
+	}
+	p.fields[canonical] = true
+
+	valuePos := tok.pos + len([]rune(field)) + 1
+	op, operand := splitOperator(value)
+	if operand == "" {
+		return nil, &QueryError{Pos: valuePos, Msg: fmt.Sprintf("missing value for %s:", field)}
+	}
+
+	switch canonical {
+	case "qty", "value", "total":
+		n, err := strconv.Atoi(operand)
+		if err != nil {
+			return nil, &QueryError{Pos: valuePos, Msg: fmt.Sprintf("%s expects a number, got %q", field, operand)}
+		}
+		if op == "" {
+			op = "="
+		}
+		return numberNode{field: canonical, op: op, value: n}, nil
+
+	case "equipped":
+		if op != "" && op != "=" {
+			return nil, &QueryError{Pos: valuePos, Msg: "equipped only supports true or false"}
+		}
+		b, err := parseQueryBool(operand)
+		if err != nil {
+			return nil, &QueryError{Pos: valuePos, Msg: err.Error()}
+		}
+		return equippedNode{want: b}, nil
+
+	default:
+		if op != "" && op != "=" && op != "!=" {
+			return nil, &QueryError{Pos: valuePos, Msg: fmt.Sprintf("%s does not support %q", field, op)}
+		}
+		var node queryNode = fieldNode{field: canonical, pattern: strings.ToLower(operand), exact: op != ""}
+		if op == "!=" {
+			node = notNode{node}
+		}
+		return node, nil
+	}
+}
+
+// splitOperator separates a leading comparison operator from its operand
+func splitOperator(value string) (string, string) {
+	for _, op := range []string{">=", "<=", "!=", ">", "<", "="} {
+		if strings.HasPrefix(value, op) {
+			return op, value[len(op):]
+		}
+	}
+	return "", value
+}
+
+func parseQueryBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true", "yes", "y", "1":
+		return true, nil
+	case "false", "no", "n", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("expected true or false, got %q", s)
+}
+
+// Expression nodes
+
+type queryNode interface {
+	match(item Item) bool
+}
+
+type andNode struct{ left, right queryNode }
+
+func (n andNode) match(item Item) bool { return n.left.match(item) && n.right.match(item) }
+
+type orNode struct{ left, right queryNode }
+
+func (n orNode) match(item Item) bool { return n.left.match(item) || n.right.match(item) }
+
+type notNode struct{ inner queryNode }
+
+func (n notNode) match(item Item) bool { return !n.inner.match(item) }
+
+// textNode matches free text against the item name and tags
+type textNode struct {
+	pattern string
+}
+
+func (n textNode) match(item Item) bool {
+	if matchText(item.Name, n.pattern, false) {
+		return true
+	}
+	for _, tag := range item.Tags {
+		if matchText(tag, n.pattern, false) {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldNode matches a text field. Tags and categories match whole values;
+// names match substrings unless exact is set.
+type fieldNode struct {
+	field   string
+	pattern string
+	exact   bool
+}
+
+func (n fieldNode) match(item Item) bool {
+	switch n.field {
+	case "name":
+		return matchText(item.Name, n.pattern, n.exact)
+	case "category":
+		return matchText(item.Category, n.pattern, true)
+	case "tag":
+		for _, tag := range item.Tags {
+			if matchText(tag, n.pattern, true) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type numberNode struct {
+	field string
+	op    string
+	value int
+}
+
+func (n numberNode) match(item Item) bool {
+	var actual int
+	switch n.field {
+	case "qty":
+		actual = item.Quantity
+	case "value":
+		actual = item.Value
+	case "total":
+		actual = item.Value * item.Quantity
+	}
+
+	switch n.op {
+	case ">":
+		return actual > n.value
+	case ">=":
+		return actual >= n.value
+	case "<":
+		return actual < n.value
+	case "<=":
+		return actual <= n.value
+	case "!=":
+		return actual != n.value
+	default:
+		return actual == n.value
+	}
+}
+
+type equippedNode struct {
+	want bool
+}
+
+func (n equippedNode) match(item Item) bool { return item.Equipped == n.want }
+
+// matchText compares s against a lowercase pattern. Patterns containing
+// wildcards must match the whole value; otherwise exact selects between
+// equality and substring matching.
+func matchText(s, pattern string, exact bool) bool {
+	s = strings.ToLower(s)
+	if strings.ContainsAny(pattern, "*?") {
+		return wildcardMatch(pattern, s)
+	}
+	if exact {
+		return s == pattern
+	}
+	return strings.Contains(s, pattern)
+}
+
+// wildcardMatch reports whether s matches pattern, where * matches any run
+// of characters and ? matches exactly one
+func wildcardMatch(pattern, s string) bool {
+	p := []rune(pattern)
+	str := []rune(s)
+	pi, si := 0, 0
+	star, mark := -1, 0
+
+	for si < len(str) {
+		switch {
+		case pi < len(p) && (p[pi] == '?' || p[pi] == str[si]):
+			pi++
+			si++
+		case pi < len(p) && p[pi] == '*':
+			star = pi
+			mark = si
+			pi++
+		case star >= 0:
+			pi = star + 1
+			mark++
+			si = mark
+		default:
+			return false
+		}
+	}
+
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
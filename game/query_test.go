@@ -0,0 +1,202 @@
+package game
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// queryItems are the items the query tests search
+var queryItems = []Item{
+	{ItemDef: &ItemDef{ID: "ore_iron", Name: "Iron Ore", Value: 8, Tags: []string{"ore", "metal"}, Category: "Resources"}, Quantity: 60},
+	{ItemDef: &ItemDef{ID: "bar_iron", Name: "Iron Bar", Value: 25, Tags: []string{"bar", "metal"}, Category: "Materials"}, Quantity: 4},
+	{ItemDef: &ItemDef{ID: "wood_oak", Name: "Oak Logs", Value: 3, Tags: []string{"wood", "log"}, Category: "Resources"}, Quantity: 150},
+	{ItemDef: &ItemDef{ID: "sword_iron", Name: "Iron Sword", Value: 120, Tags: []string{"weapon", "sword"}, Category: "Weapons"}, Quantity: 1, Equipped: true},
+}
+
+func TestTokenizeQuery(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []queryToken
+	}{
+		{"", nil},
+		{"  iron  ", []queryToken{{kind: tokTerm, text: "iron", pos: 3}}},
+		{"tag:ore qty:>=5", []queryToken{
+			{kind: tokTerm, text: "tag:ore", pos: 1},
+			{kind: tokTerm, text: "qty:>=5", pos: 9},
+		}},
+		{`"iron ore" name:"oak logs"`, []queryToken{
+			{kind: tokTerm, text: "iron ore", pos: 1, quoted: true},
+			{kind: tokTerm, text: "name:oak logs", pos: 12, quoted: true},
+		}},
+		{"(a|b)&c", []queryToken{
+			{kind: tokLParen, text: "(", pos: 1},
+			{kind: tokTerm, text: "a", pos: 2},
+			{kind: tokOr, text: "|", pos: 3},
+			{kind: tokTerm, text: "b", pos: 4},
+			{kind: tokRParen, text: ")", pos: 5},
+			{kind: tokAnd, text: "&", pos: 6},
+			{kind: tokTerm, text: "c", pos: 7},
+		}},
+		{"a AND NOT b OR c", []queryToken{
+			{kind: tokTerm, text: "a", pos: 1},
+			{kind: tokAnd, text: "AND", pos: 3},
+			{kind: tokNot, text: "NOT", pos: 7},
+			{kind: tokTerm, text: "b", pos: 11},
+			{kind: tokOr, text: "OR", pos: 13},
+			{kind: tokTerm, text: "c", pos: 16},
+		}},
+		{`"OR" or`, []queryToken{
+			{kind: tokTerm, text: "OR", pos: 1, quoted: true},
+			{kind: tokTerm, text: "or", pos: 6},
+		}},
+		{"-tag:ore !x - y", []queryToken{
+			{kind: tokNot, text: "-", pos: 1},
+			{kind: tokTerm, text: "tag:ore", pos: 2},
+			{kind: tokNot, text: "!", pos: 10},
+			{kind: tokTerm, text: "x", pos: 11},
+			{kind: tokTerm, text: "-", pos: 13},
+			{kind: tokTerm, text: "y", pos: 15},
+		}},
+		{"*ore ir?n*", []queryToken{
+			{kind: tokTerm, text: "*ore", pos: 1},
+			{kind: tokTerm, text: "ir?n*", pos: 6},
+		}},
+		{"élan x", []queryToken{
+			{kind: tokTerm, text: "élan", pos: 1},
+			{kind: tokTerm, text: "x", pos: 6},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := tokenizeQuery(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("tokens\n got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseQueryMatches(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"ore_iron", "bar_iron", "wood_oak", "sword_iron"}},
+		{"iron", []string{"ore_iron", "bar_iron", "sword_iron"}},
+		{"IRON", []string{"ore_iron", "bar_iron", "sword_iron"}},
+		{"metal", []string{"ore_iron", "bar_iron"}},
+		{`"iron ore"`, []string{"ore_iron"}},
+		{"name:bar", []string{"bar_iron"}},
+		{"name:=iron", nil},
+		{`name:="iron bar"`, []string{"bar_iron"}},
+		{"name:!=oak*", []string{"ore_iron", "bar_iron", "sword_iron"}},
+		{"tag:sword", []string{"sword_iron"}},
+		{"tag:swo", nil},
+		{"tags:wo*", []string{"wood_oak"}},
+		{"cat:resources", []string{"ore_iron", "wood_oak"}},
+		{"category:Weapons", []string{"sword_iron"}},
+		{"*ore", []string{"ore_iron"}},
+		{"ir?n*", []string{"ore_iron", "bar_iron", "sword_iron"}},
+		{"i?n", nil},
+		{"qty:60", []string{"ore_iron"}},
+		{"qty:=4", []string{"bar_iron"}},
+		{"qty:!=1", []string{"ore_iron", "bar_iron", "wood_oak"}},
+		{"qty:>60", []string{"wood_oak"}},
+		{"qty:>=60", []string{"ore_iron", "wood_oak"}},
+		{"qty:<4", []string{"sword_iron"}},
+		{"qty:<=4", []string{"bar_iron", "sword_iron"}},
+		{"quantity:>100", []string{"wood_oak"}},
+		{"value:>=25", []string{"bar_iron", "sword_iron"}},
+		{"total:>400", []string{"ore_iron", "wood_oak"}},
+		{"equipped:true", []string{"sword_iron"}},
+		{"equipped:no", []string{"ore_iron", "bar_iron", "wood_oak"}},
+		{"iron metal", []string{"ore_iron", "bar_iron"}},
+		{"iron AND metal", []string{"ore_iron", "bar_iron"}},
+		{"iron & -metal", []string{"sword_iron"}},
+		{"-iron", []string{"wood_oak"}},
+		{"!iron", []string{"wood_oak"}},
+		{"NOT iron", []string{"wood_oak"}},
+		{"NOT NOT wood", []string{"wood_oak"}},
+		{"wood OR sword", []string{"wood_oak", "sword_iron"}},
+		{"wood | tag:bar", []string{"bar_iron", "wood_oak"}},
+		{"wood OR ore AND qty:<10", []string{"wood_oak"}},
+		{"(wood OR ore) qty:>50", []string{"ore_iron", "wood_oak"}},
+		{"-(iron OR oak)", nil},
+		{"((metal))", []string{"ore_iron", "bar_iron"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, item := range queryItems {
+				if q.Match(item) {
+					got = append(got, item.ID)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("matched %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseQueryFields(t *testing.T) {
+	q, err := ParseQuery("iron cat:ores OR (quantity:>5 -tags:x)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for field, want := range map[string]bool{"category": true, "qty": true, "tag": true, "name": false, "value": false} {
+		if got := q.UsesField(field); got != want {
+			t.Errorf("UsesField(%q) = %v, want %v", field, got, want)
+		}
+	}
+	if empty, _ := ParseQuery("   "); !empty.IsEmpty() {
+		t.Error("blank query is not empty")
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		pos   int
+		msg   string
+	}{
+		{`iron "ore`, 6, "unterminated quote"},
+		{`name:"oak`, 6, "unterminated quote"},
+		{"colour:red", 1, `unknown field "colour"`},
+		{"iron tag:", 10, "missing value for tag:"},
+		{"qty:>", 5, "missing value for qty:"},
+		{"qty:lots", 5, `qty expects a number, got "lots"`},
+		{"value:>=1k", 7, `value expects a number, got "1k"`},
+		{"equipped:maybe", 10, `expected true or false, got "maybe"`},
+		{"equipped:>1", 10, "equipped only supports true or false"},
+		{"tag:>ore", 5, `tag does not support ">"`},
+		{"(iron", 6, `expected ")" but found "end of query"`},
+		{"(iron ore", 10, `expected ")" but found "end of query"`},
+		{"iron)", 5, `unexpected ")"`},
+		{"()", 2, `unexpected ")"`},
+		{"iron OR", 8, "expected a search term"},
+		{"NOT", 4, "expected a search term"},
+		{"OR iron", 1, `unexpected "OR"`},
+		{"iron AND | ore", 10, `unexpected "|"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := ParseQuery(tt.query)
+			var qerr *QueryError
+			if !errors.As(err, &qerr) {
+				t.Fatalf("got error %v, want a QueryError", err)
+			}
+			if qerr.Pos != tt.pos || qerr.Msg != tt.msg {
+				t.Errorf("got col %d %q, want col %d %q", qerr.Pos, qerr.Msg, tt.pos, tt.msg)
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ObjectiveKind is what an objective asks of the player
+type ObjectiveKind string
+
+const (
+	ObjectiveGather  ObjectiveKind = "gather"  // Obtain items by gathering, crafting, processing or loot
+	ObjectiveDefeat  ObjectiveKind = "defeat"  // Win fights against a monster
+	ObjectiveReach   ObjectiveKind = "reach"   // Arrive at a location
+	ObjectiveDeliver ObjectiveKind = "deliver" // Hand over items from storage
+)
+
+// Objective is one step of a quest. Steps are completed in order and only
+// the current step tracks progress.
+type Objective struct {
+	Kind   ObjectiveKind `json:"kind"`
+	Target string        `json:"target"` // Item, monster or location ID
+	Count  int           `json:"count,omitempty"`
+}
+
+// QuestReward is granted when the last objective is completed
+type QuestReward struct {
+	Gold   int               `json:"gold,omitempty"`
+	XP     int               `json:"xp,omitempty"`     // Combat XP
+	Skills map[SkillType]int `json:"skills,omitempty"` // Skill XP
+	Items  []ItemAmount      `json:"items,omitempty"`
+}
+
+// Quest is a sequence of objectives with a reward. Quests are loaded from
+// content files.
+type Quest struct {
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Requires    []string    `json:"requires,omitempty"` // Quests that must be completed first
+	Level       int         `json:"level,omitempty"`    // Minimum combat level
+	Objectives  []Objective `json:"objectives"`
+	Reward      QuestReward `json:"reward"`
+}
+
+// QuestStatus is where a quest stands for the player
+type QuestStatus string
+
+const (
+	QuestLocked    QuestStatus = "locked"
+	QuestAvailable QuestStatus = "available"
+	QuestActive    QuestStatus = "active"
+	QuestCompleted QuestStatus = "completed"
+)
+
+// QuestProgress tracks an accepted quest
+type QuestProgress struct {
+	Step      int  `json:"step"`  // Index of the current objective
+	Count     int  `json:"count"` // Progress toward the current objective
+	Completed bool `json:"completed,omitempty"`
+}
+
+// QuestStatus returns the status of a quest
+func (s *State) QuestStatus(quest *Quest) QuestStatus {
+	if progress, ok := s.Quests[quest.ID]; ok {
+		if progress.Completed {
+			return QuestCompleted
+		}
+		return QuestActive
+	}
+	if s.questLocked(quest) != nil {
+		return QuestLocked
+	}
+	return QuestAvailable
+}
+
+// questLocked reports why a quest cannot be accepted yet, if it cannot
+func (s *State) questLocked(quest *Quest) error {
+	for _, id := range quest.Requires {
+		if progress, ok := s.Quests[id]; !ok || !progress.Completed {
+			required, _ := s.Catalog.Quest(id)
+			return fmt.Errorf("complete %s first", required.Name)
+		}
+	}
+	if level := s.Player.Level(); level < quest.Level {
+		return fmt.Errorf("%s requires combat level %d (you are %d)", quest.Name, quest.Level, level)
+	}
+	return nil
+}
+
+// QuestsByStatus returns the quests with a status, in file order
+func (s *State) QuestsByStatus(status QuestStatus) []*Quest {
+	var quests []*Quest
+	for _, quest := range s.Catalog.Quests() {
+		if s.QuestStatus(quest) == status {
+			quests = append(quests, quest)
+		}
+	}
+	return quests
+}
+
+// CurrentObjective returns the objective an active quest is on
+func (s *State) CurrentObjective(quest *Quest) (Objective, int, bool) {
+	progress, ok := s.Quests[quest.ID]
+	if !ok || progress.Completed {
+		return Objective{}, 0, false
+	}
+	return quest.Objectives[progress.Step], progress.Count, true
+}
+
+// AcceptQuest starts an available quest
+func (s *State) AcceptQuest(id string) error {
+	quest, ok := s.Catalog.Quest(id)
+	if !ok {
+		return fmt.Errorf("unknown quest %q", id)
+	}
+	switch s.QuestStatus(quest) {
+	case QuestActive:
+		return fmt.Errorf("%s is already active", quest.Name)
+	case QuestCompleted:
+		return fmt.Errorf("%s is already completed", quest.Name)
+	case QuestLocked:
+		return s.questLocked(quest)
+	}
+
+	s.Quests[quest.ID] = &QuestProgress{}
+	s.Log("Quests", "Accepted "+quest.Name, s.DescribeObjective(quest.Objectives[0]))
+	s.checkArrival(quest.Objectives[0])
+	return nil
+}
+
+// AbandonQuest drops an active quest and its progress
+func (s *State) AbandonQuest(id string) error {
+	quest, ok := s.Catalog.Quest(id)
+	if !ok {
+		return fmt.Errorf("unknown quest %q", id)
+	}
+	if s.QuestStatus(quest) != QuestActive {
+		return fmt.Errorf("%s is not active", quest.Name)
+	}
+	delete(s.Quests, quest.ID)
+	s.Log("Quests", "Abandoned "+quest.Name, "")
+	return nil
+}
+
+// DeliverQuest hands over items for an active quest's deliver objective.
+// Partial deliveries count toward the objective.
+func (s *State) DeliverQuest(id string) error {
+	quest, ok := s.Catalog.Quest(id)
+	if !ok {
+		return fmt.Errorf("unknown quest %q", id)
+	}
+	objective, count, ok := s.CurrentObjective(quest)
+	if !ok {
+		return fmt.Errorf("%s is not active", quest.Name)
+	}
+	if objective.Kind != ObjectiveDeliver {
+		return errors.New(quest.Name + " has nothing to deliver yet")
+	}
+
+	qty := min(objective.Count-count, s.Storage.Quantity(objective.Target))
+	if qty == 0 {
+		return fmt.Errorf("no %s in storage", s.Catalog.ItemName(objective.Target))
+	}
+	if err := s.Storage.Remove(objective.Target, qty); err != nil {
+		return err
+	}
+	s.Events.Publish(ItemRemoved{Item: objective.Target, Quantity: qty, Source: SourceQuest})
+	s.advanceQuests(ObjectiveDeliver, objective.Target, qty)
+	return nil
+}
+
+// trackQuests subscribes quest progress to the events objectives count.
+// Deliveries are counted by DeliverQuest itself.
+func (s *State) trackQuests() {
+	On(s.Events, func(e ItemAdded) {
+		if e.Source.Obtained() {
+			s.advanceQuests(ObjectiveGather, e.Item, e.Quantity)
+		}
+	})
+	On(s.Events, func(e MonsterDefeated) {
+		s.advanceQuests(ObjectiveDefeat, e.Monster, 1)
+	})
+	On(s.Events, func(e LocationChanged) {
+		s.advanceQuests(ObjectiveReach, e.To, 1)
+	})
+}
+
+// advanceQuests records progress for every active quest whose current
+// objective matches
+func (s *State) advanceQuests(kind ObjectiveKind, target string, n int) {
+	for _, quest := range s.Catalog.Quests() {
+		progress, ok := s.Quests[quest.ID]
+		if !ok || progress.Completed {
+			continue
+		}
+		objective := quest.Objectives[progress.Step]
+		if objective.Kind != kind || objective.Target != target {
+			continue
+		}
+
+		progress.Count = min(objective.Count, progress.Count+n)
+		s.Events.Publish(QuestProgressed{Quest: quest.ID, Step: progress.Step, Count: progress.Count})
+		if progress.Count < objective.Count {
+			s.Log("Quests", quest.Name+":", fmt.Sprintf("%s (%d/%d)", s.DescribeObjective(objective), progress.Count, objective.Count))
+			continue
+		}
+
+		progress.Step++
+		progress.Count = 0
+		if progress.Step == len(quest.Objectives) {
+			s.completeQuest(quest)
+			continue
+		}
+		next := quest.Objectives[progress.Step]
+		s.Log("Quests", quest.Name+": objective complete", "Next: "+s.DescribeObjective(next))
+		s.checkArrival(next)
+	}
+}
+
+// checkArrival completes a reach objective that became current while the
+// player is already at its location
+func (s *State) checkArrival(objective Objective) {
+	if objective.Kind == ObjectiveReach && objective.Target == s.Location {
+		s.advanceQuests(ObjectiveReach, s.Location, 1)
+	}
+}
+
+// completeQuest marks a quest done and grants its reward
+func (s *State) completeQuest(quest *Quest) {
+	progress := s.Quests[quest.ID]
+	progress.Completed = true
+	progress.Step = len(quest.Objectives) - 1
+
+	reward := quest.Reward
+	var gains []string
+	if reward.Gold > 0 {
+		s.Player.Gold += reward.Gold
+		gains = append(gains, fmt.Sprintf("+%d gold", reward.Gold))
+	}
+	// The quest can't be turned in again, so its items go into storage
+	// even when it is full
+	for _, item := range reward.Items {
+		s.Storage.restore(item.Item, item.Quantity)
+		gains = append(gains, fmt.Sprintf("+%d %s", item.Quantity, s.Catalog.ItemName(item.Item)))
+	}
+	if reward.XP > 0 {
+		gains = append(gains, fmt.Sprintf("+%d XP", reward.XP))
+	}
+	skills := make([]SkillType, 0, len(reward.Skills))
+	for skill := range reward.Skills {
+		skills = append(skills, skill)
+	}
+	sort.Slice(skills, func(i, j int) bool { return skills[i] < skills[j] })
+	for _, skill := range skills {
+		gains = append(gains, fmt.Sprintf("+%d %s XP", reward.Skills[skill], skill))
+	}
+	s.Log("Quests", "Completed "+quest.Name, strings.Join(gains, ", "))
+	for _, item := range reward.Items {
+		s.Events.Publish(ItemAdded{Item: item.Item, Quantity: item.Quantity, Source: SourceReward})
+	}
+	s.Events.Publish(QuestFinished{Quest: quest.ID})
+
+	// XP is awarded after the completion entry so level-ups read in order
+	if reward.XP > 0 {
+		s.addCombatXP(reward.XP)
+	}
+	for _, skill := range skills {
+		s.AddXP(skill, reward.Skills[skill])
+	}
+}
+
+// DescribeObjective renders an objective like "Defeat 5 Giant Rat"
+func (s *State) DescribeObjective(objective Objective) string {
+	switch objective.Kind {
+	case ObjectiveGather:
+		return fmt.Sprintf("Gather %d %s", objective.Count, s.Catalog.ItemName(objective.Target))
+	case ObjectiveDefeat:
+		return fmt.Sprintf("Defeat %d %s", objective.Count, s.Catalog.MonsterName(objective.Target))
+	case ObjectiveReach:
+		return "Travel to " + s.Catalog.LocationName(objective.Target)
+	case ObjectiveDeliver:
+		return fmt.Sprintf("Deliver %d %s", objective.Count, s.Catalog.ItemName(objective.Target))
+	}
+	return string(objective.Kind)
+}
@@ -0,0 +1,484 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SaveVersion is the current save file format version. Bump it whenever a
+// change to saveFile cannot be read by older code, and teach migrateSave
+// how to upgrade the previous version.
+const SaveVersion = 3
+
+// DefaultSlot is the save slot used at startup and on quit
+const DefaultSlot = "default"
+
+const saveExt = ".json"
+
+var slotNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// ErrNoSave is returned when a save slot has no file yet
+var ErrNoSave = errors.New("no save in slot")
+
+// saveFile is the on-disk representation of a State
+type saveFile struct {
+	Version int           `json:"version"`
+	SavedAt time.Time     `json:"saved_at"`
+	State   stateSnapshot `json:"state"`
+}
+
+// stateSnapshot holds the persisted parts of State. New fields should be
+// optional so older saves keep loading.
+type stateSnapshot struct {
+	Player           *Player                         `json:"player,omitempty"`
+	Location         string                          `json:"location,omitempty"`
+	Items            []itemRecord                    `json:"items"`
+	ActivityLog      []LogEntry                      `json:"activity_log"`
+	SelectedCategory string                          `json:"selected_category"`
+	SavedSearches    []SavedSearch                   `json:"saved_searches"`
+	StorageSorts     map[string]ItemSort             `json:"storage_sorts,omitempty"`
+	StorageColumns   []string                        `json:"storage_columns,omitempty"`
+	Theme            string                          `json:"theme,omitempty"`
+	LogHidden        []string                        `json:"log_hidden,omitempty"`
+	Equipment        map[EquipSlot][]string          `json:"equipment,omitempty"` // Item IDs per slot
+	Market           *Market                         `json:"market,omitempty"`
+	Skills           map[SkillType]int               `json:"skills,omitempty"`
+	Actions          []Action                        `json:"actions,omitempty"`
+	Processing       []Action                        `json:"processing,omitempty"`
+	Encounter        *Encounter                      `json:"encounter,omitempty"`
+	Quests           map[string]*QuestProgress       `json:"quests,omitempty"`
+	Achievements     map[string]*AchievementProgress `json:"achievements,omitempty"`
+	Buffs            []Buff                          `json:"buffs,omitempty"`
+	Cooldowns        map[string]time.Duration        `json:"cooldowns,omitempty"`
+	Paused           bool                            `json:"paused,omitempty"`
+	Ticks            uint64                          `json:"ticks,omitempty"`
+	Seed             uint64                          `json:"seed,omitempty"`
+	Rolls            uint64                          `json:"rolls,omitempty"`
+}
+
+// SaveInfo describes a save slot on disk
+type SaveInfo struct {
+	Slot    string
+	SavedAt time.Time
+}
+
+// SaveDir returns the directory holding save files. TBRPG_SAVE_DIR
+// overrides the default location under the user config directory.
+func SaveDir() (string, error) {
+	if dir := os.Getenv("TBRPG_SAVE_DIR"); dir != "" {
+		return dir, nil
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locating save directory: %w", err)
+	}
+	return filepath.Join(configDir, "tbrpg", "saves"), nil
+}
+
+// SlotPath returns the save file path for a slot name
+func SlotPath(dir, slot string) (string, error) {
+	if !slotNamePattern.MatchString(slot) {
+		return "", fmt.Errorf("invalid slot name %q (use letters, digits, - and _)", slot)
+	}
+	return filepath.Join(dir, slot+saveExt), nil
+}
+
+// SaveSlot writes the state to the named slot in dir
+func (s *State) SaveSlot(dir, slot string) error {
+	path, err := SlotPath(dir, slot)
+	if err != nil {
+		return err
+	}
+	return s.Save(path)
+}
+
+// Save writes the state to path. The file is written to a temporary file
+// in the same directory and renamed into place, so an interrupted save
+// never leaves a partially written file behind.
+func (s *State) Save(path string) error {
+	now := time.Now()
+	data, err := json.MarshalIndent(saveFile{
+		Version: SaveVersion,
+		SavedAt: now,
+		State:   s.snapshot(),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding save: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating save directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating temp save: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing save: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("syncing save: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing save: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("replacing save: %w", err)
+	}
+
+	// Persist the rename itself; not all platforms support syncing directories
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+
+	s.LastSaved = now
+	return nil
+}
+
+// LoadSlot reads the named slot from dir
+func LoadSlot(dir, slot string, catalog *Catalog) (*State, error) {
+	path, err := SlotPath(dir, slot)
+	if err != nil {
+		return nil, err
+	}
+	return LoadState(path, catalog)
+}
+
+// LoadState reads a state previously written by Save. Items are resolved
+// against the catalog.
+func LoadState(path string, catalog *Catalog) (*State, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNoSave
+		}
+		return nil, fmt.Errorf("reading save: %w", err)
+	}
+
+	var file saveFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing save %s: %w", filepath.Base(path), err)
+	}
+
+	if err := migrateSave(&file, catalog); err != nil {
+		return nil, err
+	}
+
+	state, err := stateFromSnapshot(file.State, catalog)
+	if err != nil {
+		return nil, fmt.Errorf("loading save %s: %w", filepath.Base(path), err)
+	}
+	state.LastSaved = file.SavedAt
+	// Saves from before seeds were stored still replay the same way
+	if state.Seed == 0 {
+		state.Seed = uint64(file.SavedAt.UnixNano())
+	}
+	return state, nil
+}
+
+// ListSaves returns the save slots in dir, newest first
+func ListSaves(dir string) ([]SaveInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("listing saves: %w", err)
+	}
+
+	var saves []SaveInfo
+	for _, entry := range entries {
+		slot, ok := strings.CutSuffix(entry.Name(), saveExt)
+		if entry.IsDir() || !ok || !slotNamePattern.MatchString(slot) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		saves = append(saves, SaveInfo{Slot: slot, SavedAt: info.ModTime()})
+	}
+
+	sort.Slice(saves, func(i, j int) bool {
+		return saves[i].SavedAt.After(saves[j].SavedAt)
+	})
+	return saves, nil
+}
+
+// migrateSave upgrades older save files to the current version
+func migrateSave(file *saveFile, catalog *Catalog) error {
+	if file.Version < 1 {
+		return fmt.Errorf("unrecognized save version %d", file.Version)
+	}
+	if file.Version > SaveVersion {
+		return fmt.Errorf("save version %d is newer than this game supports (%d)", file.Version, SaveVersion)
+	}
+	// Version 1 stored full item copies; the id, quantity and equipped
+	// fields it shares with itemRecord are all version 2 needs
+
+	if file.Version < 3 {
+		// Version 2 kept equipped stacks in storage; move them to their slots
+		snap := &file.State
+		items := snap.Items[:0]
+		for _, record := range snap.Items {
+			def, ok := catalog.Item(record.ID)
+			if !record.Equipped || !ok || def.Slot == "" {
+				record.Equipped = false
+				items = append(items, record)
+				continue
+			}
+			if snap.Equipment == nil {
+				snap.Equipment = make(map[EquipSlot][]string)
+			}
+			slot := EquipSlot(def.Slot)
+			for range record.Quantity {
+				snap.Equipment[slot] = append(snap.Equipment[slot], record.ID)
+			}
+		}
+		snap.Items = items
+	}
+
+	file.Version = SaveVersion
+	return nil
+}
+
+func (s *State) snapshot() stateSnapshot {
+	items := make([]itemRecord, len(s.Storage.GetItems()))
+	for i, item := range s.Storage.GetItems() {
+		items[i] = itemRecord{ID: item.ID, Quantity: item.Quantity}
+	}
+
+	equipment := make(map[EquipSlot][]string)
+	for _, item := range s.Equipment.Items() {
+		slot := EquipSlot(item.Slot)
+		equipment[slot] = append(equipment[slot], item.ID)
+	}
+
+	return stateSnapshot{
+		Player:           s.Player,
+		Location:         s.Location,
+		Items:            items,
+		ActivityLog:      s.ActivityLog.GetRecentEntries(savedLogEntries),
+		SelectedCategory: s.SelectedCategory,
+		SavedSearches:    s.SavedSearches,
+		StorageSorts:     s.StorageSorts,
+		StorageColumns:   s.StorageColumns,
+		Theme:            s.Theme,
+		LogHidden:        s.LogHidden,
+		Equipment:        equipment,
+		Market:           s.Market,
+		Skills:           s.Skills,
+		Actions:          s.Actions,
+		Processing:       s.Processing,
+		Encounter:        s.Encounter,
+		Quests:           s.Quests,
+		Achievements:     s.Achievements,
+		Buffs:            s.Buffs,
+		Cooldowns:        s.Cooldowns,
+		Paused:           s.Clock.Paused,
+		Ticks:            s.Clock.Ticks,
+		Seed:             s.Seed,
+		Rolls:            s.Rolls,
+	}
+}
+
+func stateFromSnapshot(snap stateSnapshot, catalog *Catalog) (*State, error) {
+	// Stacks over their item's stack limit, e.g. after a content change,
+	// are split rather than lost. Items removed from content are dropped
+	// and reported once the state is loaded.
+	var dropped []string
+	storage := NewStorage(catalog, nil)
+	for _, record := range snap.Items {
+		def, ok := catalog.Item(record.ID)
+		if !ok {
+			dropped = append(dropped, fmt.Sprintf("%d %s", record.Quantity, record.ID))
+			continue
+		}
+		storage.items = append(storage.items, stacksOf(def, record.Quantity)...)
+	}
+
+	// Gear that no longer fits its slot, e.g. after a content change, goes
+	// back to storage rather than being lost
+	equipment := NewEquipment()
+	for _, ids := range snap.Equipment {
+		for _, id := range ids {
+			def, ok := catalog.Item(id)
+			if !ok {
+				dropped = append(dropped, "equipped "+id)
+				continue
+			}
+			var returned []*ItemDef
+			if canEquip(def) == nil {
+				returned = equipment.put(def)
+			} else {
+				returned = []*ItemDef{def}
+			}
+			for _, old := range returned {
+				if err := storage.restore(old.ID, 1); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if snap.SavedSearches == nil {
+		snap.SavedSearches = []SavedSearch{}
+	}
+	if snap.Skills == nil {
+		snap.Skills = make(map[SkillType]int)
+	}
+	// Sorts on unknown keys, e.g. from a newer version, are dropped
+	sorts := make(map[string]ItemSort)
+	for category, order := range snap.StorageSorts {
+		if key, err := ParseSortKey(string(order.Key)); err == nil && key != SortNone {
+			sorts[category] = order
+		}
+	}
+	// Progress for quests removed from content is dropped, and steps are
+	// clamped in case a quest lost objectives
+	quests := make(map[string]*QuestProgress)
+	for id, progress := range snap.Quests {
+		quest, ok := catalog.Quest(id)
+		if !ok || progress == nil {
+			continue
+		}
+		if progress.Step >= len(quest.Objectives) {
+			progress.Step, progress.Count = len(quest.Objectives)-1, 0
+		}
+		quests[id] = progress
+	}
+
+	// Progress on achievements removed from content is dropped
+	achievements := make(map[string]*AchievementProgress)
+	for id, progress := range snap.Achievements {
+		if _, ok := catalog.Achievement(id); ok && progress != nil {
+			achievements[id] = progress
+		}
+	}
+
+	// Buffs from items or on stats removed from content are dropped
+	var buffs []Buff
+	for _, buff := range snap.Buffs {
+		if _, ok := catalog.Item(buff.Item); ok && buffable(buff.Stat) && buff.Remaining > 0 {
+			buffs = append(buffs, buff)
+		}
+	}
+	if snap.Cooldowns == nil {
+		snap.Cooldowns = make(map[string]time.Duration)
+	}
+
+	if snap.Player == nil {
+		snap.Player = NewPlayer()
+	}
+	// Stock records for shops removed from content are dropped
+	market := NewMarket()
+	if snap.Market != nil {
+		market.History = snap.Market.History
+		for shopID, sold := range snap.Market.Sold {
+			if _, ok := catalog.Shop(shopID); ok && sold != nil {
+				market.Sold[shopID] = sold
+			}
+		}
+	}
+
+	if _, ok := catalog.Location(snap.Location); !ok {
+		snap.Location = catalog.StartLocation()
+	}
+	if snap.SelectedCategory == "" {
+		snap.SelectedCategory = AllItemsCategory
+	}
+
+	activityLog := NewActivityLog()
+	activityLog.entries = append(activityLog.entries, snap.ActivityLog...)
+	activityLog.trim()
+
+	// Actions on nodes, recipes, conversions or locations removed from
+	// content are dropped and reported like items
+	actions, droppedActions := knownActions(snap.Actions, catalog)
+	processing, droppedProcessing := knownActions(snap.Processing, catalog)
+	droppedActions = append(droppedActions, droppedProcessing...)
+
+	state := &State{
+		Catalog:          catalog,
+		Player:           snap.Player,
+		Location:         snap.Location,
+		Storage:          storage,
+		Equipment:        equipment,
+		Market:           market,
+		Events:           NewBus(),
+		ActivityLog:      activityLog,
+		SelectedCategory: snap.SelectedCategory,
+		SavedSearches:    snap.SavedSearches,
+		StorageSorts:     sorts,
+		StorageColumns:   snap.StorageColumns,
+		Theme:            snap.Theme,
+		LogHidden:        snap.LogHidden,
+		Skills:           snap.Skills,
+		Clock:            Clock{Paused: snap.Paused, Ticks: snap.Ticks},
+		Actions:          actions,
+		Processing:       processing,
+		Encounter:        snap.Encounter,
+		Quests:           quests,
+		Achievements:     achievements,
+		Buffs:            buffs,
+		Cooldowns:        snap.Cooldowns,
+		Seed:             snap.Seed,
+		Rolls:            snap.Rolls,
+		rng:              newRand(),
+	}
+	state.subscribe()
+	if len(dropped) > 0 {
+		state.Log("System", "Dropped items no longer in the game:", strings.Join(dropped, ", "))
+	}
+	if len(droppedActions) > 0 {
+		state.Log("System", "Dropped actions no longer in the game:", strings.Join(droppedActions, ", "))
+	}
+	if snap.Encounter != nil {
+		if _, ok := catalog.Monster(snap.Encounter.MonsterID); !ok {
+			state.Encounter = nil
+		}
+	}
+	state.clampVitals()
+	return state, nil
+}
+
+// knownActions splits saved actions into those whose target is still in
+// content and descriptions of the rest, like "travel removed_place"
+func knownActions(actions []Action, catalog *Catalog) ([]Action, []string) {
+	var kept []Action
+	var dropped []string
+	for _, action := range actions {
+		var ok bool
+		switch action.Kind {
+		case ActionGather:
+			_, ok = catalog.ResourceNode(action.Target)
+		case ActionCraft:
+			_, ok = catalog.Recipe(action.Target)
+		case ActionProcess:
+			_, ok = catalog.Conversion(action.Target)
+		case ActionTravel:
+			_, ok = catalog.Location(action.Target)
+		}
+		if !ok {
+			dropped = append(dropped, fmt.Sprintf("%s %s", action.Kind, action.Target))
+			continue
+		}
+		kept = append(kept, action)
+	}
+	return kept, dropped
+}
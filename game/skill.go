@@ -0,0 +1,105 @@
+package game
+
+import "math"
+
+// SkillType identifies a trainable skill
+type SkillType string
+
+const (
+	SkillWoodcutting SkillType = "Woodcutting"
+	SkillMining      SkillType = "Mining"
+	SkillFishing     SkillType = "Fishing"
+	SkillCrafting    SkillType = "Crafting"
+	SkillSmithing    SkillType = "Smithing"
+	SkillCooking     SkillType = "Cooking"
+)
+
+// MaxSkillLevel is the highest level a skill can reach
+const MaxSkillLevel = 99
+
+// AllSkills lists skills in display order
+var AllSkills = []SkillType{
+	SkillWoodcutting,
+	SkillMining,
+	SkillFishing,
+	SkillCrafting,
+	SkillSmithing,
+	SkillCooking,
+}
+
+// GatheringSkills lists the skills trained at resource nodes
+var GatheringSkills = []SkillType{
+	SkillWoodcutting,
+	SkillMining,
+	SkillFishing,
+}
+
+// isSkill reports whether s names a skill
+func isSkill(s SkillType) bool {
+	for _, skill := range AllSkills {
+		if s == skill {
+			return true
+		}
+	}
+	return false
+}
+
+// xpTable[level] is the total XP required to reach level
+var xpTable = buildXPTable()
+
+// buildXPTable computes the XP curve. Early levels come quickly and each
+// level costs roughly 10% more than the last.
+func buildXPTable() []int {
+	table := make([]int, MaxSkillLevel+1)
+	points := 0.0
+	for level := 2; level <= MaxSkillLevel; level++ {
+		n := float64(level - 1)
+		points += math.Floor(n + 300*math.Pow(2, n/7))
+		table[level] = int(points / 4)
+	}
+	return table
+}
+
+// XPForLevel returns the total XP required to reach a level
+func XPForLevel(level int) int {
+	if level <= 1 {
+		return 0
+	}
+	if level > MaxSkillLevel {
+		level = MaxSkillLevel
+	}
+	return xpTable[level]
+}
+
+// LevelForXP returns the level reached with the given total XP
+func LevelForXP(xp int) int {
+	level := 1
+	for level < MaxSkillLevel && xp >= xpTable[level+1] {
+		level++
+	}
+	return level
+}
+
+// SkillXP returns the total XP in a skill
+func (s *State) SkillXP(skill SkillType) int {
+	return s.Skills[skill]
+}
+
+// SkillLevel returns the current level of a skill
+func (s *State) SkillLevel(skill SkillType) int {
+	return LevelForXP(s.Skills[skill])
+}
+
+// AddXP grants XP in a skill, announces any level up, and returns the new
+// level
+func (s *State) AddXP(skill SkillType, amount int) int {
+	before := s.SkillLevel(skill)
+	s.Skills[skill] += amount
+	after := s.SkillLevel(skill)
+
+	s.Events.Publish(XPGained{Skill: skill, Amount: amount})
+	if after > before {
+		s.Events.Publish(LevelUp{Skill: skill, Level: after})
+	}
+	return after
+}
@@ -0,0 +1,86 @@
+package game
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SortKey is a field storage items can be sorted by
+type SortKey string
+
+const (
+	SortNone     SortKey = ""         // Storage order
+	SortName     SortKey = "name"     // Item name
+	SortQuantity SortKey = "quantity" // Stack size
+	SortValue    SortKey = "value"    // Unit value
+	SortTotal    SortKey = "total"    // Unit value times quantity
+	SortCategory SortKey = "category" // Item category, then name
+)
+
+// SortKeys lists every sort key except SortNone, in column order
+var SortKeys = []SortKey{SortName, SortQuantity, SortValue, SortTotal, SortCategory}
+
+// ItemSort is an order for a list of items. The zero value keeps storage
+// order.
+type ItemSort struct {
+	Key        SortKey `json:"key"`
+	Descending bool    `json:"descending,omitempty"`
+}
+
+// ParseSortKey checks that a sort key is known
+func ParseSortKey(key string) (SortKey, error) {
+	k := SortKey(strings.ToLower(key))
+	if k == SortNone || slices.Contains(SortKeys, k) {
+		return k, nil
+	}
+	return SortNone, fmt.Errorf("unknown sort key %q", key)
+}
+
+// SortItems orders items in place. Ties keep their storage order, so
+// stacks of one item stay together.
+func SortItems(items []Item, order ItemSort) {
+	if order.Key == SortNone {
+		return
+	}
+	slices.SortStableFunc(items, func(a, b Item) int {
+		c := compareItems(a, b, order.Key)
+		if order.Descending {
+			return -c
+		}
+		return c
+	})
+}
+
+// compareItems compares two items by one key
+func compareItems(a, b Item, key SortKey) int {
+	switch key {
+	case SortQuantity:
+		return cmp.Compare(a.Quantity, b.Quantity)
+	case SortValue:
+		return cmp.Compare(a.Value, b.Value)
+	case SortTotal:
+		return cmp.Compare(a.Value*a.Quantity, b.Value*b.Quantity)
+	case SortCategory:
+		if c := cmp.Compare(a.Category, b.Category); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+}
+
+// StorageSort returns the sort chosen for a storage category
+func (s *State) StorageSort(category string) ItemSort {
+	return s.StorageSorts[category]
+}
+
+// SetStorageSort remembers the sort for a storage category. The zero sort
+// forgets it.
+func (s *State) SetStorageSort(category string, order ItemSort) {
+	if order.Key == SortNone {
+		delete(s.StorageSorts, category)
+		return
+	}
+	s.StorageSorts[category] = order
+}
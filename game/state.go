@@ -1,85 +1,300 @@
 package game
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // State holds all game-related state
 type State struct {
+	Catalog          *Catalog
+	Player           *Player
+	Location         string // ID of the location the player is at
 	Storage          *Storage
+	Equipment        *Equipment
+	Market           *Market
+	Events           *Bus
 	ActivityLog      *ActivityLog
 	SelectedCategory string
 	SavedSearches    []SavedSearch
-	// Future: Player stats, quests, equipment, etc.
+	StorageSorts     map[string]ItemSort // Storage table sort per category
+	StorageColumns   []string            // Optional storage table columns shown
+	Theme            string              // UI color theme name, empty for the default
+	LogHidden        []string            // Activity log categories the log view hides
+	Skills           map[SkillType]int   // Total XP per skill
+	Clock            Clock
+	Seed             uint64                    // Seeds rolls that must replay the same from a save
+	Rolls            uint64                    // Rolls made from Seed so far
+	Actions          []Action                  // Timed action queue; the first entry is in progress
+	Processing       []Action                  // Station queue, run alongside Actions
+	Encounter        *Encounter                // Current or just finished fight, nil if none
+	Quests           map[string]*QuestProgress // Accepted and completed quests by ID
+	Achievements     map[string]*AchievementProgress
+	Buffs            []Buff                   // Active buffs from used items
+	Cooldowns        map[string]time.Duration // Time left per cooldown group
+	LastSaved        time.Time                // Zero until saved or loaded from disk
+
+	rng      *rand.Rand
+	recovery time.Duration // Time toward the next HP/MP regeneration
 }
 
 // SavedSearch represents a saved search query
 type SavedSearch struct {
-	Name  string
-	Query string
+	Name     string `json:"name"`
+	Query    string `json:"query"`
+	Category string `json:"category,omitempty"` // Empty means all items
 }
 
-// NewState creates a new game state with default values
-func NewState() *State {
-	// Initialize with sample items
-	storage := NewStorage(GetSampleItems())
-
-	// Initialize activity log with sample entries
-	activityLog := NewActivityLog()
-	activityLog.AddEntry("System", "Game started", "Welcome to TBRPG!")
-	activityLog.AddEntry("Navigation", "Traveled to Starting Town", "")
-	activityLog.AddEntry("Woodcutting", "+1 Oak Log", "(1,235 total) +12 XP")
-	activityLog.AddEntry("Combat", "Goblin defeated", "+Goblin Ear (12) +15 XP")
-	activityLog.AddEntry("Fishing", "+1 Raw Trout", "(58 total) +8 XP")
+// NewState creates a new game state with the catalog's starting inventory
+func NewState(catalog *Catalog) *State {
+	storage := NewStorage(catalog, nil)
+	equipment := NewEquipment()
+	for _, item := range catalog.StartingItems() {
+		if !item.Equipped {
+			storage.restore(item.ID, item.Quantity)
+			continue
+		}
+		for range item.Quantity {
+			for _, old := range equipment.put(item.ItemDef) {
+				storage.restore(old.ID, 1)
+			}
+		}
+	}
 
-	return &State{
+	s := &State{
+		Catalog:          catalog,
+		Player:           NewPlayer(),
+		Location:         catalog.StartLocation(),
 		Storage:          storage,
-		ActivityLog:      activityLog,
-		SelectedCategory: "All Items",
+		Equipment:        equipment,
+		Market:           NewMarket(),
+		Events:           NewBus(),
+		ActivityLog:      NewActivityLog(),
+		SelectedCategory: AllItemsCategory,
 		SavedSearches:    []SavedSearch{},
+		StorageSorts:     make(map[string]ItemSort),
+		Skills:           make(map[SkillType]int),
+		Quests:           make(map[string]*QuestProgress),
+		Achievements:     make(map[string]*AchievementProgress),
+		Cooldowns:        make(map[string]time.Duration),
+		Seed:             rand.Uint64(),
+		rng:              newRand(),
 	}
+	s.subscribe()
+
+	s.Log("System", "Game started", "Welcome to TBRPG!")
+	s.Log("Navigation", "Arrived in "+s.CurrentLocation().Name, "")
+	return s
+}
+
+// subscribe connects the game's own systems to the event bus. The UI adds
+// its own subscribers after the state is created.
+func (s *State) subscribe() {
+	s.logEvents()
+	s.trackQuests()
+	s.trackAchievements()
 }
 
-// GetFilteredItems returns items filtered by current category and search term
-func (s *State) GetFilteredItems(searchTerm string) []Item {
-	return s.Storage.Filter(FilterOptions{
-		SearchTerm:     searchTerm,
-		CategoryFilter: s.SelectedCategory,
+// Log publishes a message for the activity log
+func (s *State) Log(category, action, details string) {
+	s.Events.Publish(Message{Category: category, Action: action, Details: details})
+}
+
+// logEvents subscribes the activity log to the events it reports
+func (s *State) logEvents() {
+	On(s.Events, func(e Message) {
+		s.ActivityLog.AddEntry(e.Category, e.Action, e.Details)
+	})
+	On(s.Events, func(e LevelUp) {
+		if e.Skill == "" {
+			s.ActivityLog.AddEntry("Combat", "Level up!", fmt.Sprintf("Combat level %d", e.Level))
+			return
+		}
+		s.ActivityLog.AddEntry(string(e.Skill), "Level up!", fmt.Sprintf("%s is now level %d", e.Skill, e.Level))
+	})
+	On(s.Events, func(e AchievementUnlocked) {
+		if achievement, ok := s.Catalog.Achievement(e.Achievement); ok {
+			s.ActivityLog.AddEntry("Achievements", "Unlocked "+achievement.Name, s.DescribeAchievement(achievement))
+		}
 	})
 }
 
+// newRand returns a random source seeded from the current time
+func newRand() *rand.Rand {
+	seed := uint64(time.Now().UnixNano())
+	return rand.New(rand.NewPCG(seed, seed>>32))
+}
+
+// roll returns a number in [0, n) drawn from the saved seed. Each roll
+// advances the saved count, so replaying a save gives the same rolls.
+func (s *State) roll(n int) int {
+	s.Rolls++
+	return rand.New(rand.NewPCG(s.Seed, s.Rolls)).IntN(n)
+}
+
+// GetFilteredItems returns items filtered by current category and search
+// term. Worn equipment is included when the query asks about equipped state.
+func (s *State) GetFilteredItems(searchTerm string) ([]Item, error) {
+	query, err := ParseQuery(searchTerm)
+	if err != nil {
+		return nil, err
+	}
+	opts := FilterOptions{CategoryFilter: s.SelectedCategory}
+	items := s.Storage.FilterQuery(query, opts)
+	if query.UsesField("equipped") {
+		items = append(items, s.Storage.filter(s.Equipment.Items(), query, opts)...)
+	}
+	return items, nil
+}
+
 // SetCategory updates the selected category
 func (s *State) SetCategory(category string) {
 	s.SelectedCategory = category
 }
 
-// SaveSearch saves a search query with a name
-func (s *State) SaveSearch(name, query string) {
+// SaveSearch saves a search query and category under a unique name
+func (s *State) SaveSearch(name, query, category string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("search name cannot be empty")
+	}
+	if _, _, found := s.findSearchByName(name); found {
+		return fmt.Errorf("a saved search named %q already exists", name)
+	}
+
 	s.SavedSearches = append(s.SavedSearches, SavedSearch{
-		Name:  name,
-		Query: query,
+		Name:     name,
+		Query:    query,
+		Category: category,
 	})
+	return nil
 }
 
-// LoadSearch finds a saved search by name
-func (s *State) LoadSearch(name string) (string, bool) {
-	for _, saved := range s.SavedSearches {
-		if saved.Name == name {
-			return saved.Query, true
+// LoadSearch finds a saved search by name or by its 1-based position
+func (s *State) LoadSearch(ref string) (SavedSearch, error) {
+	i, err := s.searchIndex(ref)
+	if err != nil {
+		return SavedSearch{}, err
+	}
+	return s.LoadSearchAt(i)
+}
+
+// LoadSearchAt returns the saved search at a 0-based position. Lists of
+// saved searches use positions so a search named "1" is never mistaken
+// for the first one.
+func (s *State) LoadSearchAt(i int) (SavedSearch, error) {
+	if err := s.checkSearchAt(i); err != nil {
+		return SavedSearch{}, err
+	}
+	return s.SavedSearches[i], nil
+}
+
+// RenameSearch renames a saved search, keeping names unique
+func (s *State) RenameSearch(ref, newName string) error {
+	i, err := s.searchIndex(ref)
+	if err != nil {
+		return err
+	}
+	return s.RenameSearchAt(i, newName)
+}
+
+// RenameSearchAt renames the saved search at a 0-based position
+func (s *State) RenameSearchAt(i int, newName string) error {
+	if err := s.checkSearchAt(i); err != nil {
+		return err
+	}
+
+	newName = strings.TrimSpace(newName)
+	if newName == "" {
+		return errors.New("search name cannot be empty")
+	}
+	if _, j, found := s.findSearchByName(newName); found && j != i {
+		return fmt.Errorf("a saved search named %q already exists", newName)
+	}
+
+	s.SavedSearches[i].Name = newName
+	return nil
+}
+
+// DeleteSearch removes a saved search and returns it
+func (s *State) DeleteSearch(ref string) (SavedSearch, error) {
+	i, err := s.searchIndex(ref)
+	if err != nil {
+		return SavedSearch{}, err
+	}
+	return s.DeleteSearchAt(i)
+}
+
+// DeleteSearchAt removes the saved search at a 0-based position and
+// returns it
+func (s *State) DeleteSearchAt(i int) (SavedSearch, error) {
+	if err := s.checkSearchAt(i); err != nil {
+		return SavedSearch{}, err
+	}
+
+	removed := s.SavedSearches[i]
+	s.SavedSearches = append(s.SavedSearches[:i], s.SavedSearches[i+1:]...)
+	return removed, nil
+}
+
+// checkSearchAt reports a 0-based position with no saved search
+func (s *State) checkSearchAt(i int) error {
+	if i < 0 || i >= len(s.SavedSearches) {
+		return fmt.Errorf("no saved search #%d", i+1)
+	}
+	return nil
+}
+
+// searchIndex resolves a saved search reference (name or 1-based number)
+func (s *State) searchIndex(ref string) (int, error) {
+	ref = strings.TrimSpace(ref)
+	if _, i, found := s.findSearchByName(ref); found {
+		return i, nil
+	}
+	if n, err := strconv.Atoi(ref); err == nil {
+		if n < 1 || n > len(s.SavedSearches) {
+			return 0, fmt.Errorf("no saved search #%d", n)
+		}
+		return n - 1, nil
+	}
+	return 0, fmt.Errorf("no saved search named %q", ref)
+}
+
+// findSearchByName looks up a saved search by case-insensitive name
+func (s *State) findSearchByName(name string) (SavedSearch, int, bool) {
+	for i, saved := range s.SavedSearches {
+		if strings.EqualFold(saved.Name, name) {
+			return saved, i, true
 		}
 	}
-	return "", false
+	return SavedSearch{}, -1, false
 }
 
+// Log limits: the log keeps MaxLogEntries in memory and saves persist the
+// most recent savedLogEntries
+const (
+	MaxLogEntries   = 1000
+	savedLogEntries = 500
+)
+
 // ActivityLog manages the game's activity log
 type ActivityLog struct {
 	entries []LogEntry
+	version int // Bumped on every change, including trims
+	muted   bool
 }
 
 // LogEntry represents a single activity log entry
 type LogEntry struct {
-	Timestamp time.Time
-	Category  string
-	Action    string
-	Details   string
+	Timestamp time.Time `json:"timestamp"`
+	Category  string    `json:"category"`
+	Action    string    `json:"action"`
+	Details   string    `json:"details,omitempty"`
 }
 
 // NewActivityLog creates a new activity log
@@ -91,6 +306,9 @@ func NewActivityLog() *ActivityLog {
 
 // AddEntry adds a new entry to the log
 func (al *ActivityLog) AddEntry(category, action, details string) {
+	if al.muted {
+		return
+	}
 	entry := LogEntry{
 		Timestamp: time.Now(),
 		Category:  category,
@@ -98,6 +316,27 @@ func (al *ActivityLog) AddEntry(category, action, details string) {
 		Details:   details,
 	}
 	al.entries = append(al.entries, entry)
+	al.version++
+	al.trim()
+}
+
+// trim drops the oldest entries beyond MaxLogEntries
+func (al *ActivityLog) trim() {
+	if extra := len(al.entries) - MaxLogEntries; extra > 0 {
+		al.entries = slices.Delete(al.entries, 0, extra)
+	}
+}
+
+// Version changes whenever the log does. Unlike the number of entries it
+// keeps changing once the log is full.
+func (al *ActivityLog) Version() int {
+	return al.version
+}
+
+// mute discards new entries until the returned function is called
+func (al *ActivityLog) mute() (restore func()) {
+	al.muted = true
+	return func() { al.muted = false }
 }
 
 // GetEntries returns all log entries
@@ -116,4 +355,5 @@ func (al *ActivityLog) GetRecentEntries(n int) []LogEntry {
 // Clear removes all log entries
 func (al *ActivityLog) Clear() {
 	al.entries = []LogEntry{}
+	al.version++
 }
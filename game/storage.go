@@ -1,55 +1,149 @@
 package game
 
-import "strings"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
 
-// Storage manages items and provides search/filter functionality
+var (
+	// ErrNotEnough is wrapped by errors for taking more than storage holds
+	ErrNotEnough = errors.New("not enough in storage")
+	// ErrStorageFull is wrapped by errors for adding more than storage fits
+	ErrStorageFull = errors.New("storage is full")
+)
+
+// ShortageError reports taking more of an item than storage holds
+type ShortageError struct {
+	Item      *ItemDef
+	Requested int
+	Held      int
+}
+
+func (e *ShortageError) Error() string {
+	if e.Held == 0 {
+		return fmt.Sprintf("no %s in storage", e.Item.Name)
+	}
+	return fmt.Sprintf("only %d %s in storage", e.Held, e.Item.Name)
+}
+
+func (e *ShortageError) Unwrap() error {
+	return ErrNotEnough
+}
+
+// CapacityLimit names the storage limit an addition ran into
+type CapacityLimit string
+
+const (
+	LimitSlots  CapacityLimit = "slots"
+	LimitWeight CapacityLimit = "weight"
+)
+
+// OverflowError reports adding more of an item than storage has room for
+type OverflowError struct {
+	Item     *ItemDef
+	Quantity int
+	Room     int // How many would fit
+	Limit    CapacityLimit
+}
+
+func (e *OverflowError) Error() string {
+	if e.Limit == LimitWeight {
+		return fmt.Sprintf("%d %s would go over the storage weight limit (room for %d)", e.Quantity, e.Item.Name, e.Room)
+	}
+	return fmt.Sprintf("no free storage slots for %d %s (room for %d)", e.Quantity, e.Item.Name, e.Room)
+}
+
+func (e *OverflowError) Unwrap() error {
+	return ErrStorageFull
+}
+
+// Storage manages items and provides search/filter functionality. Items
+// are held in stacks of at most their definition's stack limit, and every
+// stack takes one slot.
 type Storage struct {
-	items []Item
+	catalog  *Catalog
+	items    []Item
+	Capacity Capacity
+}
+
+// Capacity limits what storage can hold. Zero means no limit.
+type Capacity struct {
+	Slots  int // Stacks held at once
+	Weight int // Total weight of every item
 }
 
-// NewStorage creates a new storage instance
-func NewStorage(items []Item) *Storage {
+// DefaultCapacity is the capacity of the player's storage
+var DefaultCapacity = Capacity{Slots: 60, Weight: 10000}
+
+// NewStorage creates a storage instance holding the given stacks
+func NewStorage(catalog *Catalog, items []Item) *Storage {
 	return &Storage{
-		items: items,
+		catalog:  catalog,
+		items:    items,
+		Capacity: DefaultCapacity,
 	}
 }
 
-// GetItems returns all items in storage
+// GetItems returns every stack in storage
 func (s *Storage) GetItems() []Item {
 	return s.items
 }
 
-// SetItems updates the storage items
-func (s *Storage) SetItems(items []Item) {
-	s.items = items
+// Totals returns one entry per item with the quantity of all its stacks
+// combined, in the order the items were first stored
+func (s *Storage) Totals() []Item {
+	var totals []Item
+	index := make(map[string]int)
+	for _, item := range s.items {
+		if i, ok := index[item.ID]; ok {
+			totals[i].Quantity += item.Quantity
+			continue
+		}
+		index[item.ID] = len(totals)
+		totals = append(totals, item)
+	}
+	return totals
 }
 
 // FilterOptions contains criteria for filtering items
 type FilterOptions struct {
-	SearchTerm       string
-	CategoryFilter   string
-	IncludeEquipped  bool
-	TagFilter        []string
+	SearchTerm     string // Query string, see Query for the syntax
+	CategoryFilter string
+	TagFilter      []string
+}
+
+// Filter returns items matching the given criteria. An error is returned
+// if the search term is not a valid query.
+func (s *Storage) Filter(opts FilterOptions) ([]Item, error) {
+	query, err := ParseQuery(opts.SearchTerm)
+	if err != nil {
+		return nil, err
+	}
+	return s.FilterQuery(query, opts), nil
+}
+
+// FilterQuery returns items matching an already compiled query and the
+// remaining criteria in opts. opts.SearchTerm is ignored.
+func (s *Storage) FilterQuery(query Query, opts FilterOptions) []Item {
+	return s.filter(s.items, query, opts)
 }
 
-// Filter returns items matching the given criteria
-func (s *Storage) Filter(opts FilterOptions) []Item {
+// filter applies a query and filter options to any list of items
+func (s *Storage) filter(items []Item, query Query, opts FilterOptions) []Item {
 	filtered := []Item{}
-	searchTerm := strings.ToLower(opts.SearchTerm)
 
-	for _, item := range s.items {
+	for _, item := range items {
 		// Category filter
-		if opts.CategoryFilter != "" && opts.CategoryFilter != "All Items" {
-			if !matchesCategory(item, opts.CategoryFilter) {
-				continue
-			}
+		if !s.catalog.InCategory(item, opts.CategoryFilter) {
+			continue
 		}
 
-		// Search filter (by name or tags)
-		if searchTerm != "" {
-			if !matchesSearch(item, searchTerm) {
-				continue
-			}
+		// Search query
+		if !query.Match(item) {
+			continue
 		}
 
 		// Tag filter
@@ -59,103 +153,356 @@ func (s *Storage) Filter(opts FilterOptions) []Item {
 			}
 		}
 
-		// Equipped filter
-		if !opts.IncludeEquipped && item.Equipped {
-			continue
-		}
-
 		filtered = append(filtered, item)
 	}
 
 	return filtered
 }
 
-// SearchByName returns items matching the search term in their name
+// SearchByName returns items whose name contains the search term
 func (s *Storage) SearchByName(searchTerm string) []Item {
-	return s.Filter(FilterOptions{
-		SearchTerm: searchTerm,
-	})
+	query := Query{root: fieldNode{field: "name", pattern: strings.ToLower(searchTerm)}}
+	return s.FilterQuery(query, FilterOptions{})
 }
 
 // GetByCategory returns all items in a specific category
 func (s *Storage) GetByCategory(category string) []Item {
-	return s.Filter(FilterOptions{
+	return s.FilterQuery(Query{}, FilterOptions{
 		CategoryFilter: category,
 	})
 }
 
 // GetByTag returns all items with the specified tag
 func (s *Storage) GetByTag(tag string) []Item {
-	return s.Filter(FilterOptions{
+	return s.FilterQuery(Query{}, FilterOptions{
 		TagFilter: []string{tag},
 	})
 }
 
-// FindByID returns an item by its ID, or nil if not found
-func (s *Storage) FindByID(id string) *Item {
-	for i, item := range s.items {
-		if item.ID == id {
-			return &s.items[i]
+// Find returns the first stack of an item
+func (s *Storage) Find(itemID string) (Item, bool) {
+	for _, item := range s.items {
+		if item.ID == itemID {
+			return item, true
 		}
 	}
-	return nil
+	return Item{}, false
 }
 
-// CountByCategory returns the number of items in each category
-func (s *Storage) CountByCategory() map[string]int {
-	counts := make(map[string]int)
+// Quantity returns how many of an item are in storage
+func (s *Storage) Quantity(itemID string) int {
+	total := 0
 	for _, item := range s.items {
-		counts[item.Category]++
+		if item.ID == itemID {
+			total += item.Quantity
+		}
 	}
-	return counts
+	return total
 }
 
-// TotalValue calculates the total value of all items
-func (s *Storage) TotalValue() int {
+// UsedSlots returns how many slots are taken
+func (s *Storage) UsedSlots() int {
+	return len(s.items)
+}
+
+// Weight returns the total weight of everything in storage
+func (s *Storage) Weight() int {
 	total := 0
 	for _, item := range s.items {
-		total += item.Value * item.Quantity
+		total += item.Weight * item.Quantity
 	}
 	return total
 }
 
-// Helper functions
+// Room returns how many more of an item fit in storage
+func (s *Storage) Room(itemID string) int {
+	def, ok := s.catalog.Item(itemID)
+	if !ok {
+		return 0
+	}
+	room := math.MaxInt
+	if s.Capacity.Slots > 0 {
+		free := max(0, s.Capacity.Slots-len(s.items))
+		switch {
+		case def.StackLimit == 0 && (free > 0 || s.Quantity(itemID) > 0):
+			// One unlimited stack takes everything
+		case def.StackLimit == 0:
+			room = 0
+		default:
+			room = s.stackRoom(def) + free*def.StackLimit
+		}
+	}
+	if s.Capacity.Weight > 0 && def.Weight > 0 {
+		room = min(room, max(0, s.Capacity.Weight-s.Weight())/def.Weight)
+	}
+	return room
+}
+
+// CanAdd reports why qty of an item would not fit, if it would not
+func (s *Storage) CanAdd(itemID string, qty int) error {
+	def, err := s.lookup(itemID, qty)
+	if err != nil {
+		return err
+	}
+	return s.checkRoom(def, qty)
+}
 
-// matchesCategory checks if an item matches the category filter
-func matchesCategory(item Item, category string) bool {
-	// Direct category match
-	if item.Category == category {
-		return true
+// Add puts qty of an item into storage, topping up existing stacks before
+// starting new ones, and returns the total held. Nothing is added if it
+// does not all fit.
+func (s *Storage) Add(itemID string, qty int) (int, error) {
+	def, err := s.lookup(itemID, qty)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.checkRoom(def, qty); err != nil {
+		return 0, err
+	}
+	s.place(def, qty)
+	return s.Quantity(itemID), nil
+}
+
+// Remove takes qty of an item out of storage, emptying the smallest stacks
+// first. Nothing is removed if storage holds fewer.
+func (s *Storage) Remove(itemID string, qty int) error {
+	def, err := s.lookup(itemID, qty)
+	if err != nil {
+		return err
+	}
+	if held := s.Quantity(itemID); held < qty {
+		return &ShortageError{Item: def, Requested: qty, Held: held}
 	}
 
-	// Check if any tags contain the category (case-insensitive)
-	categoryLower := strings.ToLower(category)
-	for _, tag := range item.Tags {
-		if strings.Contains(strings.ToLower(tag), categoryLower) {
-			return true
+	for qty > 0 {
+		smallest := -1
+		for i, item := range s.items {
+			if item.ID == itemID && (smallest < 0 || item.Quantity < s.items[smallest].Quantity) {
+				smallest = i
+			}
+		}
+		taken := min(qty, s.items[smallest].Quantity)
+		s.items[smallest].Quantity -= taken
+		qty -= taken
+		if s.items[smallest].Quantity == 0 {
+			s.items = slices.Delete(s.items, smallest, smallest+1)
 		}
 	}
+	return nil
+}
 
-	return false
+// Transfer moves qty of an item to another storage. Nothing moves unless
+// this storage holds enough and the other has room.
+func (s *Storage) Transfer(to *Storage, itemID string, qty int) error {
+	def, err := s.lookup(itemID, qty)
+	if err != nil {
+		return err
+	}
+	if held := s.Quantity(itemID); held < qty {
+		return &ShortageError{Item: def, Requested: qty, Held: held}
+	}
+	if err := to.checkRoom(def, qty); err != nil {
+		return err
+	}
+	if err := s.Remove(itemID, qty); err != nil {
+		return err
+	}
+	to.place(def, qty)
+	return nil
 }
 
-// matchesSearch checks if an item matches the search term
-func matchesSearch(item Item, searchTerm string) bool {
-	// Check name
-	if strings.Contains(strings.ToLower(item.Name), searchTerm) {
-		return true
+// Exchange removes and adds items as one change: either every removal and
+// addition happens or none do. Removals are made first, so the space they
+// free counts toward the additions.
+func (s *Storage) Exchange(remove, add []ItemAmount) error {
+	trial := &Storage{catalog: s.catalog, items: slices.Clone(s.items), Capacity: s.Capacity}
+	for _, item := range remove {
+		if err := trial.Remove(item.Item, item.Quantity); err != nil {
+			return err
+		}
 	}
+	for _, item := range add {
+		if _, err := trial.Add(item.Item, item.Quantity); err != nil {
+			return err
+		}
+	}
+	s.items = trial.items
+	return nil
+}
 
-	// Check tags
-	for _, tag := range item.Tags {
-		if strings.Contains(strings.ToLower(tag), searchTerm) {
-			return true
+// Split moves qty of an item off its largest stack into a new stack
+func (s *Storage) Split(itemID string, qty int) error {
+	def, err := s.lookup(itemID, qty)
+	if err != nil {
+		return err
+	}
+	largest := -1
+	for i, item := range s.items {
+		if item.ID == itemID && (largest < 0 || item.Quantity > s.items[largest].Quantity) {
+			largest = i
 		}
 	}
+	if largest < 0 {
+		return &ShortageError{Item: def, Requested: qty}
+	}
+	if s.items[largest].Quantity <= qty {
+		return fmt.Errorf("cannot split %d from a stack of %d %s", qty, s.items[largest].Quantity, def.Name)
+	}
+	if s.Capacity.Slots > 0 && len(s.items) >= s.Capacity.Slots {
+		return &OverflowError{Item: def, Quantity: qty, Limit: LimitSlots}
+	}
 
-	return false
+	s.items[largest].Quantity -= qty
+	s.items = slices.Insert(s.items, largest+1, Item{ItemDef: def, Quantity: qty})
+	return nil
+}
+
+// Merge combines the stacks of an item into as few as its stack limit
+// allows, where the first stack was
+func (s *Storage) Merge(itemID string) error {
+	def, ok := s.catalog.Item(itemID)
+	if !ok {
+		return fmt.Errorf("unknown item %q", itemID)
+	}
+	first, total := -1, 0
+	kept := s.items[:0]
+	for _, item := range s.items {
+		if item.ID != itemID {
+			kept = append(kept, item)
+			continue
+		}
+		if first < 0 {
+			first = len(kept)
+		}
+		total += item.Quantity
+	}
+	if first < 0 {
+		return &ShortageError{Item: def, Requested: 1}
+	}
+	s.items = slices.Insert(kept, first, stacksOf(def, total)...)
+	return nil
+}
+
+// restore puts items back without checking capacity, so loading a save or
+// returning worn gear never loses anything. Storage may end up over
+// capacity until the player makes room.
+func (s *Storage) restore(itemID string, qty int) error {
+	def, err := s.lookup(itemID, qty)
+	if err != nil {
+		return err
+	}
+	s.place(def, qty)
+	return nil
+}
+
+// lookup finds an item's definition and checks the quantity is positive
+func (s *Storage) lookup(itemID string, qty int) (*ItemDef, error) {
+	def, ok := s.catalog.Item(itemID)
+	if !ok {
+		return nil, fmt.Errorf("unknown item %q", itemID)
+	}
+	if qty < 1 {
+		return nil, errors.New("quantity must be positive")
+	}
+	return def, nil
+}
+
+// checkRoom returns an OverflowError if qty of an item does not fit
+func (s *Storage) checkRoom(def *ItemDef, qty int) error {
+	if s.Capacity.Slots > 0 && len(s.items)+s.newStacks(def, qty) > s.Capacity.Slots {
+		return &OverflowError{Item: def, Quantity: qty, Room: s.Room(def.ID), Limit: LimitSlots}
+	}
+	if s.Capacity.Weight > 0 && s.Weight()+def.Weight*qty > s.Capacity.Weight {
+		return &OverflowError{Item: def, Quantity: qty, Room: s.Room(def.ID), Limit: LimitWeight}
+	}
+	return nil
+}
+
+// stackRoom returns how many more of an item its existing stacks can take
+func (s *Storage) stackRoom(def *ItemDef) int {
+	room := 0
+	for _, item := range s.items {
+		if item.ID == def.ID {
+			room += max(0, def.StackLimit-item.Quantity)
+		}
+	}
+	return room
+}
+
+// newStacks returns how many stacks adding qty of an item would start
+func (s *Storage) newStacks(def *ItemDef, qty int) int {
+	if def.StackLimit == 0 {
+		if s.Quantity(def.ID) > 0 {
+			return 0
+		}
+		return 1
+	}
+	over := qty - s.stackRoom(def)
+	if over <= 0 {
+		return 0
+	}
+	return (over + def.StackLimit - 1) / def.StackLimit
+}
+
+// place tops up existing stacks of an item and appends new ones for the
+// rest, without checking capacity
+func (s *Storage) place(def *ItemDef, qty int) {
+	for i := range s.items {
+		if qty == 0 {
+			return
+		}
+		if s.items[i].ID != def.ID {
+			continue
+		}
+		added := qty
+		if def.StackLimit > 0 {
+			added = min(qty, max(0, def.StackLimit-s.items[i].Quantity))
+		}
+		s.items[i].Quantity += added
+		qty -= added
+	}
+	s.items = append(s.items, stacksOf(def, qty)...)
+}
+
+// stacksOf splits qty of an item into full stacks and a remainder
+func stacksOf(def *ItemDef, qty int) []Item {
+	var stacks []Item
+	for qty > 0 {
+		n := qty
+		if def.StackLimit > 0 {
+			n = min(qty, def.StackLimit)
+		}
+		stacks = append(stacks, Item{ItemDef: def, Quantity: n})
+		qty -= n
+	}
+	return stacks
 }
 
+// CountByCategory returns the number of stacks in each node of the category
+// tree, including AllItemsCategory
+func (s *Storage) CountByCategory() map[string]int {
+	names := s.catalog.CategoryNames()
+	counts := make(map[string]int, len(names))
+	for _, item := range s.items {
+		for _, name := range names {
+			if s.catalog.InCategory(item, name) {
+				counts[name]++
+			}
+		}
+	}
+	return counts
+}
+
+// TotalValue calculates the total value of all items
+func (s *Storage) TotalValue() int {
+	total := 0
+	for _, item := range s.items {
+		total += item.Value * item.Quantity
+	}
+	return total
+}
+
+// Helper functions
+
 // hasAnyTag checks if an item has any of the specified tags
 func hasAnyTag(item Item, tags []string) bool {
 	for _, filterTag := range tags {
@@ -0,0 +1,322 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// testItems are small definitions with easy limits: ore stacks to 10 and
+// weighs 2, gems stack without limit and weigh nothing, anvils don't stack
+var testItems = []*ItemDef{
+	{ID: "ore", Name: "Ore", StackLimit: 10, Weight: 2},
+	{ID: "gem", Name: "Gem"},
+	{ID: "anvil", Name: "Anvil", StackLimit: 1, Weight: 50},
+}
+
+// newTestStorage returns storage with the given capacity holding stacks
+// written like "ore:10"
+func newTestStorage(t *testing.T, capacity Capacity, stacks ...string) *Storage {
+	t.Helper()
+	catalog := &Catalog{items: make(map[string]*ItemDef)}
+	for _, def := range testItems {
+		catalog.items[def.ID] = def
+	}
+	s := NewStorage(catalog, nil)
+	s.Capacity = capacity
+	for _, stack := range stacks {
+		id, count, _ := strings.Cut(stack, ":")
+		qty, err := strconv.Atoi(count)
+		def, ok := catalog.Item(id)
+		if err != nil || !ok {
+			t.Fatalf("bad stack %q", stack)
+		}
+		s.items = append(s.items, Item{ItemDef: def, Quantity: qty})
+	}
+	return s
+}
+
+// stacks lists storage contents like "ore:10"
+func stacks(s *Storage) []string {
+	out := []string{}
+	for _, item := range s.items {
+		out = append(out, fmt.Sprintf("%s:%d", item.ID, item.Quantity))
+	}
+	return out
+}
+
+// checkOverflow fails unless err is an OverflowError for limit with room
+func checkOverflow(t *testing.T, err error, limit CapacityLimit, room int) {
+	t.Helper()
+	var overflow *OverflowError
+	if !errors.As(err, &overflow) {
+		t.Fatalf("got error %v, want an OverflowError", err)
+	}
+	if overflow.Limit != limit || overflow.Room != room {
+		t.Errorf("overflow limit %s room %d, want %s room %d", overflow.Limit, overflow.Room, limit, room)
+	}
+	if !errors.Is(err, ErrStorageFull) {
+		t.Error("overflow does not wrap ErrStorageFull")
+	}
+}
+
+// checkShortage fails unless err is a ShortageError with held items
+func checkShortage(t *testing.T, err error, requested, held int) {
+	t.Helper()
+	var shortage *ShortageError
+	if !errors.As(err, &shortage) {
+		t.Fatalf("got error %v, want a ShortageError", err)
+	}
+	if shortage.Requested != requested || shortage.Held != held {
+		t.Errorf("shortage requested %d held %d, want %d held %d", shortage.Requested, shortage.Held, requested, held)
+	}
+	if !errors.Is(err, ErrNotEnough) {
+		t.Error("shortage does not wrap ErrNotEnough")
+	}
+}
+
+func TestStorageAdd(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity Capacity
+		stacks   []string
+		item     string
+		qty      int
+		want     []string
+		limit    CapacityLimit // Expected overflow, if any
+		room     int
+	}{
+		{name: "spills into new stacks", item: "ore", qty: 25, want: []string{"ore:10", "ore:10", "ore:5"}},
+		{name: "tops up before spilling", stacks: []string{"ore:7", "gem:1"}, item: "ore", qty: 5, want: []string{"ore:10", "gem:1", "ore:2"}},
+		{name: "unlimited stack", stacks: []string{"gem:500"}, item: "gem", qty: 500, want: []string{"gem:1000"}},
+		{name: "fills the last slot", capacity: Capacity{Slots: 2}, stacks: []string{"ore:5"}, item: "ore", qty: 15, want: []string{"ore:10", "ore:10"}},
+		{name: "slot overflow", capacity: Capacity{Slots: 2}, stacks: []string{"ore:10"}, item: "ore", qty: 15, limit: LimitSlots, room: 10},
+		{name: "unlimited stack without a slot", capacity: Capacity{Slots: 1}, stacks: []string{"anvil:1"}, item: "gem", qty: 1, limit: LimitSlots, room: 0},
+		{name: "weight overflow", capacity: Capacity{Weight: 30}, item: "ore", qty: 16, limit: LimitWeight, room: 15},
+		{name: "weight counts what is held", capacity: Capacity{Weight: 60}, stacks: []string{"anvil:1"}, item: "ore", qty: 6, limit: LimitWeight, room: 5},
+		{name: "weightless items ignore weight", capacity: Capacity{Weight: 1}, item: "gem", qty: 99, want: []string{"gem:99"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStorage(t, tt.capacity, tt.stacks...)
+			before := stacks(s)
+			total, err := s.Add(tt.item, tt.qty)
+			if tt.limit != "" {
+				checkOverflow(t, err, tt.limit, tt.room)
+				if got := stacks(s); !slices.Equal(got, before) {
+					t.Errorf("failed add changed storage to %v", got)
+				}
+				if room := s.Room(tt.item); room != tt.room {
+					t.Errorf("Room = %d, want %d", room, tt.room)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := stacks(s); !slices.Equal(got, tt.want) {
+				t.Errorf("stacks %v, want %v", got, tt.want)
+			}
+			if total != s.Quantity(tt.item) {
+				t.Errorf("returned total %d, holding %d", total, s.Quantity(tt.item))
+			}
+		})
+	}
+}
+
+func TestStorageAddRejectsBadInput(t *testing.T) {
+	s := newTestStorage(t, Capacity{})
+	if _, err := s.Add("ore", 0); err == nil {
+		t.Error("added 0 ore")
+	}
+	if _, err := s.Add("ore", -1); err == nil {
+		t.Error("added -1 ore")
+	}
+	if _, err := s.Add("mithril", 1); err == nil {
+		t.Error("added an unknown item")
+	}
+}
+
+func TestStorageRemove(t *testing.T) {
+	tests := []struct {
+		name     string
+		stacks   []string
+		qty      int
+		want     []string
+		shortage bool
+	}{
+		{name: "smallest stack first", stacks: []string{"ore:10", "ore:3", "ore:6"}, qty: 5, want: []string{"ore:10", "ore:4"}},
+		{name: "within one stack", stacks: []string{"ore:10", "ore:6"}, qty: 2, want: []string{"ore:10", "ore:4"}},
+		{name: "everything", stacks: []string{"ore:10", "gem:1", "ore:6"}, qty: 16, want: []string{"gem:1"}},
+		{name: "more than held", stacks: []string{"ore:3", "ore:1"}, qty: 5, shortage: true},
+		{name: "none held", stacks: []string{"gem:1"}, qty: 1, shortage: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStorage(t, Capacity{}, tt.stacks...)
+			before := stacks(s)
+			held := s.Quantity("ore")
+			err := s.Remove("ore", tt.qty)
+			if tt.shortage {
+				checkShortage(t, err, tt.qty, held)
+				if got := stacks(s); !slices.Equal(got, before) {
+					t.Errorf("failed remove changed storage to %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := stacks(s); !slices.Equal(got, tt.want) {
+				t.Errorf("stacks %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStorageExchange(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity Capacity
+		stacks   []string
+		remove   []ItemAmount
+		add      []ItemAmount
+		want     []string // nil when the exchange fails
+	}{
+		{
+			name:     "removals free room for additions",
+			capacity: Capacity{Slots: 1},
+			stacks:   []string{"anvil:1"},
+			remove:   []ItemAmount{{Item: "anvil", Quantity: 1}},
+			add:      []ItemAmount{{Item: "ore", Quantity: 5}},
+			want:     []string{"ore:5"},
+		},
+		{
+			name:     "removals free weight for additions",
+			capacity: Capacity{Weight: 50},
+			stacks:   []string{"anvil:1"},
+			remove:   []ItemAmount{{Item: "anvil", Quantity: 1}},
+			add:      []ItemAmount{{Item: "ore", Quantity: 25}},
+			want:     []string{"ore:10", "ore:10", "ore:5"},
+		},
+		{
+			name:     "failed addition rolls back removals",
+			capacity: Capacity{Slots: 2},
+			stacks:   []string{"anvil:1", "ore:10"},
+			remove:   []ItemAmount{{Item: "ore", Quantity: 10}},
+			add:      []ItemAmount{{Item: "gem", Quantity: 1}, {Item: "ore", Quantity: 25}},
+		},
+		{
+			name:   "failed removal rolls back earlier removals",
+			stacks: []string{"ore:10", "ore:4"},
+			remove: []ItemAmount{{Item: "ore", Quantity: 5}, {Item: "gem", Quantity: 1}},
+			add:    []ItemAmount{{Item: "anvil", Quantity: 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStorage(t, tt.capacity, tt.stacks...)
+			before := stacks(s)
+			err := s.Exchange(tt.remove, tt.add)
+			if tt.want == nil {
+				if err == nil {
+					t.Fatal("exchange succeeded")
+				}
+				if got := stacks(s); !slices.Equal(got, before) {
+					t.Errorf("failed exchange changed storage to %v, want %v", got, before)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := stacks(s); !slices.Equal(got, tt.want) {
+				t.Errorf("stacks %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStorageSplit(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity Capacity
+		stacks   []string
+		qty      int
+		want     []string // nil when the split fails
+	}{
+		{name: "off the largest stack", stacks: []string{"ore:3", "ore:8"}, qty: 2, want: []string{"ore:3", "ore:6", "ore:2"}},
+		{name: "single stack", stacks: []string{"ore:10"}, qty: 9, want: []string{"ore:1", "ore:9"}},
+		{name: "zero", stacks: []string{"ore:10"}, qty: 0},
+		{name: "whole stack", stacks: []string{"ore:10"}, qty: 10},
+		{name: "more than the stack", stacks: []string{"ore:4", "ore:4"}, qty: 6},
+		{name: "none held", stacks: []string{"gem:5"}, qty: 1},
+		{name: "no free slot", capacity: Capacity{Slots: 1}, stacks: []string{"ore:10"}, qty: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStorage(t, tt.capacity, tt.stacks...)
+			before := stacks(s)
+			err := s.Split("ore", tt.qty)
+			if tt.want == nil {
+				if err == nil {
+					t.Fatal("split succeeded")
+				}
+				if got := stacks(s); !slices.Equal(got, before) {
+					t.Errorf("failed split changed storage to %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := stacks(s); !slices.Equal(got, tt.want) {
+				t.Errorf("stacks %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStorageSplitErrors(t *testing.T) {
+	s := newTestStorage(t, Capacity{Slots: 2}, "gem:5", "ore:10")
+	checkShortage(t, newTestStorage(t, Capacity{}, "gem:5").Split("ore", 1), 1, 0)
+	checkOverflow(t, s.Split("ore", 5), LimitSlots, 0)
+}
+
+func TestStorageMerge(t *testing.T) {
+	tests := []struct {
+		name   string
+		stacks []string
+		want   []string
+	}{
+		{name: "where the first stack was", stacks: []string{"gem:1", "ore:3", "anvil:1", "ore:4"}, want: []string{"gem:1", "ore:7", "anvil:1"}},
+		{name: "respects the stack limit", stacks: []string{"ore:8", "ore:9", "ore:2"}, want: []string{"ore:10", "ore:9"}},
+		{name: "single stack", stacks: []string{"ore:6"}, want: []string{"ore:6"}},
+		{name: "full stacks", stacks: []string{"ore:10", "ore:10"}, want: []string{"ore:10", "ore:10"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStorage(t, Capacity{}, tt.stacks...)
+			if err := s.Merge("ore"); err != nil {
+				t.Fatal(err)
+			}
+			if got := stacks(s); !slices.Equal(got, tt.want) {
+				t.Errorf("stacks %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStorageMergeErrors(t *testing.T) {
+	s := newTestStorage(t, Capacity{}, "gem:5")
+	checkShortage(t, s.Merge("ore"), 1, 0)
+	if err := s.Merge("mithril"); err == nil {
+		t.Error("merged an unknown item")
+	}
+	if got := stacks(s); !slices.Equal(got, []string{"gem:5"}) {
+		t.Errorf("failed merge changed storage to %v", got)
+	}
+}
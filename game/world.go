@@ -0,0 +1,216 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// LocationKind is what sort of place a location is
+type LocationKind string
+
+const (
+	LocationTown    LocationKind = "town"
+	LocationForest  LocationKind = "forest"
+	LocationMine    LocationKind = "mine"
+	LocationFishing LocationKind = "fishing"
+)
+
+// locationKinds lists the valid location kinds
+var locationKinds = []LocationKind{LocationTown, LocationForest, LocationMine, LocationFishing}
+
+// Location is a place on the world map. What the player can do depends on
+// where they are.
+type Location struct {
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	Kind        LocationKind `json:"kind"`
+	Description string       `json:"description,omitempty"`
+	Nodes       []string     `json:"nodes,omitempty"`    // Resource node IDs
+	Stations    []string     `json:"stations,omitempty"` // Processing station IDs
+	Monsters    []string     `json:"monsters,omitempty"` // Monster IDs
+	Shops       []string     `json:"shops,omitempty"`    // Shop IDs
+}
+
+// Route connects two locations. Routes work in both directions.
+type Route struct {
+	From    string  `json:"from"`
+	To      string  `json:"to"`
+	Seconds float64 `json:"seconds"`
+}
+
+// Duration returns the time to walk the route
+func (r Route) Duration() time.Duration {
+	return time.Duration(r.Seconds * float64(time.Second))
+}
+
+// worldContent is the layout of the world content file
+type worldContent struct {
+	Start     string      `json:"start"` // Where new characters begin and the defeated respawn
+	Locations []*Location `json:"locations"`
+	Routes    []Route     `json:"routes"`
+}
+
+// CurrentLocation returns where the player is
+func (s *State) CurrentLocation() *Location {
+	location, _ := s.Catalog.Location(s.Location)
+	return location
+}
+
+// NodesHere returns the resource nodes at the player's location
+func (s *State) NodesHere() []*ResourceNode {
+	var nodes []*ResourceNode
+	for _, id := range s.CurrentLocation().Nodes {
+		if node, ok := s.Catalog.ResourceNode(id); ok {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// MonstersHere returns the monsters at the player's location, in the
+// order they are listed there
+func (s *State) MonstersHere() []*Monster {
+	var found []*Monster
+	for _, id := range s.CurrentLocation().Monsters {
+		if monster, ok := s.Catalog.Monster(id); ok {
+			found = append(found, monster)
+		}
+	}
+	return found
+}
+
+// StationHere reports whether a station is at the player's location
+func (s *State) StationHere(stationID string) bool {
+	return slices.Contains(s.CurrentLocation().Stations, stationID)
+}
+
+// PlanRoute returns the quickest sequence of routes from the player's
+// location to a destination
+func (s *State) PlanRoute(destID string) ([]Route, error) {
+	dest, ok := s.Catalog.Location(destID)
+	if !ok {
+		return nil, fmt.Errorf("unknown location %q", destID)
+	}
+	if dest.ID == s.Location {
+		return nil, fmt.Errorf("already in %s", dest.Name)
+	}
+
+	// Dijkstra over the route graph; the map is small enough that a linear
+	// scan for the nearest unvisited location is fine
+	dist := map[string]time.Duration{s.Location: 0}
+	via := make(map[string]Route)
+	visited := make(map[string]bool)
+	for {
+		current, best := "", time.Duration(-1)
+		for id, d := range dist {
+			if !visited[id] && (best < 0 || d < best) {
+				current, best = id, d
+			}
+		}
+		if current == "" {
+			return nil, fmt.Errorf("no route to %s", dest.Name)
+		}
+		if current == dest.ID {
+			break
+		}
+		visited[current] = true
+		for _, route := range s.Catalog.RoutesFrom(current) {
+			d := best + route.Duration()
+			if old, seen := dist[route.To]; !seen || d < old {
+				dist[route.To] = d
+				via[route.To] = route
+			}
+		}
+	}
+
+	var path []Route
+	for id := dest.ID; id != s.Location; id = via[id].From {
+		path = append(path, via[id])
+	}
+	slices.Reverse(path)
+	return path, nil
+}
+
+// TravelTo replaces the action queue with the trip to a destination, one
+// travel action per route
+func (s *State) TravelTo(destID string) error {
+	if s.InCombat() {
+		return errors.New("cannot travel during a fight")
+	}
+	path, err := s.PlanRoute(destID)
+	if err != nil {
+		return err
+	}
+
+	actions := make([]Action, 0, len(path))
+	var total time.Duration
+	var stops []string
+	for _, route := range path {
+		actions = append(actions, NewAction(ActionTravel, route.To, 1))
+		total += route.Duration()
+		stops = append(stops, s.Catalog.LocationName(route.To))
+	}
+	s.Actions = actions
+
+	dest := s.Catalog.LocationName(destID)
+	details := fmt.Sprintf("(%s)", total.Round(time.Second))
+	if len(stops) > 1 {
+		details = fmt.Sprintf("via %s %s", strings.Join(stops[:len(stops)-1], ", "), details)
+	}
+	s.Log("Navigation", "Setting out for "+dest, details)
+	return nil
+}
+
+// route returns the route between two adjacent locations
+func (s *State) route(from, to string) (Route, bool) {
+	for _, route := range s.Catalog.RoutesFrom(from) {
+		if route.To == to {
+			return route, true
+		}
+	}
+	return Route{}, false
+}
+
+// canTravel checks that a travel action can leave from a location
+func (s *State) canTravel(to, from string) error {
+	dest, ok := s.Catalog.Location(to)
+	if !ok {
+		return fmt.Errorf("unknown location %q", to)
+	}
+	if _, ok := s.route(from, to); !ok {
+		return fmt.Errorf("no road from %s to %s", s.Catalog.LocationName(from), dest.Name)
+	}
+	return nil
+}
+
+// arrive finishes one leg of a trip
+func (s *State) arrive(locationID string) error {
+	if err := s.canTravel(locationID, s.Location); err != nil {
+		return err
+	}
+	s.Log("Navigation", "Traveled to "+s.Catalog.LocationName(locationID), "")
+	s.setLocation(locationID)
+	return nil
+}
+
+// setLocation moves the player and announces the arrival
+func (s *State) setLocation(locationID string) {
+	from := s.Location
+	s.Location = locationID
+	s.Events.Publish(LocationChanged{From: from, To: locationID})
+}
+
+// Destination returns where the player will be once every queued travel
+// action has finished
+func (s *State) Destination() string {
+	at := s.Location
+	for _, action := range s.Actions {
+		if action.Kind == ActionTravel {
+			at = action.Target
+		}
+	}
+	return at
+}
@@ -5,12 +5,20 @@ import (
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jexxer/tbrpg/content"
+	"github.com/jexxer/tbrpg/game"
 	"github.com/jexxer/tbrpg/ui"
 )
 
 func main() {
+	catalog, err := game.LoadCatalog(game.ContentFS(content.FS))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
 	p := tea.NewProgram(
-		ui.InitialModel(),
+		ui.InitialModel(catalog),
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
 	)
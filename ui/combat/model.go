@@ -0,0 +1,164 @@
+// Package combat provides the encounter screen shown in the main panel
+package combat
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/keys"
+)
+
+type View struct {
+	open   bool
+	cursor int // Monster list or action menu, depending on the screen
+	keyMap keys.KeyMap
+}
+
+// menuEntry is one choice in the encounter action menu
+type menuEntry struct {
+	label  string
+	action game.CombatAction
+}
+
+// New creates and initializes a new combat View
+func New(keyMap keys.KeyMap) View {
+	return View{keyMap: keyMap}
+}
+
+// Help lists the combat screen's keys for the help modal
+func Help(keyMap keys.KeyMap) keys.Section {
+	return keys.Section{Title: "Combat", Bindings: []key.Binding{
+		keyMap.Up, keyMap.Down, keyMap.Confirm, keyMap.Attack, keyMap.Defend, keyMap.Flee, keyMap.Monsters, keyMap.Back,
+	}}
+}
+
+// Open shows the combat screen
+func (v *View) Open() {
+	v.open = true
+	v.cursor = 0
+}
+
+// Close hides the combat screen. A fight in progress keeps waiting.
+func (v *View) Close() {
+	v.open = false
+}
+
+// IsOpen reports whether the combat screen replaces the active tab
+func (v *View) IsOpen() bool {
+	return v.open
+}
+
+// menu lists the actions available this round. Every usable consumable
+// in storage gets its own entry.
+func menu(gameState *game.State) []menuEntry {
+	entries := []menuEntry{
+		{label: "Attack", action: game.CombatAction{Kind: game.CombatAttack}},
+		{label: "Defend", action: game.CombatAction{Kind: game.CombatDefend}},
+	}
+	for _, item := range gameState.Storage.GetByTag("consumable") {
+		if len(item.Effects) == 0 {
+			continue
+		}
+		entries = append(entries, menuEntry{
+			label:  fmt.Sprintf("Use %s (%d)", item.Name, item.Quantity),
+			action: game.CombatAction{Kind: game.CombatUseItem, ItemID: item.ID},
+		})
+	}
+	return append(entries, menuEntry{label: "Flee", action: game.CombatAction{Kind: game.CombatFlee}})
+}
+
+// Update handles combat-specific updates
+func (v *View) Update(msg tea.Msg, gameState *game.State) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+
+	enc := gameState.Encounter
+	switch {
+	case enc == nil:
+		v.updatePicker(keyMsg, gameState)
+	case enc.IsOver():
+		v.updateAftermath(keyMsg, gameState)
+	default:
+		v.updateFight(keyMsg, gameState)
+	}
+	return nil
+}
+
+// updatePicker chooses a monster to fight
+func (v *View) updatePicker(msg tea.KeyMsg, gameState *game.State) {
+	monsters := gameState.MonstersHere()
+	keyMap := v.keyMap
+	switch {
+	case key.Matches(msg, keyMap.Up):
+		v.cursor = max(0, v.cursor-1)
+	case key.Matches(msg, keyMap.Down):
+		v.cursor = max(0, min(len(monsters)-1, v.cursor+1))
+	case key.Matches(msg, keyMap.Confirm, keyMap.Attack):
+		if v.cursor < len(monsters) {
+			v.start(monsters[v.cursor].ID, gameState)
+		}
+	case key.Matches(msg, keyMap.Back):
+		v.Close()
+	}
+}
+
+// updateFight plays a round
+func (v *View) updateFight(msg tea.KeyMsg, gameState *game.State) {
+	entries := menu(gameState)
+	v.cursor = min(v.cursor, len(entries)-1)
+
+	var action game.CombatAction
+	keyMap := v.keyMap
+	switch {
+	case key.Matches(msg, keyMap.Up):
+		v.cursor = max(0, v.cursor-1)
+		return
+	case key.Matches(msg, keyMap.Down):
+		v.cursor = min(len(entries)-1, v.cursor+1)
+		return
+	case key.Matches(msg, keyMap.Confirm):
+		action = entries[v.cursor].action
+	case key.Matches(msg, keyMap.Attack):
+		action = game.CombatAction{Kind: game.CombatAttack}
+	case key.Matches(msg, keyMap.Defend):
+		action = game.CombatAction{Kind: game.CombatDefend}
+	case key.Matches(msg, keyMap.Flee):
+		action = game.CombatAction{Kind: game.CombatFlee}
+	default:
+		return
+	}
+
+	if err := gameState.CombatTurn(action); err != nil {
+		gameState.Log("Combat", "Cannot do that:", err.Error())
+	}
+	if gameState.Encounter.IsOver() {
+		v.cursor = 0
+	}
+}
+
+// updateAftermath offers a rematch once a fight has ended
+func (v *View) updateAftermath(msg tea.KeyMsg, gameState *game.State) {
+	keyMap := v.keyMap
+	switch {
+	case key.Matches(msg, keyMap.Confirm, keyMap.Attack):
+		v.start(gameState.Encounter.MonsterID, gameState)
+	case key.Matches(msg, keyMap.Monsters):
+		gameState.EndEncounter()
+		v.cursor = 0
+	case key.Matches(msg, keyMap.Back):
+		gameState.EndEncounter()
+		v.Close()
+	}
+}
+
+func (v *View) start(monsterID string, gameState *game.State) {
+	if err := gameState.StartEncounter(monsterID); err != nil {
+		gameState.Log("Combat", "Cannot fight:", err.Error())
+		return
+	}
+	v.cursor = 0
+}
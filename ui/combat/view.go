@@ -0,0 +1,88 @@
+package combat
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/keys"
+	"github.com/jexxer/tbrpg/ui/shared"
+	"github.com/jexxer/tbrpg/ui/styles"
+)
+
+// barWidth is the width of the HP bars
+const barWidth = 20
+
+// View renders the combat screen
+func (v *View) View(width, height int, gameState *game.State) string {
+	titleStyle := styles.TitleStyle()
+	dimStyle := styles.DimStyle()
+	selectedStyle := styles.SelectedItemStyle()
+	keyMap := v.keyMap
+
+	var b strings.Builder
+	enc := gameState.Encounter
+
+	if enc == nil {
+		// Monster picker
+		monsters := gameState.MonstersHere()
+		b.WriteString(dimStyle.Render("Monsters in "+gameState.CurrentLocation().Name) + "\n")
+		if len(monsters) == 0 {
+			b.WriteString(dimStyle.Render("  Nothing to fight here") + "\n")
+		} else {
+			b.WriteString(dimStyle.Render(fmt.Sprintf("  %-12s %-4s %-5s %-5s %s", "Monster", "Lvl", "HP", "ATK", "DEF")) + "\n")
+		}
+		for i, monster := range monsters {
+			line := fmt.Sprintf("%-12s %-4d %-5d %-5d %d", monster.Name, monster.Level, monster.HP, monster.Attack, monster.Defense)
+			if i == v.cursor {
+				line = selectedStyle.Render("> " + line)
+			} else {
+				line = "  " + line
+			}
+			b.WriteString(line + "\n")
+		}
+		b.WriteString("\n" + dimStyle.Render(fmt.Sprintf("%s = Select  |  %s = Fight  |  %s = Leave",
+			keys.Hint(keyMap.Up, keyMap.Down), keys.Hint(keyMap.Confirm), keys.Hint(keyMap.Back))))
+		return lipgloss.JoinVertical(lipgloss.Left, titleStyle.Render("Combat"), "", b.String())
+	}
+
+	monster := gameState.CurrentMonster()
+	player := gameState.Player
+	stats := gameState.PlayerStats()
+
+	// Combatants
+	b.WriteString(fmt.Sprintf("%-16s %s %d/%d\n", player.Name,
+		shared.RenderProgressBar(barWidth, float64(player.HP)/float64(stats.MaxHP)), player.HP, stats.MaxHP))
+	b.WriteString(fmt.Sprintf("%-16s %s %d/%d\n",
+		fmt.Sprintf("%s (Lv %d)", monster.Name, monster.Level),
+		shared.RenderProgressBar(barWidth, float64(enc.MonsterHP)/float64(monster.HP)), enc.MonsterHP, monster.HP))
+
+	// Recent rounds
+	b.WriteString("\n" + dimStyle.Render(fmt.Sprintf("Round %d", enc.Round)) + "\n")
+	for _, line := range enc.Log {
+		b.WriteString("  " + line + "\n")
+	}
+	b.WriteString("\n")
+
+	if enc.IsOver() {
+		b.WriteString(dimStyle.Render(fmt.Sprintf("%s = Fight again  |  %s = Monsters  |  %s = Leave",
+			keys.Hint(keyMap.Confirm), keys.Hint(keyMap.Monsters), keys.Hint(keyMap.Back))))
+	} else {
+		for i, entry := range menu(gameState) {
+			if i == v.cursor {
+				b.WriteString(selectedStyle.Render("> "+entry.label) + "\n")
+			} else {
+				b.WriteString("  " + entry.label + "\n")
+			}
+		}
+		b.WriteString("\n" + dimStyle.Render(fmt.Sprintf("%s = Select  |  %s = Act  |  %s = Attack/Defend/Flee",
+			keys.Hint(keyMap.Up, keyMap.Down), keys.Hint(keyMap.Confirm), keys.Hint(keyMap.Attack, keyMap.Defend, keyMap.Flee))))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Render("Combat: "+monster.Name),
+		"",
+		b.String(),
+	)
+}
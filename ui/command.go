@@ -0,0 +1,192 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Invocation is a parsed command line
+type Invocation struct {
+	Name string
+	Args []string
+	Raw  string // Everything after the command name, unparsed
+}
+
+// Command describes a named command available from the command line
+type Command struct {
+	Name        string
+	Aliases     []string
+	Usage       string // Argument synopsis, e.g. "<tab>"
+	Description string
+	MinArgs     int
+	MaxArgs     int // -1 for unlimited
+	Run         func(m *Model, inv Invocation) (tea.Cmd, error)
+}
+
+// Synopsis returns the command name followed by its usage string
+func (c *Command) Synopsis() string {
+	if c.Usage == "" {
+		return c.Name
+	}
+	return c.Name + " " + c.Usage
+}
+
+// UsageError reports a command invoked with the wrong arguments
+type UsageError struct {
+	Command *Command
+	Reason  string
+}
+
+func (e *UsageError) Error() string {
+	return fmt.Sprintf("%s (usage: %s)", e.Reason, e.Command.Synopsis())
+}
+
+// CommandRegistry holds all registered commands keyed by name and alias
+type CommandRegistry struct {
+	commands []*Command
+	lookup   map[string]*Command
+}
+
+// NewCommandRegistry creates an empty command registry
+func NewCommandRegistry() *CommandRegistry {
+	return &CommandRegistry{
+		lookup: make(map[string]*Command),
+	}
+}
+
+// Register adds a command to the registry. Registering a name or alias
+// twice is a programming error and panics.
+func (r *CommandRegistry) Register(cmd Command) {
+	c := &cmd
+	for _, name := range append([]string{c.Name}, c.Aliases...) {
+		if _, exists := r.lookup[name]; exists {
+			panic("duplicate command name: " + name)
+		}
+		r.lookup[name] = c
+	}
+	r.commands = append(r.commands, c)
+}
+
+// Lookup finds a command by name or alias
+func (r *CommandRegistry) Lookup(name string) (*Command, bool) {
+	cmd, ok := r.lookup[strings.ToLower(name)]
+	return cmd, ok
+}
+
+// Commands returns all registered commands sorted by name
+func (r *CommandRegistry) Commands() []*Command {
+	sorted := make([]*Command, len(r.commands))
+	copy(sorted, r.commands)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// Execute parses and runs a command line against the model
+func (r *CommandRegistry) Execute(m *Model, input string) (tea.Cmd, error) {
+	inv, err := ParseCommandLine(input)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd, ok := r.Lookup(inv.Name)
+	if !ok {
+		return nil, fmt.Errorf("unknown command %q (try :help)", inv.Name)
+	}
+
+	if len(inv.Args) < cmd.MinArgs {
+		return nil, &UsageError{Command: cmd, Reason: "not enough arguments"}
+	}
+	if cmd.MaxArgs >= 0 && len(inv.Args) > cmd.MaxArgs {
+		return nil, &UsageError{Command: cmd, Reason: "too many arguments"}
+	}
+
+	return cmd.Run(m, inv)
+}
+
+// ParseCommandLine splits a command line into a command name and arguments.
+// Arguments are separated by whitespace; single or double quotes group
+// words and a backslash escapes the next character.
+func ParseCommandLine(input string) (Invocation, error) {
+	input = strings.TrimSpace(input)
+	input = strings.TrimPrefix(input, ":")
+	input = strings.TrimLeftFunc(input, unicode.IsSpace)
+
+	if input == "" {
+		return Invocation{}, errors.New("empty command")
+	}
+
+	nameEnd := strings.IndexFunc(input, unicode.IsSpace)
+	if nameEnd < 0 {
+		nameEnd = len(input)
+	}
+
+	inv := Invocation{
+		Name: strings.ToLower(input[:nameEnd]),
+		Raw:  strings.TrimSpace(input[nameEnd:]),
+	}
+
+	args, err := splitArgs(inv.Raw)
+	if err != nil {
+		return Invocation{}, err
+	}
+	inv.Args = args
+
+	return inv, nil
+}
+
+// splitArgs tokenizes an argument string honoring quotes and escapes
+func splitArgs(s string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	var quote rune
+	inToken := false
+	escaped := false
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+			inToken = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inToken = true
+		case unicode.IsSpace(r):
+			if inToken {
+				args = append(args, current.String())
+				current.Reset()
+				inToken = false
+			}
+		default:
+			current.WriteRune(r)
+			inToken = true
+		}
+	}
+
+	if escaped {
+		return nil, errors.New("trailing backslash")
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inToken {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
@@ -0,0 +1,855 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/keys"
+	"github.com/jexxer/tbrpg/ui/shared"
+	"github.com/jexxer/tbrpg/ui/styles"
+)
+
+// Tab indexes into shared.TabNames
+const (
+	TabNavigation = iota
+	TabStorage
+	TabEquipment
+	TabGathering
+	TabProcessing
+	TabCrafting
+	TabQuests
+	TabMarket
+)
+
+// defaultCommands builds the registry of built-in commands
+func defaultCommands() *CommandRegistry {
+	r := NewCommandRegistry()
+
+	r.Register(Command{
+		Name:        "help",
+		Aliases:     []string{"h", "?"},
+		Usage:       "[command]",
+		Description: "List commands or show usage for one",
+		MaxArgs:     1,
+		Run:         runHelp,
+	})
+	r.Register(Command{
+		Name:        "keys",
+		Aliases:     []string{"keymap"},
+		Description: "List key bindings and where to change them",
+		Run:         runKeys,
+	})
+	r.Register(Command{
+		Name:        "theme",
+		Usage:       "[name|file.json]",
+		Description: "List color themes, or switch to one by name or from a theme file",
+		MaxArgs:     1,
+		Run:         runTheme,
+	})
+	r.Register(Command{
+		Name:        "goto",
+		Aliases:     []string{"g", "tab"},
+		Usage:       "<tab>",
+		Description: "Switch to a tab (" + strings.ToLower(strings.Join(shared.TabNames, ", ")) + ")",
+		MinArgs:     1,
+		MaxArgs:     1,
+		Run:         runGoto,
+	})
+	r.Register(Command{
+		Name:        "search",
+		Aliases:     []string{"s", "find"},
+		Usage:       "[query]",
+		Description: "Search storage; no query clears the search",
+		MaxArgs:     -1,
+		Run:         runSearch,
+	})
+	r.Register(Command{
+		Name:        "sort",
+		Usage:       "[name|quantity|value|total|category] [asc|desc]",
+		Description: "Sort storage in the selected category; no key keeps storage order",
+		MaxArgs:     2,
+		Run:         runSort,
+	})
+	r.Register(Command{
+		Name:        "savesearch",
+		Usage:       "<name>",
+		Description: "Save the current storage search and category",
+		MinArgs:     1,
+		MaxArgs:     -1,
+		Run:         runSaveSearch,
+	})
+	r.Register(Command{
+		Name:        "loadsearch",
+		Usage:       "<name|#>",
+		Description: "Load a saved search by name or number",
+		MinArgs:     1,
+		MaxArgs:     -1,
+		Run:         runLoadSearch,
+	})
+	r.Register(Command{
+		Name:        "renamesearch",
+		Usage:       "<name|#> <new name>",
+		Description: "Rename a saved search",
+		MinArgs:     2,
+		MaxArgs:     2,
+		Run:         runRenameSearch,
+	})
+	r.Register(Command{
+		Name:        "delsearch",
+		Usage:       "<name|#>",
+		Description: "Delete a saved search",
+		MinArgs:     1,
+		MaxArgs:     -1,
+		Run:         runDeleteSearch,
+	})
+	r.Register(Command{
+		Name:        "equip",
+		Usage:       "<item_id>",
+		Description: "Equip an item from storage",
+		MinArgs:     1,
+		MaxArgs:     1,
+		Run:         runEquip,
+	})
+	r.Register(Command{
+		Name:        "unequip",
+		Usage:       "<item_id|slot>",
+		Description: "Return an item, or everything in a slot, to storage",
+		MinArgs:     1,
+		MaxArgs:     1,
+		Run:         runUnequip,
+	})
+	r.Register(Command{
+		Name:        "gather",
+		Usage:       "<node_id> [count]",
+		Description: "Start gathering at a resource node (repeats until stopped without a count)",
+		MinArgs:     1,
+		MaxArgs:     2,
+		Run:         runGather,
+	})
+	r.Register(Command{
+		Name:        "queue",
+		Usage:       "<node_id> <count>",
+		Description: "Queue gathering after the current action",
+		MinArgs:     2,
+		MaxArgs:     2,
+		Run:         runQueue,
+	})
+	r.Register(Command{
+		Name:        "craft",
+		Usage:       "<recipe_id> [count]",
+		Description: "Queue crafts of a recipe (default 1)",
+		MinArgs:     1,
+		MaxArgs:     2,
+		Run:         runCraft,
+	})
+	r.Register(Command{
+		Name:        "process",
+		Usage:       "<conversion_id> [count]",
+		Description: "Queue a conversion at its station; stations run alongside other actions",
+		MinArgs:     1,
+		MaxArgs:     2,
+		Run:         runProcess,
+	})
+	r.Register(Command{
+		Name:        "travel",
+		Usage:       "<location_id>",
+		Description: "Travel to a location by the quickest route",
+		MinArgs:     1,
+		MaxArgs:     1,
+		Run:         runTravel,
+	})
+	r.Register(Command{
+		Name:        "buy",
+		Usage:       "<item_id> [count]",
+		Description: "Buy from the cheapest shop here that has enough stock (default 1)",
+		MinArgs:     1,
+		MaxArgs:     2,
+		Run:         runBuy,
+	})
+	r.Register(Command{
+		Name:        "sell",
+		Usage:       "<item_id> [count]",
+		Description: "Sell to the shop here that pays the most (default all)",
+		MinArgs:     1,
+		MaxArgs:     2,
+		Run:         runSell,
+	})
+	r.Register(Command{
+		Name:        "stop",
+		Usage:       "[all]",
+		Description: "Stop the current action, or the whole queue",
+		MaxArgs:     1,
+		Run:         runStop,
+	})
+	r.Register(Command{
+		Name:        "pause",
+		Description: "Pause or resume the game clock",
+		MaxArgs:     0,
+		Run:         runPause,
+	})
+	r.Register(Command{
+		Name:        "skills",
+		Description: "Show skill levels and XP",
+		MaxArgs:     0,
+		Run:         runSkills,
+	})
+	r.Register(Command{
+		Name:        "achievements",
+		Description: "Show achievements and progress",
+		MaxArgs:     0,
+		Run:         runAchievements,
+	})
+	r.Register(Command{
+		Name:        "fight",
+		Aliases:     []string{"attack"},
+		Usage:       "[monster_id]",
+		Description: "Open combat, optionally engaging a monster",
+		MaxArgs:     1,
+		Run:         runFight,
+	})
+	r.Register(Command{
+		Name:        "character",
+		Aliases:     []string{"char", "stats"},
+		Description: "Show attributes and derived stats",
+		MaxArgs:     0,
+		Run:         runCharacter,
+	})
+	r.Register(Command{
+		Name:        "name",
+		Usage:       "<name>",
+		Description: "Rename your character",
+		MinArgs:     1,
+		MaxArgs:     -1,
+		Run:         runName,
+	})
+	r.Register(Command{
+		Name:        "quest",
+		Usage:       "<accept|deliver|abandon> <quest_id>",
+		Description: "Accept, deliver items for, or abandon a quest",
+		MinArgs:     2,
+		MaxArgs:     2,
+		Run:         runQuest,
+	})
+	r.Register(Command{
+		Name:        "clear",
+		Description: "Clear the activity log",
+		MaxArgs:     0,
+		Run:         runClear,
+	})
+	r.Register(Command{
+		Name:        "log",
+		Usage:       "[all|hide <category>|show <category>|only <category>|since [duration]]",
+		Description: "Filter the activity log by category or age, or show the filters",
+		MaxArgs:     2,
+		Run:         runLog,
+	})
+	r.Register(Command{
+		Name:        "save",
+		Aliases:     []string{"w"},
+		Usage:       "[slot]",
+		Description: "Save the game (defaults to the current slot)",
+		MaxArgs:     1,
+		Run:         runSave,
+	})
+	r.Register(Command{
+		Name:        "load",
+		Usage:       "[slot]",
+		Description: "Load a saved game (defaults to the current slot)",
+		MaxArgs:     1,
+		Run:         runLoad,
+	})
+	r.Register(Command{
+		Name:        "saves",
+		Description: "List save slots",
+		MaxArgs:     0,
+		Run:         runSaves,
+	})
+	r.Register(Command{
+		Name:        "quit",
+		Aliases:     []string{"q", "exit"},
+		Description: "Save and quit the game",
+		MaxArgs:     0,
+		Run: func(m *Model, inv Invocation) (tea.Cmd, error) {
+			return m.quit(), nil
+		},
+	})
+
+	return r
+}
+
+// ExecuteCommand runs a command line and reports the outcome in the activity log
+func (m *Model) ExecuteCommand(input string) tea.Cmd {
+	if strings.TrimSpace(input) == "" {
+		return nil
+	}
+
+	m.AddLogEntry("Command", "Ran:", styles.CommandStyle().Render(input))
+
+	cmd, err := m.commands.Execute(m, input)
+	if err != nil {
+		m.AddLogEntry("Command", "Error:", err.Error())
+		return nil
+	}
+	m.syncViews()
+	return cmd
+}
+
+func runHelp(m *Model, inv Invocation) (tea.Cmd, error) {
+	if len(inv.Args) == 1 {
+		cmd, ok := m.commands.Lookup(inv.Args[0])
+		if !ok {
+			return nil, fmt.Errorf("unknown command %q", inv.Args[0])
+		}
+		details := cmd.Description
+		if len(cmd.Aliases) > 0 {
+			details += " (aliases: " + strings.Join(cmd.Aliases, ", ") + ")"
+		}
+		m.AddLogEntry("Command", ":"+cmd.Synopsis(), details)
+		return nil, nil
+	}
+
+	for _, cmd := range m.commands.Commands() {
+		m.AddLogEntry("Command", ":"+cmd.Synopsis(), "- "+cmd.Description)
+	}
+	return nil, nil
+}
+
+func runKeys(m *Model, inv Invocation) (tea.Cmd, error) {
+	for _, named := range m.keyMap.All() {
+		bound := named.Binding.Help().Key
+		if !named.Binding.Enabled() {
+			bound = "(unbound)"
+		}
+		m.AddLogEntry("Command", named.Name+": "+bound, "- "+named.Binding.Help().Desc)
+	}
+	if path, err := keys.Path(); err == nil {
+		m.AddLogEntry("Command", "Keymap file:", path)
+	}
+	return nil, nil
+}
+
+func runTheme(m *Model, inv Invocation) (tea.Cmd, error) {
+	if len(inv.Args) == 0 {
+		current := styles.CurrentTheme().Name
+		for _, name := range styles.ThemeNames() {
+			if name == current {
+				name += " (current)"
+			}
+			m.AddLogEntry("Command", "Theme:", name)
+		}
+		if dir, err := styles.ThemeDir(); err == nil {
+			m.AddLogEntry("Command", "Theme files:", dir)
+		}
+		return nil, nil
+	}
+
+	name := inv.Args[0]
+	if strings.HasSuffix(strings.ToLower(name), ".json") {
+		theme, err := styles.LoadThemeFile(name)
+		if err != nil {
+			return nil, err
+		}
+		name = theme.Name
+	}
+	if err := styles.SetTheme(name); err != nil {
+		return nil, err
+	}
+	m.GameState.Theme = styles.CurrentTheme().Name
+	// The activity log caches its colored lines
+	m.activity.UpdateContent(m.GameState)
+	m.AddLogEntry("System", "Theme: "+m.GameState.Theme, "")
+	return nil, nil
+}
+
+func runGoto(m *Model, inv Invocation) (tea.Cmd, error) {
+	tab, err := findTab(inv.Args[0])
+	if err != nil {
+		return nil, err
+	}
+	m.switchTab(tab)
+	return nil, nil
+}
+
+func runSearch(m *Model, inv Invocation) (tea.Cmd, error) {
+	m.switchTab(TabStorage)
+	m.storage.SetQuery(inv.Raw, m.GameState)
+	if inv.Raw == "" {
+		m.AddLogEntry("Storage", "Search cleared", "")
+	} else {
+		m.AddLogEntry("Storage", "Search: "+inv.Raw, "")
+	}
+	return nil, nil
+}
+
+func runSort(m *Model, inv Invocation) (tea.Cmd, error) {
+	var order game.ItemSort
+	if len(inv.Args) > 0 {
+		key, err := game.ParseSortKey(inv.Args[0])
+		if err != nil {
+			return nil, err
+		}
+		order.Key = key
+	}
+	if len(inv.Args) > 1 {
+		switch strings.ToLower(inv.Args[1]) {
+		case "asc":
+		case "desc":
+			order.Descending = true
+		default:
+			return nil, fmt.Errorf("unknown direction %q (use asc or desc)", inv.Args[1])
+		}
+	}
+	m.switchTab(TabStorage)
+	m.storage.SetSort(order, m.GameState)
+	return nil, nil
+}
+
+func runSaveSearch(m *Model, inv Invocation) (tea.Cmd, error) {
+	name := strings.Join(inv.Args, " ")
+	query := m.storage.Query()
+	if err := m.GameState.SaveSearch(name, query, m.GameState.SelectedCategory); err != nil {
+		return nil, err
+	}
+	m.AddLogEntry("Storage", "Saved search: "+name, query)
+	return nil, nil
+}
+
+func runLoadSearch(m *Model, inv Invocation) (tea.Cmd, error) {
+	saved, err := m.GameState.LoadSearch(strings.Join(inv.Args, " "))
+	if err != nil {
+		return nil, err
+	}
+	m.switchTab(TabStorage)
+	m.storage.ApplySearch(saved, m.GameState)
+	m.AddLogEntry("Storage", "Loaded search: "+saved.Name, saved.Query)
+	return nil, nil
+}
+
+func runRenameSearch(m *Model, inv Invocation) (tea.Cmd, error) {
+	if err := m.GameState.RenameSearch(inv.Args[0], inv.Args[1]); err != nil {
+		return nil, err
+	}
+	m.AddLogEntry("Storage", "Renamed saved search: "+inv.Args[0]+" -> "+inv.Args[1], "")
+	return nil, nil
+}
+
+func runDeleteSearch(m *Model, inv Invocation) (tea.Cmd, error) {
+	removed, err := m.GameState.DeleteSearch(strings.Join(inv.Args, " "))
+	if err != nil {
+		return nil, err
+	}
+	m.AddLogEntry("Storage", "Deleted saved search: "+removed.Name, "")
+	return nil, nil
+}
+
+func runEquip(m *Model, inv Invocation) (tea.Cmd, error) {
+	if err := m.GameState.EquipItem(inv.Args[0]); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func runUnequip(m *Model, inv Invocation) (tea.Cmd, error) {
+	if err := m.GameState.UnequipItem(inv.Args[0]); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func runGather(m *Model, inv Invocation) (tea.Cmd, error) {
+	count := 0
+	if len(inv.Args) == 2 {
+		n, err := parseCount(inv.Args[1])
+		if err != nil {
+			return nil, err
+		}
+		count = n
+	}
+	action := game.NewAction(game.ActionGather, inv.Args[0], count)
+	if err := m.GameState.StartAction(action); err != nil {
+		return nil, err
+	}
+	m.AddLogEntry("System", "Started "+m.GameState.ActionLabel(action), "")
+	return nil, nil
+}
+
+func runQueue(m *Model, inv Invocation) (tea.Cmd, error) {
+	count, err := parseCount(inv.Args[1])
+	if err != nil {
+		return nil, err
+	}
+	action := game.NewAction(game.ActionGather, inv.Args[0], count)
+	if err := m.GameState.QueueAction(action); err != nil {
+		return nil, err
+	}
+	m.AddLogEntry("System", "Queued "+m.GameState.ActionLabel(action), fmt.Sprintf("x%d", count))
+	return nil, nil
+}
+
+func runCraft(m *Model, inv Invocation) (tea.Cmd, error) {
+	count := 1
+	if len(inv.Args) == 2 {
+		n, err := parseCount(inv.Args[1])
+		if err != nil {
+			return nil, err
+		}
+		count = n
+	}
+	action := game.NewAction(game.ActionCraft, inv.Args[0], count)
+	if err := m.GameState.QueueAction(action); err != nil {
+		return nil, err
+	}
+	m.AddLogEntry("System", "Queued "+m.GameState.ActionLabel(action), fmt.Sprintf("x%d", count))
+	return nil, nil
+}
+
+func runProcess(m *Model, inv Invocation) (tea.Cmd, error) {
+	count := 1
+	if len(inv.Args) == 2 {
+		n, err := parseCount(inv.Args[1])
+		if err != nil {
+			return nil, err
+		}
+		count = n
+	}
+	action := game.NewAction(game.ActionProcess, inv.Args[0], count)
+	if err := m.GameState.QueueProcess(action); err != nil {
+		return nil, err
+	}
+	m.AddLogEntry("System", "Queued "+m.GameState.ActionLabel(action), fmt.Sprintf("x%d", count))
+	return nil, nil
+}
+
+func runQuest(m *Model, inv Invocation) (tea.Cmd, error) {
+	var err error
+	switch verb, id := inv.Args[0], inv.Args[1]; verb {
+	case "accept":
+		err = m.GameState.AcceptQuest(id)
+	case "deliver":
+		err = m.GameState.DeliverQuest(id)
+	case "abandon":
+		err = m.GameState.AbandonQuest(id)
+	default:
+		return nil, fmt.Errorf("expected accept, deliver or abandon, got %q", verb)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func runTravel(m *Model, inv Invocation) (tea.Cmd, error) {
+	return nil, m.GameState.TravelTo(inv.Args[0])
+}
+
+func runBuy(m *Model, inv Invocation) (tea.Cmd, error) {
+	count := 1
+	if len(inv.Args) == 2 {
+		n, err := parseCount(inv.Args[1])
+		if err != nil {
+			return nil, err
+		}
+		count = n
+	}
+	def, ok := m.GameState.Catalog.Item(inv.Args[0])
+	if !ok {
+		return nil, fmt.Errorf("unknown item %q", inv.Args[0])
+	}
+
+	// Prefer the cheapest shop that can fill the order, then any shop that
+	// stocks the item so Buy can explain what is short
+	var best *game.Shop
+	for _, shop := range m.GameState.ShopsHere() {
+		if m.GameState.Market.InStock(shop, def.ID) >= count {
+			if best == nil || game.BuyPrice(shop, def) < game.BuyPrice(best, def) {
+				best = shop
+			}
+		}
+	}
+	if best == nil {
+		for _, shop := range m.GameState.ShopsHere() {
+			if slices.ContainsFunc(shop.Stock, func(s game.ItemAmount) bool { return s.Item == def.ID }) {
+				best = shop
+				break
+			}
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("no shop in %s sells %s", m.GameState.CurrentLocation().Name, def.Name)
+	}
+
+	if err := m.GameState.Buy(best.ID, def.ID, count); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func runSell(m *Model, inv Invocation) (tea.Cmd, error) {
+	def, ok := m.GameState.Catalog.Item(inv.Args[0])
+	if !ok {
+		return nil, fmt.Errorf("unknown item %q", inv.Args[0])
+	}
+	count := m.GameState.Storage.Quantity(def.ID)
+	if len(inv.Args) == 2 {
+		n, err := parseCount(inv.Args[1])
+		if err != nil {
+			return nil, err
+		}
+		count = n
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("no %s in storage", def.Name)
+	}
+
+	items := []game.ItemAmount{{Item: def.ID, Quantity: count}}
+	shop, err := m.GameState.BestBuyer(items)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.GameState.Sell(shop.ID, items); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func runStop(m *Model, inv Invocation) (tea.Cmd, error) {
+	if len(inv.Args) == 1 {
+		if inv.Args[0] != "all" {
+			return nil, fmt.Errorf("expected \"all\", got %q", inv.Args[0])
+		}
+		m.GameState.ClearActions()
+		m.AddLogEntry("System", "Cleared action queue", "")
+		return nil, nil
+	}
+
+	action, ok := m.GameState.CancelAction()
+	if !ok {
+		return nil, fmt.Errorf("nothing to stop")
+	}
+	m.AddLogEntry("System", "Stopped "+m.GameState.ActionLabel(action), "")
+	return nil, nil
+}
+
+func runPause(m *Model, inv Invocation) (tea.Cmd, error) {
+	paused := !m.GameState.Clock.Paused
+	m.GameState.Clock.SetPaused(paused)
+	if paused {
+		m.AddLogEntry("System", "Game paused", "")
+	} else {
+		m.AddLogEntry("System", "Game resumed", "")
+	}
+	return nil, nil
+}
+
+// parseCount parses a positive repeat count argument
+func parseCount(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("count must be a positive number, got %q", s)
+	}
+	return n, nil
+}
+
+func runSkills(m *Model, inv Invocation) (tea.Cmd, error) {
+	for _, skill := range game.AllSkills {
+		level := m.GameState.SkillLevel(skill)
+		m.AddLogEntry(string(skill), fmt.Sprintf("Level %d", level), fmt.Sprintf("(%d XP)", m.GameState.SkillXP(skill)))
+	}
+	return nil, nil
+}
+
+func runAchievements(m *Model, inv Invocation) (tea.Cmd, error) {
+	for _, achievement := range m.GameState.Catalog.Achievements() {
+		progress := fmt.Sprintf("(%d/%d)", m.GameState.AchievementCount(achievement.ID), achievement.Count)
+		if m.GameState.HasAchievement(achievement.ID) {
+			progress = "(unlocked)"
+		}
+		m.AddLogEntry("Achievements", achievement.Name+":", m.GameState.DescribeAchievement(achievement)+" "+progress)
+	}
+	return nil, nil
+}
+
+func runFight(m *Model, inv Invocation) (tea.Cmd, error) {
+	if len(inv.Args) == 1 {
+		if err := m.GameState.StartEncounter(inv.Args[0]); err != nil {
+			return nil, err
+		}
+	}
+	m.combat.Open()
+	m.FocusedView = FocusGameView
+	return nil, nil
+}
+
+func runCharacter(m *Model, inv Invocation) (tea.Cmd, error) {
+	player := m.GameState.Player
+	stats := m.GameState.PlayerStats()
+	attrs := player.Attributes
+	m.AddLogEntry("Character", fmt.Sprintf("%s, level %d", player.Name, stats.Level), fmt.Sprintf("(%d XP)", player.XP))
+	m.AddLogEntry("Character", "Attributes", fmt.Sprintf("STR %d  AGI %d  VIT %d  WIS %d", attrs.Strength, attrs.Agility, attrs.Vitality, attrs.Wisdom))
+	m.AddLogEntry("Character", "Stats", fmt.Sprintf("HP %d/%d  MP %d/%d  ATK %d  DEF %d  Skill total %d",
+		player.HP, stats.MaxHP, player.MP, stats.MaxMP, stats.Attack, stats.Defense, stats.SkillTotal))
+	return nil, nil
+}
+
+func runName(m *Model, inv Invocation) (tea.Cmd, error) {
+	if err := m.GameState.RenamePlayer(strings.Join(inv.Args, " ")); err != nil {
+		return nil, err
+	}
+	m.AddLogEntry("Character", "Renamed to "+m.GameState.Player.Name, "")
+	return nil, nil
+}
+
+func runClear(m *Model, inv Invocation) (tea.Cmd, error) {
+	m.GameState.ActivityLog.Clear()
+	m.activity.UpdateContent(m.GameState)
+	return nil, nil
+}
+
+func runLog(m *Model, inv Invocation) (tea.Cmd, error) {
+	gs := m.GameState
+	if len(inv.Args) == 0 {
+		hidden := "none"
+		if len(gs.LogHidden) > 0 {
+			hidden = strings.Join(gs.LogHidden, ", ")
+		}
+		m.AddLogEntry("Command", "Hidden log categories:", hidden)
+		if since := m.activity.Since(); since > 0 {
+			m.AddLogEntry("Command", "Log shows the last", game.FormatDuration(since))
+		}
+		return nil, nil
+	}
+
+	switch verb := strings.ToLower(inv.Args[0]); verb {
+	case "all":
+		gs.LogHidden = nil
+		m.activity.SetSince(0, gs)
+		m.AddLogEntry("Command", "Showing the whole log", "")
+
+	case "hide", "show", "only":
+		if len(inv.Args) < 2 {
+			return nil, fmt.Errorf("log %s needs a category", verb)
+		}
+		category, err := findLogCategory(gs, inv.Args[1])
+		if err != nil {
+			return nil, err
+		}
+		switch verb {
+		case "hide":
+			gs.SetLogCategoryHidden(category, true)
+		case "show":
+			gs.SetLogCategoryHidden(category, false)
+		case "only":
+			for _, other := range gs.LogCategories() {
+				gs.SetLogCategoryHidden(other, other != category)
+			}
+		}
+		m.activity.UpdateContent(gs)
+
+	case "since":
+		if len(inv.Args) < 2 {
+			m.activity.SetSince(0, gs)
+			return nil, nil
+		}
+		since, err := time.ParseDuration(inv.Args[1])
+		if err != nil || since <= 0 {
+			return nil, fmt.Errorf("invalid duration %q (like 10m or 1h30m)", inv.Args[1])
+		}
+		m.activity.SetSince(since, gs)
+
+	default:
+		return nil, fmt.Errorf("expected all, hide, show, only or since, got %q", inv.Args[0])
+	}
+	return nil, nil
+}
+
+// findLogCategory matches a category name in the log, ignoring case
+func findLogCategory(gs *game.State, name string) (string, error) {
+	categories := gs.LogCategories()
+	for _, category := range categories {
+		if strings.EqualFold(category, name) {
+			return category, nil
+		}
+	}
+	return "", fmt.Errorf("unknown log category %q (try %s)", name, strings.Join(categories, ", "))
+}
+
+func runSave(m *Model, inv Invocation) (tea.Cmd, error) {
+	slot := m.saveSlot
+	if len(inv.Args) == 1 {
+		slot = inv.Args[0]
+	}
+	if err := m.saveGame(slot); err != nil {
+		return nil, err
+	}
+	m.AddLogEntry("System", "Saved game to slot "+slot, "")
+	return nil, nil
+}
+
+func runLoad(m *Model, inv Invocation) (tea.Cmd, error) {
+	slot := m.saveSlot
+	if len(inv.Args) == 1 {
+		slot = inv.Args[0]
+	}
+	if err := m.loadGame(slot); err != nil {
+		return nil, err
+	}
+	m.AddLogEntry("System", "Loaded slot "+slot, "")
+	return nil, nil
+}
+
+func runSaves(m *Model, inv Invocation) (tea.Cmd, error) {
+	if m.saveDir == "" {
+		return nil, fmt.Errorf("saving is unavailable: no save directory")
+	}
+	saves, err := game.ListSaves(m.saveDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(saves) == 0 {
+		m.AddLogEntry("System", "No saves yet", "")
+		return nil, nil
+	}
+	for _, save := range saves {
+		marker := ""
+		if save.Slot == m.saveSlot {
+			marker = "(current)"
+		}
+		m.AddLogEntry("System", "Slot "+save.Slot, save.SavedAt.Format("2006-01-02 15:04")+" "+marker)
+	}
+	return nil, nil
+}
+
+// findTab resolves a tab by case-insensitive name or unique prefix
+func findTab(name string) (int, error) {
+	name = strings.ToLower(name)
+	match := -1
+	for i, tab := range shared.TabNames {
+		tabName := strings.ToLower(tab)
+		if tabName == name {
+			return i, nil
+		}
+		if strings.HasPrefix(tabName, name) {
+			if match >= 0 {
+				return 0, fmt.Errorf("ambiguous tab %q", name)
+			}
+			match = i
+		}
+	}
+	if match < 0 {
+		return 0, fmt.Errorf("unknown tab %q", name)
+	}
+	return match, nil
+}
+
+// switchTab shows the given tab in the game view
+func (m *Model) switchTab(tab int) {
+	m.combat.Close()
+	m.ActiveTab = tab
+	m.navigation.Select(tab)
+	m.FocusedView = FocusGameView
+}
@@ -0,0 +1,102 @@
+// Package crafting provides the crafting tab component
+package crafting
+
+import (
+	"strconv"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/keys"
+	"github.com/jexxer/tbrpg/ui/shared"
+)
+
+// maxCount caps the typed craft count
+const maxCount = 9999
+
+type View struct {
+	cursor int
+	count  string // Digits typed before queueing; empty means 1
+	keyMap keys.KeyMap
+}
+
+// New creates and initializes a new crafting View
+func New(keyMap keys.KeyMap) View {
+	return View{keyMap: keyMap}
+}
+
+// Help lists the crafting tab's keys for the help modal
+func Help(keyMap keys.KeyMap) keys.Section {
+	return keys.Section{Title: "Crafting", Bindings: []key.Binding{
+		keyMap.Up, keyMap.Down, keyMap.Queue, keyMap.QueueMax, keyMap.Stop, keyMap.Back,
+	}}
+}
+
+// SelectedRecipe returns the recipe under the cursor
+func (v *View) SelectedRecipe(gameState *game.State) (*game.Recipe, bool) {
+	recipes := gameState.Catalog.Recipes()
+	if v.cursor < 0 || v.cursor >= len(recipes) {
+		return nil, false
+	}
+	return recipes[v.cursor], true
+}
+
+// Count returns how many crafts enter will queue
+func (v *View) Count() int {
+	n, err := strconv.Atoi(v.count)
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+// Update handles crafting-specific updates
+func (v *View) Update(msg tea.Msg, gameState *game.State) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+	recipes := gameState.Catalog.Recipes()
+	keyMap := v.keyMap
+
+	switch {
+	case key.Matches(keyMsg, keyMap.Up):
+		if v.cursor > 0 {
+			v.cursor--
+		}
+	case key.Matches(keyMsg, keyMap.Down):
+		if v.cursor < len(recipes)-1 {
+			v.cursor++
+		}
+	case key.Matches(keyMsg, keyMap.Queue, keyMap.QueueMax):
+		recipe, ok := v.SelectedRecipe(gameState)
+		if !ok {
+			return nil
+		}
+		count := v.Count()
+		if key.Matches(keyMsg, keyMap.QueueMax) {
+			count = gameState.MaxCrafts(recipe)
+		}
+		v.count = ""
+		if count < 1 {
+			gameState.Log(string(recipe.Skill), "Cannot craft:", "no materials for "+recipe.Name)
+			return nil
+		}
+		action := game.NewAction(game.ActionCraft, recipe.ID, count)
+		if err := gameState.QueueAction(action); err != nil {
+			gameState.Log(string(recipe.Skill), "Cannot craft:", err.Error())
+			return nil
+		}
+		gameState.Log("System", "Queued "+gameState.ActionLabel(action), "x"+strconv.Itoa(count))
+	case key.Matches(keyMsg, keyMap.Stop):
+		if action, ok := gameState.CancelAction(); ok {
+			gameState.Log("System", "Stopped "+gameState.ActionLabel(action), "")
+		}
+	case key.Matches(keyMsg, keyMap.Back):
+		v.count = ""
+	default:
+		v.count = shared.TypeCount(v.count, keyMsg.String(), maxCount)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package crafting
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/keys"
+	"github.com/jexxer/tbrpg/ui/styles"
+)
+
+// View renders the crafting view
+func (v *View) View(width, height int, gameState *game.State) string {
+	titleStyle := styles.TitleStyle()
+	dimStyle := styles.DimStyle()
+	selectedStyle := styles.SelectedItemStyle()
+	readyStyle := styles.ReadyStyle()
+
+	var b strings.Builder
+
+	level := gameState.SkillLevel(game.SkillCrafting)
+	b.WriteString(fmt.Sprintf("%-12s Lv %-3d %d XP\n", game.SkillCrafting, level, gameState.SkillXP(game.SkillCrafting)))
+
+	// Recipes
+	b.WriteString("\n" + dimStyle.Render(fmt.Sprintf("  %-14s %-4s %-7s %s", "Recipe", "Lvl", "Time", "Can make")) + "\n")
+	for i, recipe := range gameState.Catalog.Recipes() {
+		status := fmt.Sprintf("%d", gameState.MaxCrafts(recipe))
+		err := gameState.CanCraft(recipe)
+		if err != nil && gameState.SkillLevel(recipe.Skill) < recipe.Level {
+			status = "locked"
+		}
+
+		line := fmt.Sprintf("%-14s %-4d %-7s %s", recipe.Name, recipe.Level,
+			fmt.Sprintf("%.1fs", gameState.CraftDuration(recipe).Seconds()), status)
+		switch {
+		case i == v.cursor:
+			line = selectedStyle.Render("> " + line)
+		case err != nil:
+			line = dimStyle.Render("  " + line)
+		default:
+			line = readyStyle.Render("  " + line)
+		}
+		b.WriteString(line + "\n")
+	}
+
+	// Selected recipe details
+	if recipe, ok := v.SelectedRecipe(gameState); ok {
+		b.WriteString("\n" + recipe.Name + dimStyle.Render(fmt.Sprintf("  +%d XP", recipe.XP)) + "\n")
+		for _, input := range recipe.Inputs {
+			have := gameState.Storage.Quantity(input.Item)
+			line := fmt.Sprintf("  %-16s %d/%d", gameState.Catalog.ItemName(input.Item), have, input.Quantity)
+			if have < input.Quantity {
+				line = dimStyle.Render(line)
+			}
+			b.WriteString(line + "\n")
+		}
+		if recipe.Tool != "" {
+			line := "  Needs a " + recipe.Tool
+			if !gameState.HasTool(recipe.Tool) {
+				line = dimStyle.Render(line + " (missing)")
+			}
+			b.WriteString(line + "\n")
+		}
+		if err := gameState.CanCraft(recipe); err != nil {
+			b.WriteString(dimStyle.Render("  "+err.Error()) + "\n")
+		}
+	}
+
+	b.WriteString("\n" + dimStyle.Render(fmt.Sprintf("Count: %d  |  0-9 = Count  |  %s = Queue  |  %s = Queue max  |  %s = Stop",
+		v.Count(), keys.Hint(v.keyMap.Queue), keys.Hint(v.keyMap.QueueMax), keys.Hint(v.keyMap.Stop))))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Render("Crafting"),
+		"",
+		b.String(),
+	)
+}
@@ -0,0 +1,211 @@
+// Package details provides the Details panel: the item picked in storage
+// with its actions, or shortcuts when no item is picked
+package details
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/keys"
+)
+
+// ShortcutMsg asks the root model to run a shortcut, like opening a tab
+type ShortcutMsg struct {
+	Action string // Keymap action name, like "open_combat"
+}
+
+// entry is a line of the panel's menu: an item action or a shortcut
+type entry struct {
+	action  string      // Keymap action name
+	binding key.Binding // Keys that run the entry
+	label   string
+}
+
+type View struct {
+	itemID string // Item shown, empty for the shortcuts
+	worn   bool   // Whether the item shown is worn rather than in storage
+	cursor int
+	keyMap keys.KeyMap
+}
+
+// New creates and initializes a new details View
+func New(keyMap keys.KeyMap) View {
+	return View{keyMap: keyMap}
+}
+
+// Help lists the Details panel's keys for the help modal
+func Help(keyMap keys.KeyMap) keys.Section {
+	return keys.Section{Title: "Details", Bindings: []key.Binding{
+		keyMap.Up, keyMap.Down, keyMap.Confirm, keyMap.Back,
+		keyMap.Equip, keyMap.Use, keyMap.SellOne, keyMap.SellAll, keyMap.DropOne, keyMap.DropAll, keyMap.Split, keyMap.Merge,
+		keyMap.OpenCombat, keyMap.OpenGathering, keyMap.OpenCrafting, keyMap.OpenStorage,
+	}}
+}
+
+// shortcuts are shown when no item is picked
+func (v *View) shortcuts() []entry {
+	keyMap := v.keyMap
+	return []entry{
+		{action: "open_combat", binding: keyMap.OpenCombat, label: "Attack"},
+		{action: "open_gathering", binding: keyMap.OpenGathering, label: "Gather"},
+		{action: "open_crafting", binding: keyMap.OpenCrafting, label: "Craft"},
+		{action: "open_storage", binding: keyMap.OpenStorage, label: "Inventory"},
+	}
+}
+
+// Show picks an item to show
+func (v *View) Show(item game.Item) {
+	v.itemID, v.worn, v.cursor = item.ID, item.Equipped, 0
+}
+
+// Clear goes back to the shortcuts
+func (v *View) Clear() {
+	v.itemID, v.worn, v.cursor = "", false, 0
+}
+
+// Item returns the item shown with its total quantity, if it is still held
+// where it was picked from
+func (v *View) Item(gameState *game.State) (game.Item, bool) {
+	if v.itemID == "" {
+		return game.Item{}, false
+	}
+	if v.worn {
+		for _, item := range gameState.Equipment.Items() {
+			if item.ID == v.itemID {
+				return item, true
+			}
+		}
+		return game.Item{}, false
+	}
+	item, ok := gameState.Storage.Find(v.itemID)
+	item.Quantity = gameState.Storage.Quantity(v.itemID)
+	return item, ok
+}
+
+// entries returns the menu: the actions that apply to the item shown, or
+// the shortcuts
+func (v *View) entries(gameState *game.State) []entry {
+	item, ok := v.Item(gameState)
+	if !ok {
+		return v.shortcuts()
+	}
+	keyMap := v.keyMap
+	if item.Equipped {
+		return []entry{{action: "equip", binding: keyMap.Equip, label: "Unequip"}}
+	}
+
+	var entries []entry
+	if item.Slot != "" {
+		entries = append(entries, entry{action: "equip", binding: keyMap.Equip, label: "Equip"})
+	}
+	if gameState.CanUse(item.ID) == nil {
+		entries = append(entries, entry{action: "use", binding: keyMap.Use, label: "Use"})
+	}
+	if _, err := gameState.BestBuyer([]game.ItemAmount{{Item: item.ID, Quantity: 1}}); err == nil {
+		entries = append(entries, entry{action: "sell_one", binding: keyMap.SellOne, label: "Sell 1"})
+		if item.Quantity > 1 {
+			entries = append(entries, entry{action: "sell_all", binding: keyMap.SellAll, label: fmt.Sprintf("Sell all %d", item.Quantity)})
+		}
+	}
+	entries = append(entries, entry{action: "drop_one", binding: keyMap.DropOne, label: "Drop 1"})
+	if item.Quantity > 1 {
+		entries = append(entries, entry{action: "drop_all", binding: keyMap.DropAll, label: fmt.Sprintf("Drop all %d", item.Quantity)})
+		entries = append(entries, entry{action: "split", binding: keyMap.Split, label: "Split stack"})
+	}
+	if gameState.StackCount(item.ID) > 1 {
+		entries = append(entries, entry{action: "merge", binding: keyMap.Merge, label: "Merge stacks"})
+	}
+	return entries
+}
+
+// Update handles the panel's keys. Shortcuts are passed to the root model
+// as a ShortcutMsg.
+func (v *View) Update(msg tea.Msg, gameState *game.State) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+
+	entries := v.entries(gameState)
+	v.cursor = min(v.cursor, len(entries)-1)
+	keyMap := v.keyMap
+	var chosen entry
+	switch {
+	case key.Matches(keyMsg, keyMap.Up):
+		if v.cursor > 0 {
+			v.cursor--
+		}
+		return nil
+	case key.Matches(keyMsg, keyMap.Down):
+		if v.cursor < len(entries)-1 {
+			v.cursor++
+		}
+		return nil
+	case key.Matches(keyMsg, keyMap.Back):
+		v.Clear()
+		return nil
+	case key.Matches(keyMsg, keyMap.Confirm):
+		chosen = entries[v.cursor]
+	default:
+		for _, e := range entries {
+			if key.Matches(keyMsg, e.binding) {
+				chosen = e
+				break
+			}
+		}
+	}
+	if chosen.action == "" {
+		return nil
+	}
+
+	item, ok := v.Item(gameState)
+	if !ok {
+		return func() tea.Msg { return ShortcutMsg{Action: chosen.action} }
+	}
+	v.run(chosen.action, item, gameState)
+	return nil
+}
+
+// run performs an item action
+func (v *View) run(action string, item game.Item, gameState *game.State) {
+	var err error
+	switch action {
+	case "equip":
+		if item.Equipped {
+			err = gameState.UnequipItem(item.ID)
+		} else {
+			err = gameState.EquipItem(item.ID)
+		}
+		// Follow the item to or from its slot
+		if err == nil {
+			v.worn = !item.Equipped
+		}
+	case "use":
+		err = gameState.UseItem(item.ID)
+	case "sell_one", "sell_all":
+		qty := 1
+		if action == "sell_all" {
+			qty = item.Quantity
+		}
+		items := []game.ItemAmount{{Item: item.ID, Quantity: qty}}
+		var shop *game.Shop
+		if shop, err = gameState.BestBuyer(items); err == nil {
+			err = gameState.Sell(shop.ID, items)
+		}
+	case "drop_one", "drop_all":
+		qty := 1
+		if action == "drop_all" {
+			qty = item.Quantity
+		}
+		err = gameState.DropItem(item.ID, qty)
+	case "split":
+		err = gameState.SplitStack(item.ID)
+	case "merge":
+		err = gameState.MergeStacks(item.ID)
+	}
+	if err != nil {
+		gameState.Log("Storage", "Cannot do that:", err.Error())
+	}
+}
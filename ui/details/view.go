@@ -0,0 +1,114 @@
+package details
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/keys"
+	"github.com/jexxer/tbrpg/ui/styles"
+)
+
+// View renders the panel within width by height. The item's description
+// and stats are cut short first when the panel is too small.
+func (v *View) View(width, height int, focused bool, gameState *game.State) string {
+	titleStyle := styles.TitleStyle()
+	dimStyle := styles.DimStyle()
+	selectedStyle := styles.SelectedItemStyle()
+
+	var info []string
+	if item, ok := v.Item(gameState); ok {
+		info = v.renderItem(item, width, titleStyle, dimStyle, gameState)
+	}
+
+	entries := v.entries(gameState)
+	cursor := min(v.cursor, len(entries)-1)
+	var menu []string
+	for i, e := range entries {
+		line := fmt.Sprintf("[%s] %s", keys.Hint(e.binding), e.label)
+		if focused && i == cursor {
+			menu = append(menu, selectedStyle.Render("> "+line))
+		} else {
+			menu = append(menu, "  "+line)
+		}
+	}
+
+	if len(info) > 0 {
+		keep := max(1, height-len(menu)-1)
+		if len(info) > keep {
+			info = info[:keep]
+		}
+		info = append(info, "")
+	}
+	lines := append(info, menu...)
+	if len(lines) > height {
+		lines = lines[:max(0, height)]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderItem lists an item's name, status, category, amounts, tags,
+// effects and description, or its stats if it has no description
+func (v *View) renderItem(item game.Item, width int, titleStyle, dimStyle lipgloss.Style, gameState *game.State) []string {
+	wrap := lipgloss.NewStyle().Width(width)
+
+	status := "In storage"
+	if item.Equipped {
+		status = "Equipped"
+	} else if item.Slot != "" {
+		status = "Equippable"
+	}
+
+	lines := []string{
+		titleStyle.Render(truncate(item.Name, width)),
+		dimStyle.Render(truncate(item.Category+" · "+status, width)),
+		fmt.Sprintf("Qty %d  %dg each", item.Quantity, item.Value),
+		fmt.Sprintf("Total %dg", item.Quantity*item.Value),
+	}
+	if len(item.Tags) > 0 {
+		lines = append(lines, strings.Split(wrap.Render(dimStyle.Render("Tags: "+strings.Join(item.Tags, ", "))), "\n")...)
+	}
+	description := item.Description
+	if len(item.Effects) > 0 {
+		effects := make([]string, len(item.Effects))
+		for i, e := range item.Effects {
+			effects[i] = game.DescribeEffect(e)
+		}
+		use := "Use: " + strings.Join(effects, ", ")
+		if left := gameState.Cooldown(item.ID); left > 0 {
+			use += " · ready in " + game.FormatDuration(left)
+		}
+		lines = append(lines, strings.Split(wrap.Render(use), "\n")...)
+		// Food descriptions are often just the effect
+		if description == strings.Join(effects, ", ") {
+			description = ""
+		}
+	}
+	if description != "" {
+		lines = append(lines, strings.Split(wrap.Render(description), "\n")...)
+	}
+	// Descriptions of gear and consumables already spell out their stats
+	if item.Description == "" && len(item.Stats) > 0 {
+		stats := make([]string, 0, len(item.Stats))
+		for stat, value := range item.Stats {
+			stats = append(stats, fmt.Sprintf("%s %+d", stat, value))
+		}
+		sort.Strings(stats)
+		lines = append(lines, strings.Split(wrap.Render(strings.Join(stats, ", ")), "\n")...)
+	}
+	return lines
+}
+
+// truncate shortens s to width cells
+func truncate(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	r := []rune(s)
+	for len(r) > 0 && lipgloss.Width(string(r))+1 > width {
+		r = r[:len(r)-1]
+	}
+	return string(r) + "…"
+}
@@ -0,0 +1,137 @@
+// Package equipment provides the equipment tab component
+package equipment
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/keys"
+)
+
+// focus selects which list the cursor keys move
+type focus int
+
+const (
+	focusSlots focus = iota
+	focusGear
+)
+
+type View struct {
+	slotCursor int
+	gearCursor int
+	focus      focus
+	keyMap     keys.KeyMap
+}
+
+// slotRow is one line of the paperdoll. Slots holding several items, like
+// the tool belt, get one row per place.
+type slotRow struct {
+	info game.SlotInfo
+	item *game.ItemDef // nil when the place is empty
+}
+
+// New creates and initializes a new equipment View
+func New(keyMap keys.KeyMap) View {
+	return View{keyMap: keyMap}
+}
+
+// Help lists the equipment tab's keys for the help modal
+func Help(keyMap keys.KeyMap) keys.Section {
+	return keys.Section{Title: "Equipment", Bindings: []key.Binding{
+		keyMap.Up, keyMap.Down, keyMap.Left, keyMap.Right, keyMap.Confirm, keyMap.Equip, keyMap.Unequip,
+	}}
+}
+
+// rows lists every slot place and what is worn there
+func rows(gameState *game.State) []slotRow {
+	var rows []slotRow
+	for _, info := range game.EquipmentSlots {
+		worn := gameState.Equipment.Slot(info.Slot)
+		for i := range info.Capacity {
+			row := slotRow{info: info}
+			if i < len(worn) {
+				row.item = worn[i]
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// gear lists the storage items that fit a slot
+func gear(gameState *game.State, slot game.EquipSlot) []game.Item {
+	var items []game.Item
+	for _, item := range gameState.Storage.Totals() {
+		if item.Slot == string(slot) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// selectedRow returns the slot row under the cursor
+func (v *View) selectedRow(gameState *game.State) slotRow {
+	rows := rows(gameState)
+	v.slotCursor = clamp(v.slotCursor, len(rows))
+	return rows[v.slotCursor]
+}
+
+// Update handles equipment-specific updates
+func (v *View) Update(msg tea.Msg, gameState *game.State) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+
+	row := v.selectedRow(gameState)
+	available := gear(gameState, row.info.Slot)
+
+	keyMap := v.keyMap
+	switch {
+	case key.Matches(keyMsg, keyMap.Right):
+		v.focus = focusGear
+	case key.Matches(keyMsg, keyMap.Left):
+		v.focus = focusSlots
+	case key.Matches(keyMsg, keyMap.Up):
+		if v.focus == focusSlots {
+			v.slotCursor = clamp(v.slotCursor-1, len(rows(gameState)))
+			v.gearCursor = 0
+		} else {
+			v.gearCursor = clamp(v.gearCursor-1, len(available))
+		}
+	case key.Matches(keyMsg, keyMap.Down):
+		if v.focus == focusSlots {
+			v.slotCursor = clamp(v.slotCursor+1, len(rows(gameState)))
+			v.gearCursor = 0
+		} else {
+			v.gearCursor = clamp(v.gearCursor+1, len(available))
+		}
+	case key.Matches(keyMsg, keyMap.Confirm, keyMap.Equip):
+		if v.focus == focusSlots && key.Matches(keyMsg, keyMap.Confirm) {
+			v.focus = focusGear
+			return nil
+		}
+		if len(available) == 0 {
+			return nil
+		}
+		item := available[clamp(v.gearCursor, len(available))]
+		if err := gameState.EquipItem(item.ID); err != nil {
+			gameState.Log("Equipment", "Cannot equip:", err.Error())
+		}
+		v.gearCursor = clamp(v.gearCursor, len(gear(gameState, row.info.Slot)))
+	case key.Matches(keyMsg, keyMap.Unequip):
+		if row.item == nil {
+			return nil
+		}
+		if err := gameState.UnequipItem(row.item.ID); err != nil {
+			gameState.Log("Equipment", "Cannot unequip:", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// clamp keeps a cursor inside a list of n entries
+func clamp(cursor, n int) int {
+	return max(0, min(cursor, n-1))
+}
@@ -0,0 +1,93 @@
+package equipment
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/keys"
+	"github.com/jexxer/tbrpg/ui/styles"
+)
+
+// View renders the equipment view
+func (v *View) View(width, height int, gameState *game.State) string {
+	titleStyle := styles.TitleStyle()
+	dimStyle := styles.DimStyle()
+	selectedStyle := styles.SelectedItemStyle()
+	markStyle := dimStyle
+	if v.focus == focusSlots {
+		markStyle = selectedStyle
+	}
+
+	var b strings.Builder
+
+	// Slots
+	selected := v.selectedRow(gameState)
+	for i, row := range rows(gameState) {
+		worn := dimStyle.Render("(empty)")
+		if row.item != nil {
+			worn = row.item.Name + dimStyle.Render("  "+formatStats(row.item.Stats))
+		}
+		line := fmt.Sprintf("%-10s %s", row.info.Name, worn)
+		if i == v.slotCursor {
+			line = markStyle.Render("> ") + line
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(line + "\n")
+	}
+
+	// Totals
+	b.WriteString("\n" + dimStyle.Render("Total: ") + formatStats(gameState.Equipment.Stats()) + "\n")
+
+	// Gear in storage that fits the selected slot
+	b.WriteString("\n" + dimStyle.Render("Available for "+selected.info.Name+":") + "\n")
+	available := gear(gameState, selected.info.Slot)
+	if len(available) == 0 {
+		b.WriteString(dimStyle.Render("  nothing in storage") + "\n")
+	}
+	for i, item := range available {
+		line := fmt.Sprintf("%-16s x%-3d %s", item.Name, item.Quantity, dimStyle.Render(formatStats(item.Stats)))
+		if v.focus == focusGear && i == v.gearCursor {
+			line = selectedStyle.Render("> ") + line
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(line + "\n")
+	}
+
+	b.WriteString("\n" + dimStyle.Render(fmt.Sprintf("%s = Select  |  %s = Slots/Gear  |  %s = Equip  |  %s = Unequip",
+		keys.Hint(v.keyMap.Up, v.keyMap.Down), keys.Hint(v.keyMap.Left, v.keyMap.Right), keys.Hint(v.keyMap.Confirm, v.keyMap.Equip), keys.Hint(v.keyMap.Unequip))))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Render("Equipment"),
+		"",
+		b.String(),
+	)
+}
+
+// formatStats renders modifiers like "+25 ATK, +6 DEF" in a stable order
+func formatStats(stats map[string]int) string {
+	if len(stats) == 0 {
+		return "-"
+	}
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		label := name
+		if len(label) <= 3 {
+			label = strings.ToUpper(label)
+		} else {
+			label = strings.ToUpper(label[:1]) + label[1:]
+		}
+		parts[i] = fmt.Sprintf("%+d %s", stats[name], label)
+	}
+	return strings.Join(parts, ", ")
+}
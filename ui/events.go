@@ -0,0 +1,18 @@
+package ui
+
+import "github.com/jexxer/tbrpg/game"
+
+// staleViews records which cached views game events have made stale. Views
+// are refreshed by syncViews.
+type staleViews struct {
+	storage bool
+}
+
+// subscribe connects the UI to the game state's event bus. It must be
+// called again whenever the game state is replaced.
+func (m *Model) subscribe() {
+	stale := m.stale
+	game.On(m.GameState.Events, func(game.ItemAdded) { stale.storage = true })
+	game.On(m.GameState.Events, func(game.ItemRemoved) { stale.storage = true })
+	game.On(m.GameState.Events, func(game.StacksChanged) { stale.storage = true })
+}
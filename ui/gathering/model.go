@@ -0,0 +1,82 @@
+// Package gathering provides the gathering tab component
+package gathering
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/keys"
+)
+
+// queueCycles is how many cycles a queued gathering action runs
+const queueCycles = 10
+
+type View struct {
+	cursor int
+	keyMap keys.KeyMap
+}
+
+// New creates and initializes a new gathering View
+func New(keyMap keys.KeyMap) View {
+	return View{keyMap: keyMap}
+}
+
+// Help lists the gathering tab's keys for the help modal
+func Help(keyMap keys.KeyMap) keys.Section {
+	return keys.Section{Title: "Gathering", Bindings: []key.Binding{
+		keyMap.Up, keyMap.Down, keyMap.Gather, keyMap.QueueGather, keyMap.Stop,
+	}}
+}
+
+// SelectedNode returns the resource node under the cursor
+func (v *View) SelectedNode(gameState *game.State) (*game.ResourceNode, bool) {
+	nodes := gameState.NodesHere()
+	if len(nodes) == 0 {
+		return nil, false
+	}
+	// Travel changes the node list, so keep the cursor in range
+	v.cursor = min(v.cursor, len(nodes)-1)
+	return nodes[v.cursor], true
+}
+
+// Update handles gathering-specific updates
+func (v *View) Update(msg tea.Msg, gameState *game.State) tea.Cmd {
+	nodes := gameState.NodesHere()
+	keyMap := v.keyMap
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keyMap.Up):
+			if v.cursor > 0 {
+				v.cursor--
+			}
+		case key.Matches(msg, keyMap.Down):
+			if v.cursor < len(nodes)-1 {
+				v.cursor++
+			}
+		case key.Matches(msg, keyMap.Gather, keyMap.QueueGather):
+			node, ok := v.SelectedNode(gameState)
+			if !ok {
+				return nil
+			}
+
+			var err error
+			if key.Matches(msg, keyMap.Gather) {
+				err = gameState.StartAction(game.NewAction(game.ActionGather, node.ID, 0))
+			} else {
+				err = gameState.QueueAction(game.NewAction(game.ActionGather, node.ID, queueCycles))
+			}
+			if err != nil {
+				gameState.Log(string(node.Skill), "Cannot gather:", err.Error())
+			}
+
+		case key.Matches(msg, keyMap.Stop):
+			if action, ok := gameState.CancelAction(); ok {
+				gameState.Log("System", "Stopped "+gameState.ActionLabel(action), "")
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package gathering
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/keys"
+	"github.com/jexxer/tbrpg/ui/styles"
+)
+
+// View renders the gathering view
+func (v *View) View(width, height int, gameState *game.State) string {
+	titleStyle := styles.TitleStyle()
+	dimStyle := styles.DimStyle()
+	selectedStyle := styles.SelectedItemStyle()
+
+	var b strings.Builder
+
+	// Skill summary
+	for _, skill := range game.GatheringSkills {
+		level := gameState.SkillLevel(skill)
+		xp := gameState.SkillXP(skill)
+		progress := fmt.Sprintf("%d XP", xp)
+		if level < game.MaxSkillLevel {
+			progress = fmt.Sprintf("%d / %d XP", xp, game.XPForLevel(level+1))
+		}
+
+		line := fmt.Sprintf("%-12s Lv %-3d %s", skill, level, progress)
+		if bonus := gameState.ToolBonus(skill); bonus > 0 {
+			line += dimStyle.Render(fmt.Sprintf("  (tool +%d)", bonus))
+		}
+		b.WriteString(line + "\n")
+	}
+
+	// Resource nodes at the current location
+	nodes := gameState.NodesHere()
+	selected, _ := v.SelectedNode(gameState)
+	b.WriteString("\n" + dimStyle.Render("Nodes in "+gameState.CurrentLocation().Name) + "\n")
+	if len(nodes) == 0 {
+		b.WriteString(dimStyle.Render("  Nothing to gather here; travel to a forest, mine or fishing spot") + "\n")
+	} else {
+		b.WriteString(dimStyle.Render(fmt.Sprintf("  %-14s %-12s %-4s %-7s %s", "Node", "Skill", "Lvl", "Time", "Yields")) + "\n")
+	}
+	for _, node := range nodes {
+		yields := gameState.Catalog.ItemName(node.ItemID)
+
+		timing := fmt.Sprintf("%.1fs", gameState.GatherDuration(node).Seconds())
+		if gameState.CanGather(node) != nil {
+			timing = "locked"
+		}
+
+		line := fmt.Sprintf("%-14s %-12s %-4d %-7s %s", node.Name, node.Skill, node.Level, timing, yields)
+		switch {
+		case node.ID == selected.ID:
+			line = selectedStyle.Render("> " + line)
+		case timing == "locked":
+			line = dimStyle.Render("  " + line)
+		default:
+			line = "  " + line
+		}
+		b.WriteString(line + "\n")
+	}
+
+	b.WriteString("\n" + dimStyle.Render(fmt.Sprintf("%s = Select  |  %s = Gather  |  %s = Queue %d  |  %s = Stop",
+		keys.Hint(v.keyMap.Up, v.keyMap.Down), keys.Hint(v.keyMap.Gather), keys.Hint(v.keyMap.QueueGather), queueCycles, keys.Hint(v.keyMap.Stop))))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Render("Gathering"),
+		"",
+		b.String(),
+	)
+}
@@ -0,0 +1,526 @@
+// Package keys defines the keymap: named actions bound to keys. The
+// defaults can be overridden from a JSON config file that maps action names
+// to lists of keys, like {"sort": ["s"], "quit": ["q", "ctrl+c"]}. Typed
+// text, like searches, names and counts, is not part of the keymap; the
+// confirm and back keys end it.
+package keys
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// scope is where an action's keys are read. Actions in overlapping scopes
+// cannot share a key.
+type scope int
+
+const (
+	scopePanes         scope = 1 << iota // Read in every pane before the pane's own keys
+	scopeTabs                            // Left tab list
+	scopeStorage                         // Storage tab
+	scopeSavedSearches                   // Saved searches modal
+	scopeActivity                        // Activity log
+	scopeDetails                         // Details panel
+	scopeCombat                          // Combat screen
+	scopeEquipment                       // Equipment tab
+	scopeGathering                       // Gathering tab
+	scopeProcessing                      // Processing tab
+	scopeCrafting                        // Crafting tab
+	scopeQuests                          // Quests tab
+	scopeWorld                           // Navigation tab's world map
+	scopeMarket                          // Market tab
+	scopeModals                          // Help, offline summary and log categories modals
+)
+
+// scopeViews are the panes and tabs, which all read the pane keys first
+const scopeViews = scopeTabs | scopeStorage | scopeActivity | scopeDetails | scopeCombat |
+	scopeEquipment | scopeGathering | scopeProcessing | scopeCrafting | scopeQuests |
+	scopeWorld | scopeMarket
+
+// overlaps reports whether two scopes can see the same key press
+func (s scope) overlaps(other scope) bool {
+	expand := func(s scope) scope {
+		if s&scopePanes != 0 {
+			s |= scopeViews
+		}
+		return s
+	}
+	return expand(s)&expand(other) != 0
+}
+
+// KeyMap holds a binding per action
+type KeyMap struct {
+	// Every pane
+	Quit      key.Binding
+	Command   key.Binding
+	Help      key.Binding
+	NextPane  key.Binding
+	PaneLeft  key.Binding
+	PaneDown  key.Binding
+	PaneUp    key.Binding
+	PaneRight key.Binding
+
+	// Lists, menus and modals
+	Up      key.Binding
+	Down    key.Binding
+	Left    key.Binding
+	Right   key.Binding
+	Confirm key.Binding
+	Back    key.Binding
+
+	// Tab list
+	SelectTab key.Binding
+
+	// Storage
+	Categories     key.Binding
+	Table          key.Binding
+	Open           key.Binding
+	Mark           key.Binding
+	Sell           key.Binding
+	Search         key.Binding
+	SaveSearch     key.Binding
+	LoadSearch     key.Binding
+	Sort           key.Binding
+	ReverseSort    key.Binding
+	ToggleTotal    key.Binding
+	ToggleCategory key.Binding
+	ToggleTags     key.Binding
+	ToggleWorn     key.Binding
+
+	// Saved searches modal
+	RenameSearch key.Binding
+	DeleteSearch key.Binding
+	LoadNth      key.Binding // The nth key loads the nth search
+
+	// Other modals
+	Toggle  key.Binding
+	ShowAll key.Binding
+
+	// Activity log
+	LogSearch     key.Binding
+	NextMatch     key.Binding
+	PrevMatch     key.Binding
+	LogCategories key.Binding
+	Follow        key.Binding
+	PageUp        key.Binding
+	PageDown      key.Binding
+
+	// Details panel
+	Equip         key.Binding
+	Use           key.Binding
+	SellOne       key.Binding
+	SellAll       key.Binding
+	DropOne       key.Binding
+	DropAll       key.Binding
+	Split         key.Binding
+	Merge         key.Binding
+	OpenCombat    key.Binding
+	OpenGathering key.Binding
+	OpenCrafting  key.Binding
+	OpenStorage   key.Binding
+
+	// Combat
+	Attack   key.Binding
+	Defend   key.Binding
+	Flee     key.Binding
+	Monsters key.Binding
+
+	// Equipment
+	Unequip key.Binding
+
+	// Gathering
+	Gather      key.Binding
+	QueueGather key.Binding
+
+	// Crafting and processing
+	Queue    key.Binding
+	QueueMax key.Binding
+
+	// Gathering, crafting, processing and travel
+	Stop key.Binding
+
+	// World map
+	Travel key.Binding
+
+	// Quests
+	AcceptQuest  key.Binding
+	DeliverQuest key.Binding
+	AbandonQuest key.Binding
+
+	// Market
+	Trade    key.Binding
+	TradeAll key.Binding
+	BuyMode  key.Binding
+	SellMode key.Binding
+}
+
+// action is a named binding as it appears in the config file
+type action struct {
+	name    string
+	scope   scope
+	binding *key.Binding
+}
+
+// actions lists every binding in help order
+func (k *KeyMap) actions() []action {
+	return []action{
+		{"quit", scopePanes, &k.Quit},
+		{"command", scopePanes, &k.Command},
+		{"help", scopePanes, &k.Help},
+		{"next_pane", scopePanes, &k.NextPane},
+		{"pane_left", scopePanes, &k.PaneLeft},
+		{"pane_down", scopePanes, &k.PaneDown},
+		{"pane_up", scopePanes, &k.PaneUp},
+		{"pane_right", scopePanes, &k.PaneRight},
+		{"up", scopeViews | scopeSavedSearches | scopeModals, &k.Up},
+		{"down", scopeViews | scopeSavedSearches | scopeModals, &k.Down},
+		{"left", scopeEquipment | scopeProcessing | scopeMarket, &k.Left},
+		{"right", scopeEquipment | scopeProcessing | scopeMarket, &k.Right},
+		{"confirm", scopeDetails | scopeCombat | scopeEquipment | scopeSavedSearches | scopeModals, &k.Confirm},
+		{"back", scopeDetails | scopeCombat | scopeProcessing | scopeCrafting | scopeMarket | scopeSavedSearches | scopeModals, &k.Back},
+		{"select_tab", scopeTabs, &k.SelectTab},
+		{"categories", scopeStorage, &k.Categories},
+		{"table", scopeStorage, &k.Table},
+		{"open", scopeStorage, &k.Open},
+		{"mark", scopeStorage, &k.Mark},
+		{"sell", scopeStorage, &k.Sell},
+		{"search", scopeStorage, &k.Search},
+		{"save_search", scopeStorage, &k.SaveSearch},
+		{"load_search", scopeStorage | scopeSavedSearches, &k.LoadSearch},
+		{"sort", scopeStorage, &k.Sort},
+		{"reverse_sort", scopeStorage, &k.ReverseSort},
+		{"toggle_total", scopeStorage, &k.ToggleTotal},
+		{"toggle_category", scopeStorage, &k.ToggleCategory},
+		{"toggle_tags", scopeStorage, &k.ToggleTags},
+		{"toggle_worn", scopeStorage, &k.ToggleWorn},
+		{"rename_search", scopeSavedSearches, &k.RenameSearch},
+		{"delete_search", scopeSavedSearches, &k.DeleteSearch},
+		{"load_nth", scopeSavedSearches, &k.LoadNth},
+		{"toggle", scopeModals, &k.Toggle},
+		{"show_all", scopeModals, &k.ShowAll},
+		{"log_search", scopeActivity, &k.LogSearch},
+		{"next_match", scopeActivity, &k.NextMatch},
+		{"prev_match", scopeActivity, &k.PrevMatch},
+		{"log_categories", scopeActivity, &k.LogCategories},
+		{"follow", scopeActivity, &k.Follow},
+		{"page_up", scopeActivity, &k.PageUp},
+		{"page_down", scopeActivity, &k.PageDown},
+		{"equip", scopeDetails | scopeEquipment, &k.Equip},
+		{"use", scopeDetails, &k.Use},
+		{"sell_one", scopeDetails, &k.SellOne},
+		{"sell_all", scopeDetails, &k.SellAll},
+		{"drop_one", scopeDetails, &k.DropOne},
+		{"drop_all", scopeDetails, &k.DropAll},
+		{"split", scopeDetails, &k.Split},
+		{"merge", scopeDetails, &k.Merge},
+		{"open_combat", scopeDetails, &k.OpenCombat},
+		{"open_gathering", scopeDetails, &k.OpenGathering},
+		{"open_crafting", scopeDetails, &k.OpenCrafting},
+		{"open_storage", scopeDetails, &k.OpenStorage},
+		{"attack", scopeCombat, &k.Attack},
+		{"defend", scopeCombat, &k.Defend},
+		{"flee", scopeCombat, &k.Flee},
+		{"monsters", scopeCombat, &k.Monsters},
+		{"unequip", scopeEquipment, &k.Unequip},
+		{"gather", scopeGathering, &k.Gather},
+		{"queue_gather", scopeGathering, &k.QueueGather},
+		{"queue", scopeCrafting | scopeProcessing, &k.Queue},
+		{"queue_max", scopeCrafting | scopeProcessing, &k.QueueMax},
+		{"stop", scopeGathering | scopeCrafting | scopeProcessing | scopeWorld, &k.Stop},
+		{"travel", scopeWorld, &k.Travel},
+		{"accept_quest", scopeQuests, &k.AcceptQuest},
+		{"deliver_quest", scopeQuests, &k.DeliverQuest},
+		{"abandon_quest", scopeQuests, &k.AbandonQuest},
+		{"trade", scopeMarket, &k.Trade},
+		{"trade_all", scopeMarket, &k.TradeAll},
+		{"buy_mode", scopeMarket, &k.BuyMode},
+		{"sell_mode", scopeMarket, &k.SellMode},
+	}
+}
+
+// Named is an action's config file name with its binding
+type Named struct {
+	Name    string
+	Binding key.Binding
+}
+
+// All returns every action in help order
+func (k KeyMap) All() []Named {
+	actions := k.actions()
+	all := make([]Named, len(actions))
+	for i, a := range actions {
+		all[i] = Named{Name: a.name, Binding: *a.binding}
+	}
+	return all
+}
+
+// Default returns the built-in keymap
+func Default() KeyMap {
+	return KeyMap{
+		Quit:      bind("quit", "q", "ctrl+c"),
+		Command:   bind("command line", ":"),
+		Help:      bind("show help", "?"),
+		NextPane:  bind("cycle focus", "tab"),
+		PaneLeft:  bind("focus left pane", "H"),
+		PaneDown:  bind("focus pane below", "J"),
+		PaneUp:    bind("focus pane above", "K"),
+		PaneRight: bind("focus right pane", "L"),
+
+		Up:      bind("move up", "up", "k"),
+		Down:    bind("move down", "down", "j"),
+		Left:    bind("move left", "left", "h"),
+		Right:   bind("move right", "right", "l"),
+		Confirm: bind("select", "enter"),
+		Back:    bind("back", "esc"),
+
+		SelectTab: bind("open tab", "enter", " "),
+
+		Categories:     bind("categories", "left", "h"),
+		Table:          bind("item table", "right", "l"),
+		Open:           bind("show item", "enter"),
+		Mark:           bind("mark to sell", " "),
+		Sell:           bind("sell marked", "$"),
+		Search:         bind("search", "/"),
+		SaveSearch:     bind("save search", "S"),
+		LoadSearch:     bind("load search", "O"),
+		Sort:           bind("next sort", "s"),
+		ReverseSort:    bind("reverse sort", "r"),
+		ToggleTotal:    bind("total column", "V"),
+		ToggleCategory: bind("category column", "C"),
+		ToggleTags:     bind("tags column", "T"),
+		ToggleWorn:     bind("worn column", "E"),
+
+		RenameSearch: bind("rename", "r"),
+		DeleteSearch: bind("delete", "d", "x"),
+		LoadNth:      bind("load by number", "1", "2", "3", "4", "5", "6", "7", "8", "9"),
+
+		Toggle:  bind("toggle", " "),
+		ShowAll: bind("show all", "a"),
+
+		LogSearch:     bind("search", "/"),
+		NextMatch:     bind("next match", "n"),
+		PrevMatch:     bind("previous match", "N"),
+		LogCategories: bind("categories", "c"),
+		Follow:        bind("follow", "f"),
+		PageUp:        bind("page up", "pgup"),
+		PageDown:      bind("page down", "pgdown"),
+
+		Equip:         bind("equip", "e"),
+		Use:           bind("use", "u"),
+		SellOne:       bind("sell one", "s"),
+		SellAll:       bind("sell all", "S"),
+		DropOne:       bind("drop one", "d"),
+		DropAll:       bind("drop all", "D"),
+		Split:         bind("split stack", "p"),
+		Merge:         bind("merge stacks", "m"),
+		OpenCombat:    bind("attack", "a"),
+		OpenGathering: bind("gather", "g"),
+		OpenCrafting:  bind("craft", "c"),
+		OpenStorage:   bind("inventory", "i"),
+
+		Attack:   bind("attack", "a"),
+		Defend:   bind("defend", "d"),
+		Flee:     bind("flee", "f"),
+		Monsters: bind("monsters", "m"),
+
+		Unequip: bind("unequip", "x", "u"),
+
+		Gather:      bind("gather", "enter"),
+		QueueGather: bind("queue 10", "a"),
+
+		Queue:    bind("queue", "enter"),
+		QueueMax: bind("queue max", "m"),
+
+		Stop: bind("stop", "x"),
+
+		Travel: bind("travel", "enter"),
+
+		AcceptQuest:  bind("accept", "enter", "a"),
+		DeliverQuest: bind("deliver", "d"),
+		AbandonQuest: bind("abandon", "x"),
+
+		Trade:    bind("buy/sell", "enter"),
+		TradeAll: bind("buy/sell all", "a"),
+		BuyMode:  bind("buy mode", "b"),
+		SellMode: bind("sell mode", "s"),
+	}
+}
+
+// bind creates a binding whose help shows its keys
+func bind(desc string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(Label(keys), desc))
+}
+
+// Label renders keys for help text, like "↑/k", "space" or "1-9"
+func Label(keys []string) string {
+	if digitRun(keys) {
+		return keys[0] + "-" + keys[len(keys)-1]
+	}
+	names := map[string]string{"up": "↑", "down": "↓", "left": "←", "right": "→", " ": "space"}
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		if name, ok := names[k]; ok {
+			k = name
+		}
+		labels[i] = k
+	}
+	return strings.Join(labels, "/")
+}
+
+// digitRun reports whether keys are three or more consecutive digits
+func digitRun(keys []string) bool {
+	if len(keys) < 3 {
+		return false
+	}
+	for i, k := range keys {
+		if len(k) != 1 || k[0] < '0' || k[0] > '9' || (i > 0 && k[0] != keys[i-1][0]+1) {
+			return false
+		}
+	}
+	return true
+}
+
+// Hint returns the keys of bindings for inline hints, like "↑/k ↓/j"
+func Hint(bindings ...key.Binding) string {
+	labels := make([]string, len(bindings))
+	for i, b := range bindings {
+		labels[i] = b.Help().Key
+	}
+	return strings.Join(labels, " ")
+}
+
+// Path returns the keymap config file. TBRPG_KEYS overrides the default
+// location under the user config directory.
+func Path() (string, error) {
+	if path := os.Getenv("TBRPG_KEYS"); path != "" {
+		return path, nil
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locating keymap: %w", err)
+	}
+	return filepath.Join(configDir, "tbrpg", "keys.json"), nil
+}
+
+// Load reads the keymap from a config file, starting from the defaults.
+// A missing file gives the defaults. On any problem the defaults are
+// returned along with an error listing every problem found.
+func Load(path string) (KeyMap, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Default(), nil
+	}
+	if err != nil {
+		return Default(), err
+	}
+
+	var overrides map[string][]string
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return Default(), fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	keyMap := Default()
+	if err := keyMap.apply(overrides); err != nil {
+		return Default(), fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return keyMap, nil
+}
+
+// apply rebinds the actions named in overrides and checks the result for
+// conflicts. An empty key list unbinds an action.
+func (k *KeyMap) apply(overrides map[string][]string) error {
+	var problems []string
+	actions := k.actions()
+	for name, keys := range overrides {
+		i := slices.IndexFunc(actions, func(a action) bool { return a.name == name })
+		if i < 0 {
+			problems = append(problems, fmt.Sprintf("unknown action %q", name))
+			continue
+		}
+		for j, pressed := range keys {
+			// Spaces are easy to lose in a config file
+			if pressed == "space" {
+				keys[j] = " "
+			}
+		}
+		b := actions[i].binding
+		b.SetKeys(keys...)
+		b.SetHelp(Label(keys), b.Help().Desc)
+		b.SetEnabled(len(keys) > 0)
+	}
+	slices.Sort(problems)
+	problems = append(problems, k.typed()...)
+	problems = append(problems, k.conflicts()...)
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// typed describes confirm and back keys that would be typed into text
+// fields instead of ending them
+func (k *KeyMap) typed() []string {
+	var problems []string
+	for _, a := range []action{{name: "confirm", binding: &k.Confirm}, {name: "back", binding: &k.Back}} {
+		for _, pressed := range a.binding.Keys() {
+			if utf8.RuneCountInString(pressed) == 1 {
+				problems = append(problems, fmt.Sprintf("%s ends text entry, so it cannot be %q", a.name, Label([]string{pressed})))
+			}
+		}
+	}
+	return problems
+}
+
+// conflicts describes every key bound to two actions that can see it
+func (k *KeyMap) conflicts() []string {
+	var problems []string
+	actions := k.actions()
+	for i, a := range actions {
+		for _, b := range actions[i+1:] {
+			if !a.scope.overlaps(b.scope) {
+				continue
+			}
+			for _, shared := range a.binding.Keys() {
+				if slices.Contains(b.binding.Keys(), shared) {
+					problems = append(problems, fmt.Sprintf("%q is bound to both %s and %s", Label([]string{shared}), a.name, b.name))
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// Section is a titled column of a help screen
+type Section struct {
+	Title    string
+	Bindings []key.Binding
+}
+
+// General returns the keys read in every pane
+func (k KeyMap) General() Section {
+	return Section{"General", []key.Binding{k.NextPane, k.PaneLeft, k.PaneDown, k.PaneUp, k.PaneRight, k.Command, k.Help, k.Quit}}
+}
+
+// ShortHelp returns the bindings worth a reminder, for help.KeyMap
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Help, k.Command, k.NextPane, k.Quit}
+}
+
+// FullHelp returns the storage help screen's columns, for help.KeyMap:
+// general keys, storage navigation, search and sorting, and columns
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		k.General().Bindings,
+		{k.Up, k.Down, k.Categories, k.Table, k.Open, k.Mark, k.Sell},
+		{k.Search, k.SaveSearch, k.LoadSearch, k.Sort, k.ReverseSort},
+		{k.ToggleTotal, k.ToggleCategory, k.ToggleTags, k.ToggleWorn},
+	}
+}
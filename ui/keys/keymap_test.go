@@ -0,0 +1,65 @@
+package keys
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDefaultHasNoConflicts(t *testing.T) {
+	k := Default()
+	for _, problem := range append(k.typed(), k.conflicts()...) {
+		t.Error(problem)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string // Empty for no file
+		action func(KeyMap) []string
+		keys   []string
+		err    string
+	}{
+		{name: "missing file", action: func(k KeyMap) []string { return k.Quit.Keys() }, keys: []string{"q", "ctrl+c"}},
+		{name: "rebind", config: `{"quit": ["Q"]}`, action: func(k KeyMap) []string { return k.Quit.Keys() }, keys: []string{"Q"}},
+		{name: "space", config: `{"mark": ["space", "m"]}`, action: func(k KeyMap) []string { return k.Mark.Keys() }, keys: []string{" ", "m"}},
+		{name: "separate scopes share keys", config: `{"flee": ["s"]}`, action: func(k KeyMap) []string { return k.Flee.Keys() }, keys: []string{"s"}},
+		{name: "unbind", config: `{"follow": []}`, action: func(k KeyMap) []string { return k.Follow.Keys() }, keys: []string{}},
+		{name: "conflict with a pane key", config: `{"quit": ["e"]}`, err: `keys.json: "e" is bound to both quit and equip`},
+		{name: "conflict within a scope", config: `{"attack": ["d"]}`, err: `keys.json: "d" is bound to both attack and defend`},
+		{name: "conflict across shared scopes", config: `{"up": ["x"]}`, err: `"x" is bound to both up and delete_search`},
+		{name: "typed confirm", config: `{"confirm": ["y"]}`, err: `confirm ends text entry, so it cannot be "y"`},
+		{name: "unknown action", config: `{"dance": ["z"]}`, err: `keys.json: unknown action "dance"`},
+		{name: "malformed", config: `{"quit": "q"}`, err: "keys.json: json: cannot unmarshal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "keys.json")
+			if tt.config != "" {
+				if err := os.WriteFile(path, []byte(tt.config), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			k, err := Load(path)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("got error %v, want one containing %q", err, tt.err)
+				}
+				// A rejected file leaves every default in place
+				if !slices.Equal(k.Quit.Keys(), Default().Quit.Keys()) {
+					t.Errorf("quit is %v after a rejected file", k.Quit.Keys())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := tt.action(k); !slices.Equal(got, tt.keys) {
+				t.Errorf("keys %q, want %q", got, tt.keys)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+// Package market provides the market tab component
+package market
+
+import (
+	"strconv"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/keys"
+	"github.com/jexxer/tbrpg/ui/shared"
+)
+
+// maxCount caps the typed trade count
+const maxCount = 9999
+
+type View struct {
+	shop    int  // Index into the shops at the current location
+	selling bool // Sell list instead of the shop's stock
+	cursor  int
+	count   string // Digits typed before trading; empty means 1
+	keyMap  keys.KeyMap
+}
+
+// row is one line of the buy or sell list
+type row struct {
+	def   *game.ItemDef
+	price int
+	stock int // Shop stock when buying, storage quantity when selling
+}
+
+// New creates and initializes a new market View
+func New(keyMap keys.KeyMap) View {
+	return View{keyMap: keyMap}
+}
+
+// Help lists the market tab's keys for the help modal
+func Help(keyMap keys.KeyMap) keys.Section {
+	return keys.Section{Title: "Market", Bindings: []key.Binding{
+		keyMap.Left, keyMap.Right, keyMap.BuyMode, keyMap.SellMode, keyMap.Up, keyMap.Down, keyMap.Trade, keyMap.TradeAll, keyMap.Back,
+	}}
+}
+
+// SelectedShop returns the shop being viewed
+func (v *View) SelectedShop(gameState *game.State) (*game.Shop, bool) {
+	shops := gameState.ShopsHere()
+	if len(shops) == 0 {
+		return nil, false
+	}
+	// Travel changes the shop list, so keep the index in range
+	v.shop = min(v.shop, len(shops)-1)
+	return shops[v.shop], true
+}
+
+// rows lists what the selected shop sells, or what it will buy from storage
+func (v *View) rows(gameState *game.State) []row {
+	shop, ok := v.SelectedShop(gameState)
+	if !ok {
+		return nil
+	}
+
+	var rows []row
+	if !v.selling {
+		for _, stock := range shop.Stock {
+			def, _ := gameState.Catalog.Item(stock.Item)
+			rows = append(rows, row{def: def, price: game.BuyPrice(shop, def), stock: gameState.Market.InStock(shop, stock.Item)})
+		}
+		return rows
+	}
+	for _, item := range gameState.Storage.Totals() {
+		if price := game.SellPrice(shop, item.ItemDef); shop.WillBuy(item.ItemDef) && price > 0 {
+			rows = append(rows, row{def: item.ItemDef, price: price, stock: item.Quantity})
+		}
+	}
+	return rows
+}
+
+// selected returns the row under the cursor
+func (v *View) selected(gameState *game.State) (row, bool) {
+	rows := v.rows(gameState)
+	if len(rows) == 0 {
+		return row{}, false
+	}
+	// Selling can empty a row, so keep the cursor in range
+	v.cursor = min(v.cursor, len(rows)-1)
+	return rows[v.cursor], true
+}
+
+// Count returns how many units enter will trade
+func (v *View) Count() int {
+	n, err := strconv.Atoi(v.count)
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+// Update handles market-specific updates
+func (v *View) Update(msg tea.Msg, gameState *game.State) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+
+	keyMap := v.keyMap
+	switch {
+	case key.Matches(keyMsg, keyMap.Left):
+		if v.shop > 0 {
+			v.shop--
+			v.cursor = 0
+		}
+	case key.Matches(keyMsg, keyMap.Right):
+		if v.shop < len(gameState.ShopsHere())-1 {
+			v.shop++
+			v.cursor = 0
+		}
+	case key.Matches(keyMsg, keyMap.BuyMode, keyMap.SellMode):
+		v.selling = key.Matches(keyMsg, keyMap.SellMode)
+		v.cursor = 0
+	case key.Matches(keyMsg, keyMap.Up):
+		if v.cursor > 0 {
+			v.cursor--
+		}
+	case key.Matches(keyMsg, keyMap.Down):
+		if v.cursor < len(v.rows(gameState))-1 {
+			v.cursor++
+		}
+	case key.Matches(keyMsg, keyMap.Trade, keyMap.TradeAll):
+		shop, ok := v.SelectedShop(gameState)
+		if !ok {
+			return nil
+		}
+		r, ok := v.selected(gameState)
+		if !ok {
+			return nil
+		}
+		count := v.Count()
+		if key.Matches(keyMsg, keyMap.TradeAll) {
+			// All of the stock or storage, as far as gold allows when buying
+			count = r.stock
+			if !v.selling {
+				// Try at least one so Buy can explain the cost or lack of room
+				count = max(1, min(count, gameState.Player.Gold/r.price, gameState.Storage.Room(r.def.ID)))
+			}
+		}
+		v.count = ""
+
+		var err error
+		if v.selling {
+			err = gameState.Sell(shop.ID, []game.ItemAmount{{Item: r.def.ID, Quantity: count}})
+		} else {
+			err = gameState.Buy(shop.ID, r.def.ID, count)
+		}
+		if err != nil {
+			gameState.Log("Market", "Cannot trade:", err.Error())
+		}
+	case key.Matches(keyMsg, keyMap.Back):
+		v.count = ""
+	default:
+		v.count = shared.TypeCount(v.count, keyMsg.String(), maxCount)
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package market
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/keys"
+	"github.com/jexxer/tbrpg/ui/styles"
+)
+
+const (
+	historyLines = 5  // Recent trades shown
+	chromeLines  = 13 // Lines besides the item rows and trades
+)
+
+// View renders the market view
+func (v *View) View(width, height int, gameState *game.State) string {
+	titleStyle := styles.TitleStyle()
+	dimStyle := styles.DimStyle()
+	selectedStyle := styles.SelectedItemStyle()
+
+	var b strings.Builder
+
+	shop, ok := v.SelectedShop(gameState)
+	if !ok {
+		b.WriteString(dimStyle.Render("There is no market in "+gameState.CurrentLocation().Name) + "\n")
+	} else {
+		// Shop tabs
+		var tabs []string
+		for i, s := range gameState.ShopsHere() {
+			if i == v.shop {
+				tabs = append(tabs, selectedStyle.Render("["+s.Name+"]"))
+			} else {
+				tabs = append(tabs, dimStyle.Render(" "+s.Name+" "))
+			}
+		}
+		b.WriteString(strings.Join(tabs, " ") + "\n")
+
+		mode, other := "Buying", keys.Hint(v.keyMap.SellMode)+" = Sell"
+		if v.selling {
+			mode, other = "Selling", keys.Hint(v.keyMap.BuyMode)+" = Buy"
+		}
+		b.WriteString(fmt.Sprintf("%s  |  Gold: %dg", mode, gameState.Player.Gold) + dimStyle.Render(fmt.Sprintf("  (%d%% markup, pays %d%%)", shop.Markup, shop.Payout)) + "\n")
+
+		stockTitle := "Stock"
+		if v.selling {
+			stockTitle = "Have"
+		}
+		b.WriteString("\n" + dimStyle.Render(fmt.Sprintf("  %-18s %-7s %s", "Item", "Price", stockTitle)) + "\n")
+		rows := v.rows(gameState)
+		if len(rows) == 0 {
+			if v.selling {
+				b.WriteString(dimStyle.Render("  Nothing in storage "+shop.Name+" will buy") + "\n")
+			} else {
+				b.WriteString(dimStyle.Render("  Nothing for sale") + "\n")
+			}
+		}
+		// Show a window of rows that follows the cursor; the rest of the
+		// view takes about chromeLines
+		current, _ := v.selected(gameState)
+		ws := styles.GetWindowSizes(width, height)
+		visible := max(3, ws.MainPanel.Height-ws.BorderOffset-chromeLines-historyLines)
+		start := min(max(0, v.cursor-visible/2), max(0, len(rows)-visible))
+		end := min(len(rows), start+visible)
+		if start > 0 {
+			b.WriteString(dimStyle.Render(fmt.Sprintf("  ↑ %d more", start)) + "\n")
+		}
+		for _, r := range rows[start:end] {
+			line := fmt.Sprintf("%-18s %-7s %d", r.def.Name, fmt.Sprintf("%dg", r.price), r.stock)
+			switch {
+			case r.def == current.def:
+				line = selectedStyle.Render("> " + line)
+			case r.stock == 0 || (!v.selling && r.price > gameState.Player.Gold):
+				line = dimStyle.Render("  " + line)
+			default:
+				line = "  " + line
+			}
+			b.WriteString(line + "\n")
+		}
+		if end < len(rows) {
+			b.WriteString(dimStyle.Render(fmt.Sprintf("  ↓ %d more", len(rows)-end)) + "\n")
+		}
+
+		verb := "Buy"
+		if v.selling {
+			verb = "Sell"
+		}
+		b.WriteString("\n" + dimStyle.Render(fmt.Sprintf("Count: %d  |  0-9 = Count  |  %s = %s  |  %s = %s all",
+			v.Count(), keys.Hint(v.keyMap.Trade), verb, keys.Hint(v.keyMap.TradeAll), verb)) + "\n")
+		b.WriteString(dimStyle.Render(fmt.Sprintf("%s  |  %s = Shop", other, keys.Hint(v.keyMap.Left, v.keyMap.Right))) + "\n")
+	}
+
+	// Recent trades, newest first
+	history := gameState.Market.History
+	b.WriteString("\n" + dimStyle.Render("Recent trades:") + "\n")
+	if len(history) == 0 {
+		b.WriteString(dimStyle.Render("  none") + "\n")
+	}
+	for i := len(history) - 1; i >= max(0, len(history)-historyLines); i-- {
+		t := history[i]
+		shopName := t.Shop
+		if s, ok := gameState.Catalog.Shop(t.Shop); ok {
+			shopName = s.Name
+		}
+		verb, sign := "Bought", "-"
+		if t.Kind == game.TradeSell {
+			verb, sign = "Sold", "+"
+		}
+		b.WriteString(fmt.Sprintf("  %s %-6s %d %-16s %s%dg", t.Timestamp.Format("15:04"), verb, t.Quantity,
+			gameState.Catalog.ItemName(t.Item), sign, t.Gold) + dimStyle.Render("  "+shopName) + "\n")
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Render("Market"),
+		"",
+		b.String(),
+	)
+}
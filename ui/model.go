@@ -1,15 +1,22 @@
 package ui
 
 import (
-	"fmt"
-	"io"
+	"time"
 
-	"github.com/charmbracelet/bubbles/list"
-	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/combat"
+	"github.com/jexxer/tbrpg/ui/crafting"
+	"github.com/jexxer/tbrpg/ui/details"
+	"github.com/jexxer/tbrpg/ui/equipment"
+	"github.com/jexxer/tbrpg/ui/gathering"
+	"github.com/jexxer/tbrpg/ui/keys"
+	"github.com/jexxer/tbrpg/ui/market"
+	"github.com/jexxer/tbrpg/ui/processing"
+	"github.com/jexxer/tbrpg/ui/quests"
 	"github.com/jexxer/tbrpg/ui/shared"
 	"github.com/jexxer/tbrpg/ui/storage"
+	"github.com/jexxer/tbrpg/ui/world"
 )
 
 type FocusedView int
@@ -31,133 +38,142 @@ type Model struct {
 	// Game state
 	GameState *game.State
 
+	// Command line interpreter
+	commands *CommandRegistry
+
+	// Key bindings, from the keymap config file or the defaults
+	keyMap keys.KeyMap
+
+	// Persistence
+	saveDir  string // Empty when saving is unavailable
+	saveSlot string
+	autosave bool // Save to saveSlot on quit
+
+	// Time of the last game clock tick
+	lastTick time.Time
+
+	// Progress made while away, shown after loading a save
+	offlineSummary game.OfflineSummary
+
+	// Views made stale by game events, shared by every copy of the model
+	stale *staleViews
+
 	// View components
 	navigation shared.NavigationView
 	storage    storage.View
+	equipment  equipment.View
+	combat     combat.View
+	gathering  gathering.View
+	processing processing.View
+	crafting   crafting.View
+	quests     quests.View
+	world      world.View
+	market     market.View
+	details    details.View
 	activity   shared.ActivityView
 	command    shared.CommandView
 	modal      shared.ModalView
-
-	// Legacy components (to be refactored later)
-	detailsList    list.Model
-	resourcesTable table.Model
-}
-
-type listItem struct {
-	title string
 }
 
-func (i listItem) Title() string       { return i.title }
-func (i listItem) FilterValue() string { return i.title }
+// InitialModel builds the root model using content from the catalog
+func InitialModel(catalog *game.Catalog) Model {
+	// Initialize game state from the default save slot
+	saveDir, saveDirErr := game.SaveDir()
+	gameState, loadErr := loadStartupState(saveDir, catalog)
+	keyMap, keysErr := loadKeyMap()
+	themesErr := loadThemes()
 
-type compactDelegate struct{}
+	// Initialize view components
+	navigation := shared.NewNavigationView(keyMap)
+	storageView := storage.New(catalog.Categories(), keyMap)
+	equipmentView := equipment.New(keyMap)
+	combatView := combat.New(keyMap)
+	gatheringView := gathering.New(keyMap)
+	processingView := processing.New(keyMap)
+	craftingView := crafting.New(keyMap)
+	questsView := quests.New(keyMap)
+	worldView := world.New(keyMap)
+	marketView := market.New(keyMap)
+	activity := shared.NewActivityView(keyMap)
+	command := shared.NewCommandView(keyMap)
+	modal := shared.NewModalView()
 
-func (d compactDelegate) Height() int                               { return 1 }
-func (d compactDelegate) Spacing() int                              { return 0 }
-func (d compactDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd { return nil }
-func (d compactDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
-	i, ok := item.(listItem)
-	if !ok {
-		return
+	m := Model{
+		Width:       80,
+		Height:      24,
+		FocusedView: FocusGameView,
+		ActiveTab:   0,
+		GameState:   gameState,
+		stale:       &staleViews{},
+		commands:    defaultCommands(),
+		keyMap:      keyMap,
+		saveDir:     saveDir,
+		saveSlot:    game.DefaultSlot,
+		autosave:    saveDirErr == nil && loadErr == nil,
+		navigation:  navigation,
+		storage:     storageView,
+		equipment:   equipmentView,
+		combat:      combatView,
+		gathering:   gatheringView,
+		processing:  processingView,
+		crafting:    craftingView,
+		quests:      questsView,
+		world:       worldView,
+		market:      marketView,
+		activity:    activity,
+		command:     command,
+		modal:       modal,
+		details:     details.New(keyMap),
 	}
 
-	str := i.title
+	m.subscribe()
 
-	// Highlight selected item (placeholder for legacy components)
-	if index == m.Index() {
-		str = "> " + str
-	} else {
-		str = "  " + str
+	// Report persistence problems without clobbering an unreadable save
+	if saveDirErr != nil {
+		gameState.Log("System", "Saving disabled:", saveDirErr.Error())
 	}
-
-	fmt.Fprint(w, str)
-}
-
-func InitialModel() Model {
-	// Setup details list (legacy component - to be refactored)
-	detailsItems := []list.Item{
-		listItem{title: "[A]ttack"},
-		listItem{title: "[G]ather"},
-		listItem{title: "[C]raft"},
-		listItem{title: "[I]nventory"},
+	if loadErr != nil {
+		gameState.Log("System", "Failed to load save:", loadErr.Error()+" (autosave disabled)")
 	}
-
-	detailsList := list.New(detailsItems, compactDelegate{}, 20, 5)
-	detailsList.SetShowHelp(false)
-	detailsList.SetShowStatusBar(false)
-	detailsList.SetFilteringEnabled(false)
-	detailsList.SetShowTitle(false)
-
-	// Setup resources table (legacy component - to be refactored)
-	columns := []table.Column{
-		{Title: "Resource", Width: 15},
-		{Title: "Quantity", Width: 10},
-	}
-
-	rows := []table.Row{
-		{"Wood", "150"},
-		{"Iron Ore", "45"},
-		{"Coal", "23"},
-		{"Fish", "12"},
-		{"Stone", "89"},
-		{"Gold", "1,234g"},
+	if keysErr != nil {
+		gameState.Log("System", "Failed to load keymap:", keysErr.Error()+" (using default keys)")
 	}
-
-	resourcesTable := table.New(
-		table.WithColumns(columns),
-		table.WithRows(rows),
-		table.WithFocused(false),
-		table.WithHeight(7),
-	)
-
-	// Initialize game state
-	gameState := game.NewState()
-
-	// Initialize view components
-	navigation := shared.NewNavigationView()
-	storageView := storage.New()
-	activity := shared.NewActivityView()
-	command := shared.NewCommandView()
-	modal := shared.NewModalView()
-
-	m := Model{
-		Width:          80,
-		Height:         24,
-		FocusedView:    FocusGameView,
-		ActiveTab:      0,
-		GameState:      gameState,
-		navigation:     navigation,
-		storage:        storageView,
-		activity:       activity,
-		command:        command,
-		modal:          modal,
-		detailsList:    detailsList,
-		resourcesTable: resourcesTable,
+	if themesErr != nil {
+		gameState.Log("System", "Failed to load themes:", themesErr.Error())
 	}
+	m.applyTheme()
 
-	// Initialize activity log
-	m.activity.UpdateContent(gameState)
-	m.activity.GotoBottom()
+	m.applyOfflineProgress()
 
-	// Update storage table with all items initially
-	m.storage.UpdateTable(gameState)
+	// Initialize activity log and storage table
+	m.refreshViews()
 
 	return m
 }
 
 func (m Model) Init() tea.Cmd {
-	return nil
+	return tickCmd()
 }
 
 // AddLogEntry adds an entry to the activity log
 func (m *Model) AddLogEntry(category, action, details string) {
-	m.GameState.ActivityLog.AddEntry(category, action, details)
+	m.GameState.Log(category, action, details)
+	m.syncViews()
+}
 
-	// Update activity view content
-	m.activity.UpdateContent(m.GameState)
+// syncViews refreshes views after game logic has written to the log or
+// published events that make them stale
+func (m *Model) syncViews() {
+	if m.stale.storage {
+		m.stale.storage = false
+		m.storage.UpdateTable(m.GameState)
+	}
 
-	// Auto-scroll to bottom if not focused
-	if m.FocusedView != FocusActivityLog {
-		m.activity.GotoBottom()
+	if !m.activity.IsStale(m.GameState) {
+		return
 	}
+
+	// Update activity view content, which scrolls to new entries when
+	// following
+	m.activity.UpdateContent(m.GameState)
 }
@@ -0,0 +1,129 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/keys"
+	"github.com/jexxer/tbrpg/ui/shared"
+	"github.com/jexxer/tbrpg/ui/styles"
+)
+
+// loadStartupState loads the default save slot, falling back to a new game
+// when there is no save yet
+func loadStartupState(saveDir string, catalog *game.Catalog) (*game.State, error) {
+	if saveDir == "" {
+		return game.NewState(catalog), nil
+	}
+
+	state, err := game.LoadSlot(saveDir, game.DefaultSlot, catalog)
+	if errors.Is(err, game.ErrNoSave) {
+		return game.NewState(catalog), nil
+	}
+	if err != nil {
+		return game.NewState(catalog), err
+	}
+	return state, nil
+}
+
+// loadKeyMap reads the keymap config file, falling back to the default keys
+func loadKeyMap() (keys.KeyMap, error) {
+	path, err := keys.Path()
+	if err != nil {
+		return keys.Default(), err
+	}
+	return keys.Load(path)
+}
+
+// loadThemes adds the theme files in the theme directory
+func loadThemes() error {
+	dir, err := styles.ThemeDir()
+	if err != nil {
+		return err
+	}
+	return styles.LoadThemeDir(dir)
+}
+
+// applyTheme switches to the game state's theme, falling back to the
+// default theme if it is no longer available
+func (m *Model) applyTheme() {
+	name := m.GameState.Theme
+	if name == "" {
+		name = styles.DefaultTheme
+	}
+	if err := styles.SetTheme(name); err != nil {
+		m.GameState.Log("System", "Failed to load theme:", err.Error())
+		styles.SetTheme(styles.DefaultTheme)
+		m.GameState.Theme = ""
+	}
+}
+
+// saveGame writes the game state to a save slot
+func (m *Model) saveGame(slot string) error {
+	if m.saveDir == "" {
+		return errors.New("saving is unavailable: no save directory")
+	}
+	if err := m.GameState.SaveSlot(m.saveDir, slot); err != nil {
+		return err
+	}
+	m.saveSlot = slot
+	m.autosave = true
+	return nil
+}
+
+// loadGame replaces the game state with the contents of a save slot
+func (m *Model) loadGame(slot string) error {
+	if m.saveDir == "" {
+		return errors.New("loading is unavailable: no save directory")
+	}
+	state, err := game.LoadSlot(m.saveDir, slot, m.GameState.Catalog)
+	if errors.Is(err, game.ErrNoSave) {
+		return fmt.Errorf("no save in slot %q", slot)
+	}
+	if err != nil {
+		return err
+	}
+
+	m.GameState = state
+	m.subscribe()
+	m.applyTheme()
+	m.saveSlot = slot
+	m.autosave = true
+	m.applyOfflineProgress()
+	m.refreshViews()
+	return nil
+}
+
+// applyOfflineProgress simulates time passed since the state was saved and
+// opens the summary modal if anything happened
+func (m *Model) applyOfflineProgress() {
+	summary, ok := m.GameState.SimulateOffline(time.Now())
+	if !ok {
+		return
+	}
+	m.offlineSummary = summary
+	m.modal.SetActive(shared.ModalOfflineSummary)
+}
+
+// refreshViews re-renders components that cache game state
+func (m *Model) refreshViews() {
+	m.storage.Refresh(m.GameState)
+	m.activity.UpdateContent(m.GameState)
+	m.activity.GotoBottom()
+}
+
+// quit saves to the current slot, if autosave is enabled, and exits
+func (m *Model) quit() tea.Cmd {
+	if m.autosave {
+		if err := m.saveGame(m.saveSlot); err != nil {
+			m.AddLogEntry("System", "Autosave failed:", err.Error())
+			// Stay open so the player can see the error and retry
+			m.autosave = false
+			return nil
+		}
+	}
+	return tea.Quit
+}
@@ -0,0 +1,127 @@
+// Package processing provides the processing tab component
+package processing
+
+import (
+	"strconv"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/keys"
+	"github.com/jexxer/tbrpg/ui/shared"
+)
+
+// maxCount caps the typed conversion count
+const maxCount = 9999
+
+type View struct {
+	station int // Index into the catalog's stations
+	cursor  int // Conversion at the selected station
+	count   string
+	keyMap  keys.KeyMap
+}
+
+// New creates and initializes a new processing View
+func New(keyMap keys.KeyMap) View {
+	return View{keyMap: keyMap}
+}
+
+// Help lists the processing tab's keys for the help modal
+func Help(keyMap keys.KeyMap) keys.Section {
+	return keys.Section{Title: "Processing", Bindings: []key.Binding{
+		keyMap.Left, keyMap.Right, keyMap.Up, keyMap.Down, keyMap.Queue, keyMap.QueueMax, keyMap.Stop, keyMap.Back,
+	}}
+}
+
+// SelectedStation returns the station being viewed
+func (v *View) SelectedStation(gameState *game.State) (*game.Station, bool) {
+	stations := gameState.Catalog.Stations()
+	if v.station < 0 || v.station >= len(stations) {
+		return nil, false
+	}
+	return stations[v.station], true
+}
+
+// SelectedConversion returns the conversion under the cursor
+func (v *View) SelectedConversion(gameState *game.State) (*game.Conversion, bool) {
+	station, ok := v.SelectedStation(gameState)
+	if !ok {
+		return nil, false
+	}
+	convs := gameState.Catalog.Conversions(station.ID)
+	if v.cursor < 0 || v.cursor >= len(convs) {
+		return nil, false
+	}
+	return convs[v.cursor], true
+}
+
+// Count returns how many conversions enter will queue
+func (v *View) Count() int {
+	n, err := strconv.Atoi(v.count)
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+// Update handles processing-specific updates
+func (v *View) Update(msg tea.Msg, gameState *game.State) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+	stations := gameState.Catalog.Stations()
+	keyMap := v.keyMap
+
+	switch {
+	case key.Matches(keyMsg, keyMap.Left):
+		if v.station > 0 {
+			v.station--
+			v.cursor = 0
+		}
+	case key.Matches(keyMsg, keyMap.Right):
+		if v.station < len(stations)-1 {
+			v.station++
+			v.cursor = 0
+		}
+	case key.Matches(keyMsg, keyMap.Up):
+		if v.cursor > 0 {
+			v.cursor--
+		}
+	case key.Matches(keyMsg, keyMap.Down):
+		if station, ok := v.SelectedStation(gameState); ok && v.cursor < len(gameState.Catalog.Conversions(station.ID))-1 {
+			v.cursor++
+		}
+	case key.Matches(keyMsg, keyMap.Queue, keyMap.QueueMax):
+		conv, ok := v.SelectedConversion(gameState)
+		if !ok {
+			return nil
+		}
+		skill := string(gameState.ProcessingSkill(conv))
+		count := v.Count()
+		if key.Matches(keyMsg, keyMap.QueueMax) {
+			count = gameState.MaxProcesses(conv)
+		}
+		v.count = ""
+		if count < 1 {
+			gameState.Log(skill, "Cannot process:", "no materials for "+conv.Name)
+			return nil
+		}
+		action := game.NewAction(game.ActionProcess, conv.ID, count)
+		if err := gameState.QueueProcess(action); err != nil {
+			gameState.Log(skill, "Cannot process:", err.Error())
+			return nil
+		}
+		gameState.Log("System", "Queued "+gameState.ActionLabel(action), "x"+strconv.Itoa(count))
+	case key.Matches(keyMsg, keyMap.Stop):
+		if action, ok := gameState.CancelProcess(); ok {
+			gameState.Log("System", "Stopped "+gameState.ActionLabel(action), "")
+		}
+	case key.Matches(keyMsg, keyMap.Back):
+		v.count = ""
+	default:
+		v.count = shared.TypeCount(v.count, keyMsg.String(), maxCount)
+	}
+
+	return nil
+}
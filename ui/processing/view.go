@@ -0,0 +1,109 @@
+package processing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/keys"
+	"github.com/jexxer/tbrpg/ui/shared"
+	"github.com/jexxer/tbrpg/ui/styles"
+)
+
+// View renders the processing view
+func (v *View) View(width, height int, gameState *game.State) string {
+	titleStyle := styles.TitleStyle()
+	dimStyle := styles.DimStyle()
+	selectedStyle := styles.SelectedItemStyle()
+	readyStyle := styles.ReadyStyle()
+
+	var b strings.Builder
+
+	// Station tabs
+	var tabs []string
+	for i, station := range gameState.Catalog.Stations() {
+		if i == v.station {
+			tabs = append(tabs, selectedStyle.Render("["+station.Name+"]"))
+		} else {
+			tabs = append(tabs, dimStyle.Render(" "+station.Name+" "))
+		}
+	}
+	b.WriteString(strings.Join(tabs, " ") + "\n")
+
+	station, ok := v.SelectedStation(gameState)
+	if !ok {
+		return lipgloss.JoinVertical(lipgloss.Left, titleStyle.Render("Processing"), "", "No stations")
+	}
+	level := gameState.SkillLevel(station.Skill)
+	line := fmt.Sprintf("%s Lv %d  %d XP", station.Skill, level, gameState.SkillXP(station.Skill))
+	if len(station.Fuel) > 0 {
+		var fuels []string
+		for _, fuel := range station.Fuel {
+			fuels = append(fuels, fmt.Sprintf("%s %d/use (%d)", gameState.Catalog.ItemName(fuel.Item), fuel.Quantity, gameState.Storage.Quantity(fuel.Item)))
+		}
+		line += dimStyle.Render("  Fuel: " + strings.Join(fuels, ", "))
+	}
+	b.WriteString(line + "\n")
+	if !gameState.StationHere(station.ID) {
+		b.WriteString(dimStyle.Render(fmt.Sprintf("No %s in %s; queued work carries on without you",
+			strings.ToLower(station.Name), gameState.CurrentLocation().Name)) + "\n")
+	}
+
+	// Conversions
+	b.WriteString("\n" + dimStyle.Render(fmt.Sprintf("  %-14s %-4s %-6s %-5s %-5s %s", "Product", "Lvl", "Time", "Fail", "Can", "Inputs")) + "\n")
+	for i, conv := range gameState.Catalog.Conversions(station.ID) {
+		var inputs []string
+		for _, input := range conv.Inputs {
+			inputs = append(inputs, fmt.Sprintf("%d %s", input.Quantity, gameState.Catalog.ItemName(input.Item)))
+		}
+		status := fmt.Sprintf("%d", gameState.MaxProcesses(conv))
+		if level < conv.Level {
+			status = "locked"
+		}
+		line := fmt.Sprintf("%-14s %-4d %-6s %-5s %-5s %s", conv.Name, conv.Level,
+			fmt.Sprintf("%.1fs", gameState.ProcessDuration(conv).Seconds()),
+			fmt.Sprintf("%d%%", conv.FailChance(level)), status, strings.Join(inputs, " + "))
+
+		switch {
+		case i == v.cursor:
+			line = selectedStyle.Render("> " + line)
+		case gameState.CanProcess(conv) != nil:
+			line = dimStyle.Render("  " + line)
+		default:
+			line = readyStyle.Render("  " + line)
+		}
+		b.WriteString(line + "\n")
+	}
+	if conv, ok := v.SelectedConversion(gameState); ok {
+		if err := gameState.CanProcess(conv); err != nil {
+			b.WriteString(dimStyle.Render("  "+err.Error()) + "\n")
+		}
+	}
+
+	// Processing queue
+	b.WriteString("\n" + dimStyle.Render("Queue:") + "\n")
+	if len(gameState.Processing) == 0 {
+		b.WriteString(dimStyle.Render("  idle") + "\n")
+	}
+	for i, action := range gameState.Processing {
+		label := gameState.ActionLabel(action)
+		if action.Repeat > 0 {
+			label += fmt.Sprintf(" (%d left)", action.Repeat+1)
+		}
+		if i == 0 {
+			barWidth := max(10, width/4)
+			label = fmt.Sprintf("%-30s %s", label, shared.RenderProgressBar(barWidth, gameState.ProcessProgress()))
+		}
+		b.WriteString("  " + label + "\n")
+	}
+
+	b.WriteString("\n" + dimStyle.Render(fmt.Sprintf("Count: %d  |  %s = Station  |  0-9 = Count  |  %s = Queue  |  %s = Max  |  %s = Stop",
+		v.Count(), keys.Hint(v.keyMap.Left, v.keyMap.Right), keys.Hint(v.keyMap.Queue), keys.Hint(v.keyMap.QueueMax), keys.Hint(v.keyMap.Stop))))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Render("Processing"),
+		"",
+		b.String(),
+	)
+}
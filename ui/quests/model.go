@@ -0,0 +1,96 @@
+// Package quests provides the quests tab component
+package quests
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/keys"
+)
+
+// sections lists quest statuses in display order
+var sections = []game.QuestStatus{
+	game.QuestActive,
+	game.QuestAvailable,
+	game.QuestLocked,
+	game.QuestCompleted,
+}
+
+type View struct {
+	cursor int
+	keyMap keys.KeyMap
+}
+
+// New creates and initializes a new quests View
+func New(keyMap keys.KeyMap) View {
+	return View{keyMap: keyMap}
+}
+
+// Help lists the quests tab's keys for the help modal
+func Help(keyMap keys.KeyMap) keys.Section {
+	return keys.Section{Title: "Quests", Bindings: []key.Binding{
+		keyMap.Up, keyMap.Down, keyMap.AcceptQuest, keyMap.DeliverQuest, keyMap.AbandonQuest,
+	}}
+}
+
+// listed returns the quests in display order, grouped by status
+func listed(gameState *game.State) []*game.Quest {
+	var quests []*game.Quest
+	for _, status := range sections {
+		quests = append(quests, gameState.QuestsByStatus(status)...)
+	}
+	return quests
+}
+
+// SelectedQuest returns the quest under the cursor
+func (v *View) SelectedQuest(gameState *game.State) (*game.Quest, bool) {
+	quests := listed(gameState)
+	if len(quests) == 0 {
+		return nil, false
+	}
+	// Accepting or completing a quest moves it between sections, so keep
+	// the cursor in range
+	v.cursor = min(v.cursor, len(quests)-1)
+	return quests[v.cursor], true
+}
+
+// Update handles quest-specific updates
+func (v *View) Update(msg tea.Msg, gameState *game.State) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+
+	keyMap := v.keyMap
+	switch {
+	case key.Matches(keyMsg, keyMap.Up):
+		if v.cursor > 0 {
+			v.cursor--
+		}
+	case key.Matches(keyMsg, keyMap.Down):
+		if v.cursor < len(listed(gameState))-1 {
+			v.cursor++
+		}
+	case key.Matches(keyMsg, keyMap.AcceptQuest):
+		if quest, ok := v.SelectedQuest(gameState); ok {
+			v.report(gameState, gameState.AcceptQuest(quest.ID))
+		}
+	case key.Matches(keyMsg, keyMap.DeliverQuest):
+		if quest, ok := v.SelectedQuest(gameState); ok {
+			v.report(gameState, gameState.DeliverQuest(quest.ID))
+		}
+	case key.Matches(keyMsg, keyMap.AbandonQuest):
+		if quest, ok := v.SelectedQuest(gameState); ok {
+			v.report(gameState, gameState.AbandonQuest(quest.ID))
+		}
+	}
+
+	return nil
+}
+
+// report logs a failed quest action
+func (v *View) report(gameState *game.State, err error) {
+	if err != nil {
+		gameState.Log("Quests", "Cannot do that:", err.Error())
+	}
+}
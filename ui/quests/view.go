@@ -0,0 +1,147 @@
+package quests
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/keys"
+	"github.com/jexxer/tbrpg/ui/styles"
+)
+
+// sectionTitles names each status section
+var sectionTitles = map[game.QuestStatus]string{
+	game.QuestActive:    "Active",
+	game.QuestAvailable: "Available",
+	game.QuestLocked:    "Locked",
+	game.QuestCompleted: "Completed",
+}
+
+// View renders the quests view
+func (v *View) View(width, height int, gameState *game.State) string {
+	titleStyle := styles.TitleStyle()
+	dimStyle := styles.DimStyle()
+	selectedStyle := styles.SelectedItemStyle()
+	readyStyle := styles.ReadyStyle()
+
+	var b strings.Builder
+	selected, _ := v.SelectedQuest(gameState)
+
+	for _, status := range sections {
+		quests := gameState.QuestsByStatus(status)
+		if len(quests) == 0 {
+			continue
+		}
+		b.WriteString(dimStyle.Render(fmt.Sprintf("%s (%d)", sectionTitles[status], len(quests))) + "\n")
+		for _, quest := range quests {
+			line := quest.Name
+			if objective, count, ok := gameState.CurrentObjective(quest); ok {
+				line = fmt.Sprintf("%-20s %s %d/%d", quest.Name, gameState.DescribeObjective(objective), count, objective.Count)
+			}
+			switch {
+			case quest == selected:
+				line = selectedStyle.Render("> " + line)
+			case status == game.QuestAvailable:
+				line = readyStyle.Render("  " + line)
+			case status == game.QuestActive:
+				line = "  " + line
+			default:
+				line = dimStyle.Render("  " + line)
+			}
+			b.WriteString(line + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if selected != nil {
+		b.WriteString(v.renderDetails(selected, gameState, dimStyle, readyStyle))
+	} else {
+		b.WriteString(dimStyle.Render("No quests") + "\n")
+	}
+
+	b.WriteString("\n" + dimStyle.Render(fmt.Sprintf("%s = Accept  |  %s = Deliver  |  %s = Abandon",
+		keys.Hint(v.keyMap.AcceptQuest), keys.Hint(v.keyMap.DeliverQuest), keys.Hint(v.keyMap.AbandonQuest))))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Render("Quests"),
+		"",
+		b.String(),
+	)
+}
+
+// renderDetails shows a quest's description, objectives and reward
+func (v *View) renderDetails(quest *game.Quest, gameState *game.State, dimStyle, doneStyle lipgloss.Style) string {
+	var b strings.Builder
+	status := gameState.QuestStatus(quest)
+
+	b.WriteString(quest.Name + dimStyle.Render("  "+string(status)) + "\n")
+	if quest.Description != "" {
+		b.WriteString(dimStyle.Render("  "+quest.Description) + "\n")
+	}
+	if status == game.QuestLocked {
+		var needs []string
+		for _, id := range quest.Requires {
+			if required, ok := gameState.Catalog.Quest(id); ok && gameState.QuestStatus(required) != game.QuestCompleted {
+				needs = append(needs, required.Name)
+			}
+		}
+		if len(needs) > 0 {
+			b.WriteString(dimStyle.Render("  Requires: "+strings.Join(needs, ", ")) + "\n")
+		}
+		if quest.Level > gameState.Player.Level() {
+			b.WriteString(dimStyle.Render(fmt.Sprintf("  Requires combat level %d", quest.Level)) + "\n")
+		}
+	}
+
+	step, count := -1, 0
+	if progress, ok := gameState.Quests[quest.ID]; ok {
+		step, count = progress.Step, progress.Count
+		if progress.Completed {
+			step = len(quest.Objectives)
+		}
+	}
+	for i, objective := range quest.Objectives {
+		text := gameState.DescribeObjective(objective)
+		switch {
+		case i < step:
+			b.WriteString(doneStyle.Render("  [x] "+text) + "\n")
+		case i == step:
+			line := fmt.Sprintf("  [ ] %s (%d/%d)", text, count, objective.Count)
+			if objective.Kind == game.ObjectiveDeliver {
+				line += dimStyle.Render(fmt.Sprintf("  have %d", gameState.Storage.Quantity(objective.Target)))
+			}
+			b.WriteString(line + "\n")
+		default:
+			b.WriteString(dimStyle.Render("  [ ] "+text) + "\n")
+		}
+	}
+
+	b.WriteString(dimStyle.Render("  Reward: "+describeReward(quest.Reward, gameState)) + "\n")
+	return b.String()
+}
+
+// describeReward renders a reward like "+40 gold, +3 Bread, +30 XP"
+func describeReward(reward game.QuestReward, gameState *game.State) string {
+	var parts []string
+	if reward.Gold > 0 {
+		parts = append(parts, fmt.Sprintf("+%d gold", reward.Gold))
+	}
+	for _, item := range reward.Items {
+		parts = append(parts, fmt.Sprintf("+%d %s", item.Quantity, gameState.Catalog.ItemName(item.Item)))
+	}
+	if reward.XP > 0 {
+		parts = append(parts, fmt.Sprintf("+%d XP", reward.XP))
+	}
+	skills := make([]string, 0, len(reward.Skills))
+	for skill, xp := range reward.Skills {
+		skills = append(skills, fmt.Sprintf("+%d %s XP", xp, skill))
+	}
+	sort.Strings(skills)
+	parts = append(parts, skills...)
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -4,32 +4,111 @@ package shared
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/keys"
 	"github.com/jexxer/tbrpg/ui/styles"
 )
 
 type ActivityView struct {
-	viewport viewport.Model
+	viewport     viewport.Model
+	rendered     int // Log version in the viewport content
+	keyMap       keys.KeyMap
+	follow       bool          // Keep the newest entries in view
+	since        time.Duration // Only show entries this recent, 0 for all
+	expires      time.Time     // When the oldest shown entry leaves the since window
+	searchInput  textinput.Model
+	searchActive bool
+	matches      []int // Content lines matching the search
+	match        int   // Current match, an index into matches
 }
 
 // NewActivityView creates and initializes a new ActivityView component
-func NewActivityView() ActivityView {
+func NewActivityView(keyMap keys.KeyMap) ActivityView {
 	vp := viewport.New(78, 5)
 	vp.SetContent("Activity log initialized...")
+	// Only the keymap scrolls the log, so its keys don't clash with the
+	// viewport's defaults
+	vp.KeyMap = viewport.KeyMap{Up: keyMap.Up, Down: keyMap.Down, PageUp: keyMap.PageUp, PageDown: keyMap.PageDown}
+
+	searchInput := textinput.New()
+	searchInput.Prompt = "/"
+	searchInput.CharLimit = 50
 
 	return ActivityView{
-		viewport: vp,
+		viewport:    vp,
+		keyMap:      keyMap,
+		follow:      true,
+		searchInput: searchInput,
 	}
 }
 
-// Update handles activity view-specific updates
-func (a *ActivityView) Update(msg tea.Msg) tea.Cmd {
+// ActivityHelp lists the activity log's keys for the help modal
+func ActivityHelp(keyMap keys.KeyMap) keys.Section {
+	return keys.Section{Title: "Activity Log", Bindings: []key.Binding{
+		keyMap.Up, keyMap.Down, keyMap.PageUp, keyMap.PageDown,
+		keyMap.LogSearch, keyMap.NextMatch, keyMap.PrevMatch, keyMap.LogCategories, keyMap.Follow,
+	}}
+}
+
+// Update handles activity view-specific updates: scrolling, search and
+// follow mode
+func (a *ActivityView) Update(msg tea.Msg, gameState *game.State) tea.Cmd {
 	var cmd tea.Cmd
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if ok && a.searchActive {
+		switch {
+		case key.Matches(keyMsg, a.keyMap.Back):
+			a.searchActive = false
+			a.searchInput.Blur()
+			a.searchInput.Reset()
+			a.UpdateContent(gameState)
+		case key.Matches(keyMsg, a.keyMap.Confirm):
+			a.searchActive = false
+			a.searchInput.Blur()
+		default:
+			a.searchInput, cmd = a.searchInput.Update(msg)
+			a.UpdateContent(gameState)
+			// Jump to the newest match while typing
+			a.match = len(a.matches) - 1
+			a.showMatch()
+		}
+		return cmd
+	}
+
+	if ok {
+		switch {
+		case key.Matches(keyMsg, a.keyMap.LogSearch):
+			a.searchActive = true
+			a.searchInput.Focus()
+			return textinput.Blink
+		case key.Matches(keyMsg, a.keyMap.NextMatch):
+			a.stepMatch(1)
+			return nil
+		case key.Matches(keyMsg, a.keyMap.PrevMatch):
+			a.stepMatch(-1)
+			return nil
+		case key.Matches(keyMsg, a.keyMap.Follow):
+			a.follow = !a.follow
+			if a.follow {
+				a.viewport.GotoBottom()
+			}
+			return nil
+		}
+	}
+
+	offset := a.viewport.YOffset
 	a.viewport, cmd = a.viewport.Update(msg)
+	// Scrolling up pauses following; scrolling back down resumes it
+	if a.viewport.YOffset != offset {
+		a.follow = a.viewport.AtBottom()
+	}
 	return cmd
 }
 
@@ -38,24 +117,109 @@ func (a *ActivityView) View() string {
 	return a.viewport.View()
 }
 
-// UpdateContent updates the viewport content with the activity log
+// UpdateContent updates the viewport content with the log entries that
+// pass the category and time filters
 func (a *ActivityView) UpdateContent(gameState *game.State) {
 	content := a.formatActivityLog(gameState)
 	a.viewport.SetContent(content)
+	a.rendered = gameState.ActivityLog.Version()
+	if a.follow {
+		a.viewport.GotoBottom()
+	}
 }
 
-// GotoBottom scrolls to the bottom of the viewport
+// IsStale reports whether the log has changed since the last UpdateContent
+// or an entry has aged out of the since window
+func (a *ActivityView) IsStale(gameState *game.State) bool {
+	if a.since > 0 && !a.expires.IsZero() && !time.Now().Before(a.expires) {
+		return true
+	}
+	return a.rendered != gameState.ActivityLog.Version()
+}
+
+// GotoBottom scrolls to the bottom of the viewport and resumes following
 func (a *ActivityView) GotoBottom() {
+	a.follow = true
 	a.viewport.GotoBottom()
 }
 
-// formatActivityLog formats the activity log entries for display
+// IsSearchActive returns whether the search input has focus
+func (a *ActivityView) IsSearchActive() bool {
+	return a.searchActive
+}
+
+// Since returns how recent shown entries must be, 0 for all
+func (a *ActivityView) Since() time.Duration {
+	return a.since
+}
+
+// SetSince only shows entries more recent than d, or all entries if d is 0
+func (a *ActivityView) SetSince(d time.Duration, gameState *game.State) {
+	a.since = d
+	a.UpdateContent(gameState)
+}
+
+// query returns the filters for shown entries
+func (a *ActivityView) query(gameState *game.State) game.LogQuery {
+	q := game.LogQuery{Exclude: gameState.LogHidden}
+	if a.since > 0 {
+		q.Since = time.Now().Add(-a.since)
+	}
+	return q
+}
+
+// stepMatch moves to the next (1) or previous (-1) search match, wrapping
+// around, and scrolls it into view
+func (a *ActivityView) stepMatch(delta int) {
+	if len(a.matches) == 0 {
+		return
+	}
+	a.match = (a.match + delta + len(a.matches)) % len(a.matches)
+	a.showMatch()
+}
+
+// showMatch scrolls the current match into view, which stops following
+func (a *ActivityView) showMatch() {
+	if a.match < 0 || a.match >= len(a.matches) {
+		return
+	}
+	a.viewport.SetYOffset(a.matches[a.match] - a.viewport.Height/2)
+	a.follow = a.viewport.AtBottom()
+}
+
+// formatActivityLog formats the activity log entries for display and
+// finds the lines matching the search
 func (a *ActivityView) formatActivityLog(gameState *game.State) string {
 	var content strings.Builder
+	search := game.LogQuery{Text: a.searchInput.Value()}
+	highlightStyle := styles.HighlightStyle()
 
-	for _, entry := range gameState.ActivityLog.GetEntries() {
-		timestamp := entry.Timestamp.Format("15:04")
-		color := getCategoryColor(entry.Category)
+	// Keep the current match on the same entry as lines come and go
+	current := -1
+	if a.match >= 0 && a.match < len(a.matches) {
+		current = a.matches[a.match]
+	}
+	a.matches = a.matches[:0]
+
+	entries := gameState.ActivityLog.Query(a.query(gameState))
+	a.expires = time.Time{}
+	if a.since > 0 && len(entries) > 0 {
+		a.expires = entries[0].Timestamp.Add(a.since)
+	}
+
+	// Content starts with a blank line, so entry i is on line i+1
+	for i, entry := range entries {
+		line := i + 1
+		matched := search.Text != "" && search.Matches(entry)
+		if matched {
+			a.matches = append(a.matches, line)
+		}
+
+		timestamp := "[" + entry.Timestamp.Format("15:04") + "]"
+		if matched && line == current {
+			timestamp = highlightStyle.Render(timestamp)
+		}
+		color := styles.CategoryColor(entry.Category)
 
 		categoryStyle := lipgloss.NewStyle().
 			Foreground(color).
@@ -63,39 +227,121 @@ func (a *ActivityView) formatActivityLog(gameState *game.State) string {
 
 		paddedCategory := fmt.Sprintf("%-11s", entry.Category)
 
-		line := fmt.Sprintf("[%s] %s  %s",
-			timestamp,
-			categoryStyle.Render(paddedCategory),
-			entry.Action,
-		)
-
+		text := entry.Action
 		if entry.Details != "" {
-			line += " " + entry.Details
+			text += " " + entry.Details
+		}
+		if matched {
+			text = highlight(text, search.Text, highlightStyle)
 		}
 
-		content.WriteString("\n" + line)
+		content.WriteString(fmt.Sprintf("\n%s %s  %s", timestamp, categoryStyle.Render(paddedCategory), text))
 	}
 
+	a.match = len(a.matches) - 1
+	for i, line := range a.matches {
+		if line >= current {
+			a.match = i
+			break
+		}
+	}
 	return content.String()
 }
 
-// getCategoryColor returns the color for a category
-func getCategoryColor(category string) lipgloss.Color {
-	categoryColors := map[string]lipgloss.Color{
-		"Combat":      "196",
-		"Woodcutting": "34",
-		"Fishing":     "33",
-		"Market":      "226",
-		"Navigation":  "205",
-		"System":      "240",
-		"Command":     "240",
-		"Storage":     "33",
+// highlight renders each case-insensitive occurrence of query in s with
+// style. Text that is already styled is left alone.
+func highlight(s, query string, style lipgloss.Style) string {
+	lower, lowerQuery := strings.ToLower(s), strings.ToLower(query)
+	if query == "" || strings.Contains(s, "\x1b") || len(lower) != len(s) {
+		return s
 	}
+	var b strings.Builder
+	for {
+		i := strings.Index(lower, lowerQuery)
+		if i < 0 {
+			break
+		}
+		end := i + len(lowerQuery)
+		b.WriteString(s[:i] + style.Render(s[i:end]))
+		s, lower = s[end:], lower[end:]
+	}
+	b.WriteString(s)
+	return b.String()
+}
 
-	if color, ok := categoryColors[category]; ok {
-		return color
+// Status summarizes the filters, search and follow mode, like
+// "Activity · 2 hidden · /iron 1/3 · following". When focused it ends with
+// the log's keys.
+func (a *ActivityView) Status(gameState *game.State, focused bool) string {
+	parts := []string{"Activity"}
+	if n := len(gameState.LogHidden); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d hidden", n))
+	}
+	if a.since > 0 {
+		parts = append(parts, "last "+game.FormatDuration(a.since))
 	}
-	return "255"
+	if query := a.searchInput.Value(); a.searchActive {
+		parts = append(parts, a.searchInput.View())
+	} else if query != "" {
+		if len(a.matches) == 0 {
+			parts = append(parts, "/"+query+" no matches")
+		} else {
+			parts = append(parts, fmt.Sprintf("/%s %d/%d", query, a.match+1, len(a.matches)))
+		}
+	}
+	if a.follow {
+		parts = append(parts, "following")
+	} else {
+		parts = append(parts, fmt.Sprintf("paused (%s to follow)", keys.Hint(a.keyMap.Follow)))
+	}
+	status := strings.Join(parts, " · ")
+
+	if focused && !a.searchActive {
+		hints := []key.Binding{a.keyMap.LogSearch, a.keyMap.LogCategories, a.keyMap.Follow}
+		if len(a.matches) > 0 {
+			hints = append(hints, a.keyMap.NextMatch, a.keyMap.PrevMatch)
+		}
+		var help []string
+		for _, b := range hints {
+			if b.Enabled() {
+				help = append(help, keys.Hint(b)+" "+b.Help().Desc)
+			}
+		}
+		status += "  " + styles.DimStyle().Render(strings.Join(help, "  "))
+	}
+	return status
+}
+
+// RenderLogCategoriesModal renders the modal that shows and hides log
+// categories
+func RenderLogCategoriesModal(gameState *game.State, cursor int, keyMap keys.KeyMap) string {
+	selectedStyle := styles.SelectedItemStyle()
+	var lines []string
+	categories := gameState.LogCategories()
+	for i, category := range categories {
+		mark := "[x]"
+		if gameState.LogCategoryHidden(category) {
+			mark = "[ ]"
+		}
+		line := mark + " " + category
+		if i == cursor {
+			lines = append(lines, selectedStyle.Render("> "+line))
+		} else {
+			lines = append(lines, "  "+line)
+		}
+	}
+	if len(categories) == 0 {
+		lines = append(lines, styles.DimStyle().Render("The log is empty"))
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		styles.TitleStyle().Render("Log Categories"),
+		"",
+		strings.Join(lines, "\n"),
+		styles.DimStyle().Render(fmt.Sprintf("\n%s = Toggle  |  %s = Show all  |  %s = Close",
+			keys.Hint(keyMap.Toggle, keyMap.Confirm), keys.Hint(keyMap.ShowAll), keys.Hint(keyMap.Back))),
+	)
 }
 
 // UpdateSize updates the component sizes based on window dimensions
@@ -0,0 +1,110 @@
+package shared
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jexxer/tbrpg/content"
+	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/keys"
+)
+
+func TestHighlight(t *testing.T) {
+	// Upper case stands in for the highlight, since tests render no color
+	style := lipgloss.NewStyle().Transform(strings.ToUpper)
+	tests := []struct {
+		s, query string
+		want     string
+	}{
+		{"Smelted iron bar", "", "Smelted iron bar"},
+		{"Smelted iron bar", "iron", "Smelted IRON bar"},
+		{"Iron ore and iron bar", "IRON", "IRON ore and IRON bar"},
+		{"aaa", "aa", "AAa"},
+		{"+1 Oak Logs", "copper", "+1 Oak Logs"},
+		{"\x1b[1miron\x1b[0m", "iron", "\x1b[1miron\x1b[0m"},
+		{"İron", "iron", "İron"}, // Lowercasing changes the length
+	}
+	for _, tt := range tests {
+		t.Run(tt.s+"/"+tt.query, func(t *testing.T) {
+			if got := highlight(tt.s, tt.query, style); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatActivityLogMatches(t *testing.T) {
+	catalog, err := game.LoadCatalog(content.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gameState := game.NewState(catalog)
+	gameState.ActivityLog.Clear()
+	for _, entry := range [][2]string{
+		{"Mining", "+1 Iron Ore"},
+		{"Woodcutting", "+1 Oak Logs"},
+		{"Smithing", "Smelted Iron Bar"},
+		{"Market", "Sold 5 Iron Bar"},
+		{"Mining", "+1 Copper Ore"},
+	} {
+		gameState.ActivityLog.AddEntry(entry[0], entry[1], "")
+	}
+
+	tests := []struct {
+		name    string
+		search  string
+		hidden  []string
+		matches []int
+	}{
+		{name: "no search", matches: []int{}},
+		{name: "entry i on line i+1", search: "iron", matches: []int{1, 3, 4}},
+		{name: "case-insensitive", search: "ORE", matches: []int{1, 5}},
+		{name: "category text", search: "woodcutting", matches: []int{2}},
+		{name: "hidden entries take no lines", search: "iron", hidden: []string{"Mining"}, matches: []int{2, 3}},
+		{name: "no match", search: "gold", matches: []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewActivityView(keys.Default())
+			a.searchInput.SetValue(tt.search)
+			gameState.LogHidden = tt.hidden
+			rendered := a.formatActivityLog(gameState)
+			if !slices.Equal(a.matches, tt.matches) {
+				t.Errorf("matches %v, want %v", a.matches, tt.matches)
+			}
+			lines := strings.Split(rendered, "\n")
+			for _, line := range a.matches {
+				if !strings.Contains(strings.ToLower(lines[line]), strings.ToLower(tt.search)) {
+					t.Errorf("line %d %q does not contain %q", line, lines[line], tt.search)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatActivityLogKeepsCurrentMatch(t *testing.T) {
+	catalog, err := game.LoadCatalog(content.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gameState := game.NewState(catalog)
+	gameState.ActivityLog.Clear()
+	gameState.ActivityLog.AddEntry("Mining", "+1 Iron Ore", "")
+	gameState.ActivityLog.AddEntry("Mining", "+1 Iron Ore", "")
+
+	a := NewActivityView(keys.Default())
+	a.searchInput.SetValue("iron")
+	a.formatActivityLog(gameState)
+	a.stepMatch(1) // The second match, on line 2
+	gameState.ActivityLog.AddEntry("Mining", "+1 Iron Ore", "")
+	a.formatActivityLog(gameState)
+
+	if len(a.matches) != 3 {
+		t.Fatalf("%d matches, want 3", len(a.matches))
+	}
+	if got := a.matches[a.match]; got != 2 {
+		t.Errorf("current match on line %d after a new entry, want 2", got)
+	}
+}
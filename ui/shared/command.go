@@ -3,15 +3,17 @@ package shared
 import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jexxer/tbrpg/ui/keys"
 )
 
 type CommandView struct {
 	input textinput.Model
 	mode  bool
+	hint  string // Shown while command mode is off
 }
 
 // NewCommandView creates and initializes a new CommandView component
-func NewCommandView() CommandView {
+func NewCommandView(keyMap keys.KeyMap) CommandView {
 	input := textinput.New()
 	input.Placeholder = "Enter command..."
 	input.CharLimit = 100
@@ -20,6 +22,8 @@ func NewCommandView() CommandView {
 	return CommandView{
 		input: input,
 		mode:  false,
+		hint: " > Commands - " + keys.Hint(keyMap.Help) + " for help - Press '" +
+			keys.Hint(keyMap.Command) + "' to enter command mode",
 	}
 }
 
@@ -35,7 +39,7 @@ func (c *CommandView) View() string {
 	if c.mode {
 		return c.input.View()
 	}
-	return " > Commands - ? for help - Press ':' to enter command mode"
+	return c.hint
 }
 
 // Activate enables command mode and focuses the input
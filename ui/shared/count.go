@@ -0,0 +1,20 @@
+package shared
+
+import "strconv"
+
+// TypeCount applies a digit or backspace to a typed count, up to limit.
+// Counts are typed text rather than keymap keys, so other keys leave the
+// count as it is.
+func TypeCount(count, pressed string, limit int) string {
+	switch pressed {
+	case "0", "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		if n, _ := strconv.Atoi(count + pressed); n <= limit {
+			return strconv.Itoa(n)
+		}
+	case "backspace":
+		if count != "" {
+			return count[:len(count)-1]
+		}
+	}
+	return count
+}
@@ -0,0 +1,38 @@
+package shared
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jexxer/tbrpg/ui/keys"
+	"github.com/jexxer/tbrpg/ui/styles"
+)
+
+// RenderHelpModal renders a help modal listing the sections' keys two to a
+// row, followed by any extra text and how to close it
+func RenderHelpModal(keyMap keys.KeyMap, title string, sections []keys.Section, extra ...string) string {
+	titleStyle := styles.TitleStyle().
+		Align(lipgloss.Center)
+	sectionStyle := lipgloss.NewStyle().Bold(true)
+
+	theme := styles.CurrentTheme()
+	helpView := help.New()
+	helpView.Styles.FullKey = lipgloss.NewStyle().Foreground(theme.Focused)
+	helpView.Styles.FullDesc = lipgloss.NewStyle().Foreground(theme.Text)
+	helpView.Styles.FullSeparator = styles.DimStyle()
+	var rows []string
+	for i := 0; i < len(sections); i += 2 {
+		var row []string
+		for _, section := range sections[i:min(i+2, len(sections))] {
+			column := sectionStyle.Render(section.Title) + "\n" + helpView.FullHelpView([][]key.Binding{section.Bindings})
+			row = append(row, lipgloss.NewStyle().Width(28).Render(column))
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...), "")
+	}
+
+	parts := append([]string{titleStyle.Render(title), "", strings.Join(rows, "\n")}, extra...)
+	parts = append(parts, "", "Press "+keys.Hint(keyMap.Back)+" or "+keys.Hint(keyMap.Help)+" to close")
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
@@ -6,7 +6,6 @@ import (
 
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 	"github.com/jexxer/tbrpg/ui/styles"
 )
 
@@ -34,9 +33,7 @@ func (d CompactDelegate) Render(w io.Writer, m list.Model, index int, item list.
 
 	// Highlight selected item
 	if index == m.Index() {
-		str = lipgloss.NewStyle().
-			Foreground(lipgloss.Color(styles.FocusedColor)).
-			Bold(true).
+		str = styles.SelectedItemStyle().
 			Render("> " + str)
 	} else {
 		str = "  " + str
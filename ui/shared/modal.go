@@ -12,11 +12,16 @@ const (
 	ModalHelp
 	ModalSaveSearch
 	ModalLoadSearch
+	ModalRenameSearch
+	ModalOfflineSummary
+	ModalLogCategories
 )
 
 type ModalView struct {
-	active ModalType
-	input  textinput.Model
+	active  ModalType
+	input   textinput.Model
+	cursor  int    // Selected row in list modals
+	message string // Error or status shown inside the modal
 }
 
 // NewModalView creates and initializes a new ModalView component
@@ -53,6 +58,37 @@ func (m *ModalView) ResetInput() {
 	m.input.Reset()
 }
 
+// SetInputValue replaces the input value
+func (m *ModalView) SetInputValue(value string) {
+	m.input.SetValue(value)
+	m.input.CursorEnd()
+}
+
+// Cursor returns the selected row in list modals
+func (m *ModalView) Cursor() int {
+	return m.cursor
+}
+
+// MoveCursor moves the selection by delta, clamped to [0, count)
+func (m *ModalView) MoveCursor(delta, count int) {
+	m.SetCursor(m.cursor+delta, count)
+}
+
+// SetCursor selects a row, clamped to [0, count)
+func (m *ModalView) SetCursor(index, count int) {
+	m.cursor = max(0, min(index, count-1))
+}
+
+// Message returns the message shown inside the modal
+func (m *ModalView) Message() string {
+	return m.message
+}
+
+// SetMessage sets the message shown inside the modal
+func (m *ModalView) SetMessage(message string) {
+	m.message = message
+}
+
 // FocusInput focuses the input
 func (m *ModalView) FocusInput() tea.Cmd {
 	return m.input.Focus()
@@ -68,9 +104,10 @@ func (m *ModalView) GetActive() ModalType {
 	return m.active
 }
 
-// SetActive sets the active modal type
+// SetActive sets the active modal type and clears any message
 func (m *ModalView) SetActive(modalType ModalType) {
 	m.active = modalType
+	m.message = ""
 }
 
 // IsActive returns whether any modal is active
@@ -81,4 +118,5 @@ func (m *ModalView) IsActive() bool {
 // Close closes the current modal
 func (m *ModalView) Close() {
 	m.active = ModalNone
+	m.message = ""
 }
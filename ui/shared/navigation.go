@@ -1,25 +1,40 @@
 package shared
 
 import (
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jexxer/tbrpg/ui/keys"
 	"github.com/jexxer/tbrpg/ui/styles"
 )
 
+// TabNames lists the main tabs in display order
+var TabNames = []string{
+	"Navigation",
+	"Storage",
+	"Equipment",
+	"Gathering",
+	"Processing",
+	"Crafting",
+	"Quests",
+	"Market",
+}
+
 type NavigationView struct {
 	tabsList list.Model
+	keyMap   keys.KeyMap
+}
+
+// NavigationHelp lists the tab list's keys for the help modal
+func NavigationHelp(keyMap keys.KeyMap) keys.Section {
+	return keys.Section{Title: "Tabs", Bindings: []key.Binding{keyMap.Up, keyMap.Down, keyMap.SelectTab}}
 }
 
 // NewNavigationView creates and initializes a new NavigationView component
-func NewNavigationView() NavigationView {
-	tabsItems := []list.Item{
-		ListItem{TitleText: "Navigation"},
-		ListItem{TitleText: "Storage"},
-		ListItem{TitleText: "Equipment"},
-		ListItem{TitleText: "Gathering"},
-		ListItem{TitleText: "Processing"},
-		ListItem{TitleText: "Crafting"},
-		ListItem{TitleText: "Quests"},
+func NewNavigationView(keyMap keys.KeyMap) NavigationView {
+	tabsItems := make([]list.Item, len(TabNames))
+	for i, name := range TabNames {
+		tabsItems[i] = ListItem{TitleText: name}
 	}
 
 	tabsList := list.New(tabsItems, CompactDelegate{}, 15, 10)
@@ -30,13 +45,27 @@ func NewNavigationView() NavigationView {
 
 	return NavigationView{
 		tabsList: tabsList,
+		keyMap:   keyMap,
 	}
 }
 
-// Update handles navigation-specific updates
+// Update handles navigation-specific updates. Only the keymap's up and down
+// keys reach the list, translated to the arrow keys it understands.
 func (n *NavigationView) Update(msg tea.Msg) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+	switch {
+	case key.Matches(keyMsg, n.keyMap.Up):
+		keyMsg = tea.KeyMsg{Type: tea.KeyUp}
+	case key.Matches(keyMsg, n.keyMap.Down):
+		keyMsg = tea.KeyMsg{Type: tea.KeyDown}
+	default:
+		return nil
+	}
 	var cmd tea.Cmd
-	n.tabsList, cmd = n.tabsList.Update(msg)
+	n.tabsList, cmd = n.tabsList.Update(keyMsg)
 	return cmd
 }
 
@@ -50,6 +79,11 @@ func (n *NavigationView) GetSelectedIndex() int {
 	return n.tabsList.Index()
 }
 
+// Select moves the tab cursor to the given index
+func (n *NavigationView) Select(index int) {
+	n.tabsList.Select(index)
+}
+
 // UpdateSize updates the component sizes based on window dimensions
 func (n *NavigationView) UpdateSize(width, height int) {
 	ws := styles.GetWindowSizes(width, height)
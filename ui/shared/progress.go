@@ -0,0 +1,28 @@
+package shared
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jexxer/tbrpg/ui/styles"
+)
+
+// RenderProgressBar renders a fixed-width bar filled to percent (0..1)
+func RenderProgressBar(width int, percent float64) string {
+	if width < 1 {
+		return ""
+	}
+	percent = max(0, min(1, percent))
+	filled := int(percent * float64(width))
+
+	theme := styles.CurrentTheme()
+	bar := lipgloss.NewStyle().
+		Foreground(theme.Focused).
+		Render(strings.Repeat("█", filled))
+	bar += lipgloss.NewStyle().
+		Foreground(theme.Border).
+		Render(strings.Repeat("░", width-filled))
+
+	return fmt.Sprintf("%s %3d%%", bar, int(percent*100))
+}
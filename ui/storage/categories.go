@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/jexxer/tbrpg/game"
+)
+
+// rebuildCategories regenerates the visible category rows from the tree,
+// keeping the cursor on the named category or its nearest visible ancestor
+func (v *View) rebuildCategories(selected string) {
+	rows := []list.Item{listItem{
+		title: game.AllItemsCategory,
+		count: v.counts[game.AllItemsCategory],
+	}}
+
+	var walk func(cats []game.ItemCategory, parent string, depth int)
+	walk = func(cats []game.ItemCategory, parent string, depth int) {
+		for _, cat := range cats {
+			expanded := v.expanded[cat.Name]
+			rows = append(rows, listItem{
+				title:       cat.Name,
+				parent:      parent,
+				depth:       depth,
+				hasChildren: len(cat.Children) > 0,
+				expanded:    expanded,
+				count:       v.counts[cat.Name],
+			})
+			if expanded {
+				walk(cat.Children, cat.Name, depth+1)
+			}
+		}
+	}
+	walk(v.categories, "", 0)
+
+	v.categoryList.SetItems(rows)
+
+	for name := selected; name != ""; name = v.parentOf(name) {
+		if i := indexOfCategory(rows, name); i >= 0 {
+			v.categoryList.Select(i)
+			return
+		}
+	}
+	v.categoryList.Select(0)
+}
+
+// revealCategory expands the ancestors of a category and selects it
+func (v *View) revealCategory(name string) {
+	for parent := v.parentOf(name); parent != ""; parent = v.parentOf(parent) {
+		v.expanded[parent] = true
+	}
+	v.rebuildCategories(name)
+}
+
+// toggleSelectedCategory expands or collapses the category under the cursor
+func (v *View) toggleSelectedCategory() {
+	item, ok := v.categoryList.SelectedItem().(listItem)
+	if !ok || !item.hasChildren {
+		return
+	}
+	v.expanded[item.title] = !item.expanded
+	v.rebuildCategories(item.title)
+}
+
+// selectedCategory returns the category name under the cursor
+func (v *View) selectedCategory() string {
+	if item, ok := v.categoryList.SelectedItem().(listItem); ok {
+		return item.title
+	}
+	return game.AllItemsCategory
+}
+
+// updateCounts refreshes per-category item counts in the sidebar
+func (v *View) updateCounts(gameState *game.State) {
+	v.counts = gameState.Storage.CountByCategory()
+	v.rebuildCategories(v.selectedCategory())
+}
+
+// parentOf returns the parent category name, or "" for top-level and
+// unknown categories
+func (v *View) parentOf(name string) string {
+	var find func(cats []game.ItemCategory, parent string) (string, bool)
+	find = func(cats []game.ItemCategory, parent string) (string, bool) {
+		for _, cat := range cats {
+			if cat.Name == name {
+				return parent, true
+			}
+			if p, ok := find(cat.Children, cat.Name); ok {
+				return p, true
+			}
+		}
+		return "", false
+	}
+	parent, _ := find(v.categories, "")
+	return parent
+}
+
+func indexOfCategory(rows []list.Item, name string) int {
+	for i, row := range rows {
+		if item, ok := row.(listItem); ok && item.title == name {
+			return i
+		}
+	}
+	return -1
+}
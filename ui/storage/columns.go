@@ -0,0 +1,238 @@
+package storage
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/styles"
+)
+
+// column is a storage table column. Optional columns are shown when their
+// ID is in the state's StorageColumns.
+type column struct {
+	id       string
+	title    string
+	sort     game.SortKey // SortNone if the column cannot be sorted
+	width    int          // 0 takes the space the other columns leave
+	optional bool
+}
+
+var columns = []column{
+	{id: "name", title: "Name", sort: game.SortName},
+	{id: "qty", title: "Qty", sort: game.SortQuantity, width: 8},
+	{id: "value", title: "Value", sort: game.SortValue, width: 8},
+	{id: "total", title: "Total", sort: game.SortTotal, width: 8, optional: true},
+	{id: "category", title: "Category", sort: game.SortCategory, width: 12, optional: true},
+	{id: "tags", title: "Tags", width: 14, optional: true},
+	{id: "equipped", title: "Worn", width: 6, optional: true},
+}
+
+// minNameWidth keeps item names readable when many columns are shown
+const minNameWidth = 10
+
+// visibleColumns returns the columns shown, in table order
+func visibleColumns(gameState *game.State) []column {
+	var shown []column
+	for _, col := range columns {
+		if !col.optional || slices.Contains(gameState.StorageColumns, col.id) {
+			shown = append(shown, col)
+		}
+	}
+	return shown
+}
+
+// fitColumns drops optional columns from the right until the rest fit a
+// table of the given inner width
+func fitColumns(shown []column, tableWidth int) []column {
+	need := 0
+	for _, col := range shown {
+		if col.width == 0 {
+			need += minNameWidth + 2
+		} else {
+			need += col.width + 2
+		}
+	}
+	n := len(shown)
+	for n > 0 && shown[n-1].optional && need > tableWidth {
+		n--
+		need -= shown[n].width + 2
+	}
+	return shown[:n]
+}
+
+// columnWidths returns the width of each visible column for a table of
+// the given inner width. Cells are padded by one space on each side.
+func columnWidths(shown []column, tableWidth int) []int {
+	widths := make([]int, len(shown))
+	rest := tableWidth
+	for i, col := range shown {
+		widths[i] = col.width
+		rest -= col.width + 2
+	}
+	for i, col := range shown {
+		if col.width == 0 {
+			widths[i] = max(minNameWidth, rest)
+		}
+	}
+	return widths
+}
+
+// tableColumns builds the table header, marking the sorted column with an
+// arrow
+func tableColumns(shown []column, order game.ItemSort) []table.Column {
+	cols := make([]table.Column, len(shown))
+	for i, col := range shown {
+		title := col.title
+		if order.Key != game.SortNone && col.sort == order.Key {
+			if order.Descending {
+				title += " ▼"
+			} else {
+				title += " ▲"
+			}
+		}
+		cols[i] = table.Column{Title: title, Width: max(col.width, minNameWidth)}
+	}
+	return cols
+}
+
+// cell renders one column of an item's row
+func (v *View) cell(col column, item game.Item, gameState *game.State) string {
+	switch col.id {
+	case "name":
+		if v.marked[item.ID] && !item.Equipped {
+			return "* " + item.Name
+		}
+		return item.Name
+	case "qty":
+		return fmt.Sprintf("%*d", col.width, item.Quantity)
+	case "value":
+		return fmt.Sprintf("%*d", col.width, item.Value)
+	case "total":
+		return fmt.Sprintf("%*d", col.width, item.Value*item.Quantity)
+	case "category":
+		return item.Category
+	case "tags":
+		return strings.Join(item.Tags, ",")
+	case "equipped":
+		if item.Equipped {
+			return "yes"
+		}
+		if worn := wornCount(gameState, item.ID); worn > 0 {
+			return fmt.Sprintf("+%d", worn)
+		}
+	}
+	return ""
+}
+
+// wornCount returns how many of an item the player is wearing
+func wornCount(gameState *game.State, itemID string) int {
+	n := 0
+	for _, item := range gameState.Equipment.Items() {
+		if item.ID == itemID {
+			n++
+		}
+	}
+	return n
+}
+
+// cycleSort sorts by the next visible sortable column, going back to
+// storage order after the last one
+func (v *View) cycleSort(gameState *game.State) {
+	var keys []game.SortKey
+	for _, col := range visibleColumns(gameState) {
+		if col.sort != game.SortNone {
+			keys = append(keys, col.sort)
+		}
+	}
+	order := gameState.StorageSort(gameState.SelectedCategory)
+	next := game.SortNone
+	if i := slices.Index(keys, order.Key); i+1 < len(keys) {
+		next = keys[i+1]
+	}
+	v.SetSort(game.ItemSort{Key: next}, gameState)
+}
+
+// reverseSort flips the direction of the current sort
+func (v *View) reverseSort(gameState *game.State) {
+	order := gameState.StorageSort(gameState.SelectedCategory)
+	if order.Key == game.SortNone {
+		return
+	}
+	order.Descending = !order.Descending
+	v.SetSort(order, gameState)
+}
+
+// SetSort sorts the selected category's items and logs the change
+func (v *View) SetSort(order game.ItemSort, gameState *game.State) {
+	gameState.SetStorageSort(gameState.SelectedCategory, order)
+	v.UpdateTable(gameState)
+	gameState.Log("Storage", "Sort: "+describeSort(order), gameState.SelectedCategory)
+}
+
+// describeSort names a sort like "value, descending"
+func describeSort(order game.ItemSort) string {
+	switch {
+	case order.Key == game.SortNone:
+		return "storage order"
+	case order.Descending:
+		return string(order.Key) + ", descending"
+	}
+	return string(order.Key) + ", ascending"
+}
+
+// toggleColumn shows or hides an optional column
+func (v *View) toggleColumn(id string, gameState *game.State) {
+	for _, col := range columns {
+		if !col.optional || col.id != id {
+			continue
+		}
+		if i := slices.Index(gameState.StorageColumns, col.id); i >= 0 {
+			gameState.StorageColumns = slices.Delete(gameState.StorageColumns, i, i+1)
+		} else {
+			gameState.StorageColumns = append(gameState.StorageColumns, col.id)
+		}
+		v.UpdateTable(gameState)
+		return
+	}
+}
+
+// headerRow is the line of the table header within the storage view:
+// below the title, the search bar and the table's top border
+const headerRow = 4
+
+// Click sorts by the table column header at x, y within the storage view,
+// or reverses the sort if that column is already sorted. It reports
+// whether the click was on a sortable header.
+func (v *View) Click(x, y, width, height int, gameState *game.State) bool {
+	if y != headerRow {
+		return false
+	}
+	ws := styles.GetWindowSizes(width, height)
+	shown := fitColumns(visibleColumns(gameState), tableWidth(ws))
+	left := ws.Storage.Categories.Width + 2 // Category panel and table border
+	for i, w := range columnWidths(shown, tableWidth(ws)) {
+		if x >= left && x < left+w+2 {
+			if shown[i].sort == game.SortNone {
+				return false
+			}
+			order := gameState.StorageSort(gameState.SelectedCategory)
+			if order.Key == shown[i].sort {
+				order.Descending = !order.Descending
+			} else {
+				order = game.ItemSort{Key: shown[i].sort}
+			}
+			v.SetSort(order, gameState)
+			return true
+		}
+		left += w + 2
+	}
+	return false
+}
+
+// tableWidth returns the inner width of the table panel
+func tableWidth(ws *styles.WindowStyles) int {
+	return ws.MainPanel.Width - ws.BorderOffset - ws.Storage.Categories.Width - 3
+}
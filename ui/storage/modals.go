@@ -5,62 +5,52 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/keys"
+	"github.com/jexxer/tbrpg/ui/shared"
+	"github.com/jexxer/tbrpg/ui/styles"
 )
 
-// RenderHelpModal renders the storage help modal
-func RenderHelpModal() string {
-	titleStyle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("205")).
-		Align(lipgloss.Center)
-
-	help := `
-Storage Keybinds
-
-Navigation:
-  Tab         - Cycle focus
-  ↑/↓ or j/k  - Navigate lists/table
-  ←/→ or h/l  - Switch between categories/table
-  Enter       - Select item
-
-Search:
-  /           - Activate search
-  ESC         - Exit search
-
-Search Syntax:
-  text        - Match item name
-  tag:weapon  - Filter by tag
-  *ore        - Wildcard (ends with "ore")
-  qty:>50     - Quantity greater than 50
-
-Actions:
-  S           - Save current search
-  O           - Load saved search
-  ?           - Show this help
-
-Press ESC or ? to close
-`
-
-	return titleStyle.Render("Storage Help") + "\n" + help
+// helpSections titles the columns of the keymap's full help
+var helpSections = []string{"General", "Storage", "Search & Sort", "Columns"}
+
+// RenderHelpModal renders the storage help modal from the active keymap
+func RenderHelpModal(keyMap keys.KeyMap) string {
+	var sections []keys.Section
+	for i, group := range keyMap.FullHelp() {
+		sections = append(sections, keys.Section{Title: helpSections[i], Bindings: group})
+	}
+
+	syntax := `Search Syntax:
+  text        - Name or tag contains text
+  "iron ore"  - Quoted phrase
+  tag:weapon  - Filter by tag (also name:, cat:)
+  *ore  ir?n* - Wildcards (* any, ? one char)
+  qty:>50     - Compare qty, value, total
+                (=, !=, >, >=, <, <=)
+  equipped:y  - Equipped items only
+  -tag:ore    - Exclude matches (also NOT)
+  a OR b      - Either term (also |)
+  (a b)       - Group; terms AND by default`
+
+	return shared.RenderHelpModal(keyMap, "Storage Help", sections, syntax)
 }
 
 // RenderSaveSearchModal renders the save search modal
-func RenderSaveSearchModal(currentQuery, modalInputView string) string {
-	titleStyle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("205"))
+func RenderSaveSearchModal(currentQuery, category, modalInputView, message string, keyMap keys.KeyMap) string {
+	titleStyle := styles.TitleStyle()
 
 	title := titleStyle.Render("Save Search")
 
-	currentQueryText := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
-		Render("Query: " + currentQuery)
+	if currentQuery == "" {
+		currentQuery = "(empty)"
+	}
+	currentQueryText := styles.DimStyle().
+		Render("Query: " + currentQuery + "\nCategory: " + category)
 
 	prompt := "Name: " + modalInputView
 
-	instructions := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
-		Render("\nEnter = Save  |  ESC = Cancel")
+	instructions := styles.DimStyle().
+		Render(fmt.Sprintf("\n%s = Save  |  %s = Cancel", keys.Hint(keyMap.Confirm), keys.Hint(keyMap.Back)))
 
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
@@ -69,31 +59,73 @@ func RenderSaveSearchModal(currentQuery, modalInputView string) string {
 		currentQueryText,
 		"",
 		prompt,
+		renderModalMessage(message),
 		instructions,
 	)
 }
 
 // RenderLoadSearchModal renders the load search modal
-func RenderLoadSearchModal(savedSearches []game.SavedSearch) string {
-	titleStyle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("205"))
+func RenderLoadSearchModal(savedSearches []game.SavedSearch, cursor int, message string, keyMap keys.KeyMap) string {
+	titleStyle := styles.TitleStyle()
 
 	title := titleStyle.Render("Saved Searches")
 
 	var searches string
 	if len(savedSearches) == 0 {
-		searches = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("240")).
-			Render("\nNo saved searches yet\n\nPress 's' while searching to save a search")
+		searches = styles.DimStyle().
+			Render("\nNo saved searches yet\n\nPress " + keys.Hint(keyMap.SaveSearch) + " in storage to save the current search")
 	} else {
+		selectedStyle := styles.SelectedItemStyle()
+
 		for i, search := range savedSearches {
-			searches += fmt.Sprintf("\n%d. %s\n   %s\n", i+1, search.Name, search.Query)
+			line := fmt.Sprintf("%d. %s", i+1, search.Name)
+			if i == cursor {
+				line = selectedStyle.Render("> " + line)
+			} else {
+				line = "  " + line
+			}
+
+			category := search.Category
+			if category == "" {
+				category = game.AllItemsCategory
+			}
+			searches += fmt.Sprintf("\n%s\n     %s  [%s]\n", line, search.Query, category)
 		}
-		searches += lipgloss.NewStyle().
-			Foreground(lipgloss.Color("240")).
-			Render("\nPress number to load  |  ESC to close")
+		searches += renderModalMessage(message)
+		searches += styles.DimStyle().
+			Render(fmt.Sprintf("\n%s = Load  |  %s = Close\n%s = Rename  |  %s = Delete",
+				keys.Hint(keyMap.LoadNth, keyMap.Confirm), keys.Hint(keyMap.Back), keys.Hint(keyMap.RenameSearch), keys.Hint(keyMap.DeleteSearch)))
 	}
 
 	return lipgloss.JoinVertical(lipgloss.Left, title, searches)
 }
+
+// RenderRenameSearchModal renders the rename saved search modal
+func RenderRenameSearchModal(oldName, modalInputView, message string, keyMap keys.KeyMap) string {
+	titleStyle := styles.TitleStyle()
+
+	current := styles.DimStyle().
+		Render("Renaming: " + oldName)
+
+	instructions := styles.DimStyle().
+		Render(fmt.Sprintf("\n%s = Rename  |  %s = Back", keys.Hint(keyMap.Confirm), keys.Hint(keyMap.Back)))
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		titleStyle.Render("Rename Search"),
+		"",
+		current,
+		"",
+		"Name: "+modalInputView,
+		renderModalMessage(message),
+		instructions,
+	)
+}
+
+// renderModalMessage renders an error line for modals, or nothing
+func renderModalMessage(message string) string {
+	if message == "" {
+		return ""
+	}
+	return styles.ErrorStyle().Render("\n" + message)
+}
@@ -4,6 +4,7 @@ package storage
 import (
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/table"
@@ -11,6 +12,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/keys"
 	"github.com/jexxer/tbrpg/ui/styles"
 )
 
@@ -23,14 +25,29 @@ const (
 
 type View struct {
 	searchInput  textinput.Model
+	categories   []game.ItemCategory
+	expanded     map[string]bool // Category names expanded in the sidebar
+	counts       map[string]int  // Item stacks per category
 	categoryList list.Model
 	table        table.Model
+	shown        []column // Table columns, matching the table's
 	searchActive bool
+	searchErr    error // Parse error for the current query, if any
 	focus        Focus
+	items        []game.Item     // Items behind the table rows
+	marked       map[string]bool // Item IDs marked for bulk selling
+	capacity     string          // Slots and weight used, like "12/60 slots"
+	keyMap       keys.KeyMap
 }
 
+// listItem is a visible row of the category tree
 type listItem struct {
-	title string
+	title       string // Category name
+	parent      string
+	depth       int
+	hasChildren bool
+	expanded    bool
+	count       int
 }
 
 func (i listItem) Title() string       { return i.title }
@@ -47,13 +64,20 @@ func (d compactDelegate) Render(w io.Writer, m list.Model, index int, item list.
 		return
 	}
 
-	str := i.title
+	marker := "  "
+	if i.hasChildren {
+		marker = "▸ "
+		if i.expanded {
+			marker = "▾ "
+		}
+	}
+	label := fmt.Sprintf("%s (%d)", i.title, i.count)
+	str := strings.Repeat(" ", i.depth) + marker + label
+	str = lipgloss.NewStyle().MaxWidth(m.Width() - 2).Render(str)
 
 	// Highlight selected item
 	if index == m.Index() {
-		str = lipgloss.NewStyle().
-			Foreground(lipgloss.Color(styles.FocusedColor)).
-			Bold(true).
+		str = styles.SelectedItemStyle().
 			Render("> " + str)
 	} else {
 		str = "  " + str
@@ -63,33 +87,29 @@ func (d compactDelegate) Render(w io.Writer, m list.Model, index int, item list.
 }
 
 // New creates and initializes a new storage View
-func New() View {
+func New(categories []game.ItemCategory, keyMap keys.KeyMap) View {
 	// Setup storage search input
 	searchInput := textinput.New()
-	searchInput.Placeholder = "Search items... (? for help)"
+	searchInput.Placeholder = fmt.Sprintf("Search items... (%s for help)", keys.Hint(keyMap.Help))
 	searchInput.CharLimit = 100
 	searchInput.Width = 25
 
-	// Setup storage category list
-	categories := game.GetCategories()
-	categoryItems := make([]list.Item, len(categories))
-	for i, cat := range categories {
-		categoryItems[i] = listItem{title: cat.Name}
-	}
-
-	categoryList := list.New(categoryItems, compactDelegate{}, 20, 4)
+	// Setup storage category tree; rows are filled in by rebuildCategories
+	categoryList := list.New([]list.Item{}, compactDelegate{}, 20, 4)
 	categoryList.SetShowPagination(false)
 	categoryList.SetShowHelp(false)
 	categoryList.SetShowStatusBar(false)
 	categoryList.SetFilteringEnabled(false)
 	categoryList.SetShowTitle(false)
 
-	// Setup storage table
-	storageColumns := []table.Column{
-		{Title: "Name", Width: 20},
-		{Title: "Qty", Width: 15},
-		{Title: "Value (g)", Width: 8},
+	// Setup storage table; optional columns are added by UpdateTable
+	var shown []column
+	for _, col := range columns {
+		if !col.optional {
+			shown = append(shown, col)
+		}
 	}
+	storageColumns := tableColumns(shown, game.ItemSort{})
 
 	storageTable := table.New(
 		table.WithColumns(storageColumns),
@@ -98,28 +118,75 @@ func New() View {
 		table.WithHeight(20),
 	)
 
-	tableStyles := table.DefaultStyles()
-	tableStyles.Header = tableStyles.Header.
-		BorderStyle(lipgloss.NormalBorder()).
-		BorderForeground(lipgloss.Color(styles.BorderColor)).
-		BorderBottom(true).
-		Bold(false)
-	tableStyles.Selected = tableStyles.Selected.
-		Foreground(lipgloss.Color(styles.BlackColor)).
-		Background(lipgloss.Color(styles.FocusedColor)).
-		Bold(false)
-	storageTable.SetStyles(tableStyles)
+	storageTable.SetStyles(tableStyles())
 
-	return View{
+	v := View{
 		searchInput:  searchInput,
+		categories:   categories,
+		expanded:     make(map[string]bool),
+		counts:       make(map[string]int),
+		marked:       make(map[string]bool),
 		categoryList: categoryList,
 		table:        storageTable,
+		shown:        shown,
 		searchActive: false,
 		focus:        FocusCategory,
+		keyMap:       keyMap,
 	}
+	v.rebuildCategories(game.AllItemsCategory)
+	return v
+}
+
+// tableStyles returns the item table's styles in the current theme
+func tableStyles() table.Styles {
+	theme := styles.CurrentTheme()
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(theme.Border).
+		BorderBottom(true).
+		Bold(false)
+	s.Selected = s.Selected.
+		Foreground(theme.SelectedText).
+		Background(theme.Focused).
+		Bold(false)
+	return s
 }
 
 // IsSearchActive returns whether search mode is active
 func (v *View) IsSearchActive() bool {
 	return v.searchActive
 }
+
+// SearchError returns the parse error for the current query, if any
+func (v *View) SearchError() error {
+	return v.searchErr
+}
+
+// Query returns the current search query
+func (v *View) Query() string {
+	return v.searchInput.Value()
+}
+
+// SetQuery replaces the search query and refreshes the table
+func (v *View) SetQuery(query string, gameState *game.State) {
+	v.searchInput.SetValue(query)
+	v.UpdateTable(gameState)
+}
+
+// Refresh syncs the category selection with game state and rebuilds the table
+func (v *View) Refresh(gameState *game.State) {
+	v.revealCategory(gameState.SelectedCategory)
+	v.UpdateTable(gameState)
+}
+
+// ApplySearch switches to a saved search's category and query
+func (v *View) ApplySearch(saved game.SavedSearch, gameState *game.State) {
+	category := saved.Category
+	if category == "" {
+		category = game.AllItemsCategory
+	}
+	gameState.SetCategory(category)
+	v.searchInput.SetValue(saved.Query)
+	v.Refresh(gameState)
+}
@@ -3,6 +3,7 @@ package storage
 import (
 	"fmt"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -10,28 +11,51 @@ import (
 	"github.com/jexxer/tbrpg/ui/styles"
 )
 
-// UpdateTable updates the storage table with filtered items from game state
+// UpdateTable updates the storage table with filtered items from game state.
+// If the search query does not parse, the table keeps its previous rows and
+// the error is shown in the search bar.
 func (v *View) UpdateTable(gameState *game.State) {
 	searchTerm := v.searchInput.Value()
-	filtered := gameState.GetFilteredItems(searchTerm)
+	v.updateCounts(gameState)
+	capacity := gameState.Storage.Capacity
+	v.capacity = fmt.Sprintf("%d/%d slots  %d/%d wt",
+		gameState.Storage.UsedSlots(), capacity.Slots, gameState.Storage.Weight(), capacity.Weight)
+
+	filtered, err := gameState.GetFilteredItems(searchTerm)
+	v.searchErr = err
+	if err != nil {
+		return
+	}
+
+	// Drop marks on items that are no longer in storage
+	for id := range v.marked {
+		if gameState.Storage.Quantity(id) == 0 {
+			delete(v.marked, id)
+		}
+	}
+
+	order := gameState.StorageSort(gameState.SelectedCategory)
+	game.SortItems(filtered, order)
+	v.items = filtered
 
+	// Columns are set before rows so every row has a cell per column
+	shown := visibleColumns(gameState)
+	v.shown = shown
+	v.table.SetRows(nil)
+	v.table.SetColumns(tableColumns(shown, order))
 	rows := make([]table.Row, len(filtered))
 	for i, item := range filtered {
-		qtyStr := fmt.Sprintf("%d", item.Quantity)
-		valueStr := fmt.Sprintf("%d", item.Value)
-
-		rows[i] = table.Row{
-			item.Name,
-			fmt.Sprintf("%8s", qtyStr),
-			fmt.Sprintf("%8s", valueStr),
+		row := make(table.Row, len(shown))
+		for j, col := range shown {
+			row[j] = v.cell(col, item, gameState)
 		}
+		rows[i] = row
 	}
-
 	v.table.SetRows(rows)
 }
 
 // Update handles storage-specific updates
-func (v *View) Update(msg tea.Msg, gameState *game.State, onLog func(category, action, details string)) tea.Cmd {
+func (v *View) Update(msg tea.Msg, gameState *game.State) tea.Cmd {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
 
@@ -39,15 +63,19 @@ func (v *View) Update(msg tea.Msg, gameState *game.State, onLog func(category, a
 	case tea.KeyMsg:
 		// Handle search mode
 		if v.searchActive {
-			switch msg.String() {
-			case "esc":
+			switch {
+			case key.Matches(msg, v.keyMap.Back):
 				v.searchActive = false
 				v.searchInput.Blur()
 				return nil
-			case "enter":
+			case key.Matches(msg, v.keyMap.Confirm):
+				if v.searchErr != nil {
+					gameState.Log("Storage", "Invalid search:", v.searchErr.Error())
+					return nil
+				}
 				v.searchActive = false
 				v.searchInput.Blur()
-				onLog("Storage", "Search completed", "")
+				gameState.Log("Storage", "Search completed", "")
 				return nil
 			default:
 				v.searchInput, cmd = v.searchInput.Update(msg)
@@ -57,44 +85,73 @@ func (v *View) Update(msg tea.Msg, gameState *game.State, onLog func(category, a
 		}
 
 		// Handle normal keyboard navigation
-		switch msg.String() {
-		case "/":
+		keyMap := v.keyMap
+		switch {
+		case key.Matches(msg, keyMap.Search):
 			v.searchActive = true
 			v.searchInput.Focus()
 			return textinput.Blink
 
-		case "left", "h":
+		case key.Matches(msg, keyMap.Categories):
 			v.focus = FocusCategory
 			v.table.Blur()
 
-		case "right", "l":
+		case key.Matches(msg, keyMap.Table):
 			v.focus = FocusTable
 			v.table.Focus()
 
-		case "up", "k", "down", "j":
+		case key.Matches(msg, keyMap.Up, keyMap.Down):
 			if v.focus == FocusCategory {
-				v.categoryList, cmd = v.categoryList.Update(msg)
+				v.categoryList, cmd = v.categoryList.Update(v.listKey(msg))
 
 				// Update filter when category changes
-				selectedItem := v.categoryList.SelectedItem()
-				if item, ok := selectedItem.(listItem); ok {
-					if gameState.SelectedCategory != item.title {
-						gameState.SetCategory(item.title)
-						v.UpdateTable(gameState)
-						onLog("Storage", "Category: "+item.title, "")
-					}
+				if category := v.selectedCategory(); gameState.SelectedCategory != category {
+					gameState.SetCategory(category)
+					v.UpdateTable(gameState)
+					gameState.Log("Storage", "Category: "+category, "")
 				}
 				cmds = append(cmds, cmd)
 			} else {
-				v.table, cmd = v.table.Update(msg)
+				v.table, cmd = v.table.Update(v.listKey(msg))
 				cmds = append(cmds, cmd)
 			}
 
-		case "enter":
+		case key.Matches(msg, keyMap.Open, keyMap.Mark):
+			if v.focus == FocusCategory {
+				v.toggleSelectedCategory()
+			} else if key.Matches(msg, keyMap.Open) {
+				// The root model shows the item in the Details panel
+			} else if item, ok := v.SelectedItem(); ok && !item.Equipped {
+				v.marked[item.ID] = !v.marked[item.ID]
+				if !v.marked[item.ID] {
+					delete(v.marked, item.ID)
+				}
+				v.UpdateTable(gameState)
+			}
+
+		case key.Matches(msg, keyMap.Sell):
 			if v.focus == FocusTable {
-				onLog("Storage", "Selected item", "")
+				v.sellMarked(gameState)
 			}
 
+		case key.Matches(msg, keyMap.Sort):
+			v.cycleSort(gameState)
+
+		case key.Matches(msg, keyMap.ReverseSort):
+			v.reverseSort(gameState)
+
+		case key.Matches(msg, keyMap.ToggleTotal):
+			v.toggleColumn("total", gameState)
+
+		case key.Matches(msg, keyMap.ToggleCategory):
+			v.toggleColumn("category", gameState)
+
+		case key.Matches(msg, keyMap.ToggleTags):
+			v.toggleColumn("tags", gameState)
+
+		case key.Matches(msg, keyMap.ToggleWorn):
+			v.toggleColumn("equipped", gameState)
+
 		default:
 			if v.focus == FocusTable {
 				v.table, cmd = v.table.Update(msg)
@@ -106,9 +163,61 @@ func (v *View) Update(msg tea.Msg, gameState *game.State, onLog func(category, a
 	return tea.Batch(cmds...)
 }
 
+// listKey translates a rebound up or down key to the arrow key the list and
+// table understand
+func (v *View) listKey(msg tea.KeyMsg) tea.KeyMsg {
+	if key.Matches(msg, v.keyMap.Up) {
+		return tea.KeyMsg{Type: tea.KeyUp}
+	}
+	return tea.KeyMsg{Type: tea.KeyDown}
+}
+
+// TableFocused reports whether the item table has focus
+func (v *View) TableFocused() bool {
+	return v.focus == FocusTable
+}
+
+// SelectedItem returns the item on the table row under the cursor
+func (v *View) SelectedItem() (game.Item, bool) {
+	i := v.table.Cursor()
+	if i < 0 || i >= len(v.items) {
+		return game.Item{}, false
+	}
+	return v.items[i], true
+}
+
+// sellMarked sells every marked item, or the item under the cursor when
+// nothing is marked, to the local shop paying the most for all of them
+func (v *View) sellMarked(gameState *game.State) {
+	var items []game.ItemAmount
+	for _, item := range gameState.Storage.Totals() {
+		if v.marked[item.ID] {
+			items = append(items, game.ItemAmount{Item: item.ID, Quantity: item.Quantity})
+		}
+	}
+	if len(items) == 0 {
+		item, ok := v.SelectedItem()
+		if !ok || item.Equipped {
+			return
+		}
+		items = append(items, game.ItemAmount{Item: item.ID, Quantity: item.Quantity})
+	}
+
+	shop, err := gameState.BestBuyer(items)
+	if err == nil {
+		err = gameState.Sell(shop.ID, items)
+	}
+	if err != nil {
+		gameState.Log("Market", "Cannot sell:", err.Error())
+		return
+	}
+	clear(v.marked)
+	v.UpdateTable(gameState)
+}
+
 // UpdateSize updates the component sizes based on window dimensions
 func (v *View) UpdateSize(width, height int) {
 	ws := styles.GetWindowSizes(width, height)
-	v.categoryList.SetHeight(4)
+	v.categoryList.SetHeight(ws.MainPanel.Height - 7)
 	v.table.SetHeight(ws.MainPanel.Height - 7)
 }
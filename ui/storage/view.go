@@ -2,7 +2,7 @@ package storage
 
 import (
 	"github.com/charmbracelet/lipgloss"
-	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/keys"
 	"github.com/jexxer/tbrpg/ui/styles"
 )
 
@@ -18,25 +18,38 @@ func (v *View) View(width, height int) string {
 	// Update input width
 	v.searchInput.Width = availableWidth - 33
 
-	// Update table dimensions
-	qtyWidth := 10
-	valueWidth := 10
-	nameWidth := availableWidth - ws.Storage.Categories.Width - qtyWidth - valueWidth - 9
-
-	v.table.Columns()[0].Width = nameWidth
-	v.table.Columns()[1].Width = qtyWidth
-	v.table.Columns()[2].Width = valueWidth
+	// Update table dimensions; optional columns that do not fit are left
+	// out and the name column takes the space left over
+	fitted := fitColumns(v.shown, tableWidth(ws))
+	widths := columnWidths(fitted, tableWidth(ws))
+	for i := range v.table.Columns() {
+		if i < len(widths) {
+			v.table.Columns()[i].Width = widths[i]
+		} else {
+			v.table.Columns()[i].Width = 0 // Hidden by the table
+		}
+	}
 	v.table.SetHeight(availableHeight - searchBarHeight - ws.BorderOffset)
+	// Restyled each render so theme changes show at once
+	v.table.SetStyles(tableStyles())
+	v.categoryList.SetHeight(availableHeight - searchBarHeight - ws.BorderOffset)
 
 	// Render the view
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	titleStyle := styles.TitleStyle()
 
 	// Search bar
 	searchBarStyle := lipgloss.NewStyle().
 		Border(lipgloss.NormalBorder(), false, false, true, false).
 		Padding(0, 1)
 
-	searchBar := searchBarStyle.Render("Search: " + v.searchInput.View() + " (press / to search)")
+	searchHint := " (press " + keys.Hint(v.keyMap.Search) + " to search)"
+	if v.searchErr != nil {
+		searchHint = " " + styles.ErrorStyle().Render(v.searchErr.Error())
+	}
+
+	searchBar := searchBarStyle.Render("Search: " + v.searchInput.View() + searchHint)
+	capacityStyle := styles.DimStyle()
+	title := titleStyle.Render("Storage") + "  " + capacityStyle.Render(v.capacity)
 
 	// Layout: Category list on left, table on right
 	tableWidth := ws.MainPanel.Width - ws.Storage.Categories.Width - (ws.BorderOffset * 2) - 1
@@ -44,20 +57,13 @@ func (v *View) View(width, height int) string {
 	// Category panel with focus indicator
 	categoryStyle := lipgloss.NewStyle().
 		Width(ws.Storage.Categories.Width).
-		Height(len(game.GetCategories()))
+		Height(availableHeight - searchBarHeight - ws.BorderOffset)
 
 	// Add border to show focus
-	if v.focus == FocusCategory {
-		categoryStyle = categoryStyle.
-			Border(lipgloss.RoundedBorder()).
-			BorderRight(false).
-			BorderForeground(lipgloss.Color(styles.FocusedColor))
-	} else {
-		categoryStyle = categoryStyle.
-			Border(lipgloss.RoundedBorder()).
-			BorderRight(false).
-			BorderForeground(lipgloss.Color("#9E9E9E"))
-	}
+	categoryStyle = categoryStyle.
+		Border(lipgloss.RoundedBorder()).
+		BorderRight(false).
+		BorderForeground(styles.PaneBorderColor(v.focus == FocusCategory))
 
 	categoryPanel := categoryStyle.Render(v.categoryList.View())
 
@@ -67,22 +73,16 @@ func (v *View) View(width, height int) string {
 		// Height(ws.MainPanel.Height - ws.Storage.Search.Height - (ws.BorderOffset * 2))
 
 	// Add border to show focus
-	if v.focus == FocusTable {
-		tableStyle = tableStyle.
-			Border(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color(styles.FocusedColor))
-	} else {
-		tableStyle = tableStyle.
-			Border(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color("#9E9E9E"))
-	}
+	tableStyle = tableStyle.
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(styles.PaneBorderColor(v.focus == FocusTable))
 
 	tablePanel := tableStyle.Render(v.table.View())
 
 	content := lipgloss.JoinHorizontal(lipgloss.Top, categoryPanel, tablePanel)
 
 	return lipgloss.JoinVertical(lipgloss.Left,
-		titleStyle.Render("Storage"),
+		title,
 		searchBar,
 		content,
 	)
@@ -0,0 +1,321 @@
+package styles
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is a named palette. Colors are ANSI numbers like "205" or hex
+// like "#27F576".
+type Theme struct {
+	Name         string                    `json:"name"`
+	Focused      lipgloss.Color            `json:"focused"`       // Focused borders, selections and progress
+	Unfocused    lipgloss.Color            `json:"unfocused"`     // Other borders
+	Title        lipgloss.Color            `json:"title"`         // View and modal titles
+	Dim          lipgloss.Color            `json:"dim"`           // Hints and secondary text
+	Border       lipgloss.Color            `json:"border"`        // Rules and empty progress
+	Ready        lipgloss.Color            `json:"ready"`         // Things that can be done now
+	Error        lipgloss.Color            `json:"error"`         // Error messages
+	Command      lipgloss.Color            `json:"command"`       // Echoed commands
+	SelectedText lipgloss.Color            `json:"selected_text"` // Text on a Focused background
+	Backdrop     lipgloss.Color            `json:"backdrop"`      // Behind modals
+	Text         lipgloss.Color            `json:"text"`          // Log categories without a color
+	Categories   map[string]lipgloss.Color `json:"categories"`    // Activity log category colors
+}
+
+// DefaultTheme is the name of the theme used until another is picked
+const DefaultTheme = "default"
+
+// builtinThemes are always available; the first is the default
+var builtinThemes = []Theme{
+	{
+		Name:         DefaultTheme,
+		Focused:      "#27F576",
+		Unfocused:    "#9E9E9E",
+		Title:        "205",
+		Dim:          "240",
+		Border:       "240",
+		Ready:        "34",
+		Error:        "196",
+		Command:      "13",
+		SelectedText: "#000000",
+		Backdrop:     "236",
+		Text:         "255",
+		Categories: map[string]lipgloss.Color{
+			"Combat":       "196",
+			"Woodcutting":  "34",
+			"Mining":       "136",
+			"Fishing":      "33",
+			"Crafting":     "178",
+			"Smithing":     "166",
+			"Cooking":      "209",
+			"Market":       "226",
+			"Navigation":   "205",
+			"System":       "240",
+			"Command":      "240",
+			"Storage":      "33",
+			"Equipment":    "173",
+			"Character":    "141",
+			"Quests":       "220",
+			"Achievements": "214",
+		},
+	},
+	{
+		Name:         "high-contrast",
+		Focused:      "#FFFF00",
+		Unfocused:    "#FFFFFF",
+		Title:        "#00FFFF",
+		Dim:          "252",
+		Border:       "#FFFFFF",
+		Ready:        "#00FF00",
+		Error:        "#FF0000",
+		Command:      "#FF00FF",
+		SelectedText: "#000000",
+		Backdrop:     "238",
+		Text:         "#FFFFFF",
+		Categories: map[string]lipgloss.Color{
+			"Combat":       "#FF0000",
+			"Woodcutting":  "#00FF00",
+			"Mining":       "#FFAF00",
+			"Fishing":      "#00AFFF",
+			"Crafting":     "#FFFF00",
+			"Smithing":     "#FF8700",
+			"Cooking":      "#FF875F",
+			"Market":       "#FFFF00",
+			"Navigation":   "#00FFFF",
+			"System":       "#FFFFFF",
+			"Command":      "#FFFFFF",
+			"Storage":      "#00AFFF",
+			"Equipment":    "#FFAF5F",
+			"Character":    "#D787FF",
+			"Quests":       "#FFD700",
+			"Achievements": "#FFAF00",
+		},
+	},
+	{
+		Name:         "light-terminal",
+		Focused:      "#007A33",
+		Unfocused:    "#8A8A8A",
+		Title:        "125",
+		Dim:          "242",
+		Border:       "248",
+		Ready:        "28",
+		Error:        "160",
+		Command:      "91",
+		SelectedText: "#FFFFFF",
+		Backdrop:     "254",
+		Text:         "16",
+		Categories: map[string]lipgloss.Color{
+			"Combat":       "160",
+			"Woodcutting":  "28",
+			"Mining":       "94",
+			"Fishing":      "25",
+			"Crafting":     "136",
+			"Smithing":     "130",
+			"Cooking":      "166",
+			"Market":       "136",
+			"Navigation":   "125",
+			"System":       "242",
+			"Command":      "242",
+			"Storage":      "25",
+			"Equipment":    "130",
+			"Character":    "91",
+			"Quests":       "136",
+			"Achievements": "166",
+		},
+	},
+	{
+		// Okabe-Ito colors, told apart with any common color blindness
+		Name:         "colorblind-safe",
+		Focused:      "#56B4E9",
+		Unfocused:    "#9E9E9E",
+		Title:        "#CC79A7",
+		Dim:          "244",
+		Border:       "240",
+		Ready:        "#0072B2",
+		Error:        "#D55E00",
+		Command:      "#F0E442",
+		SelectedText: "#000000",
+		Backdrop:     "236",
+		Text:         "255",
+		Categories: map[string]lipgloss.Color{
+			"Combat":       "#D55E00",
+			"Woodcutting":  "#009E73",
+			"Mining":       "#E69F00",
+			"Fishing":      "#56B4E9",
+			"Crafting":     "#F0E442",
+			"Smithing":     "#E69F00",
+			"Cooking":      "#D55E00",
+			"Market":       "#F0E442",
+			"Navigation":   "#CC79A7",
+			"System":       "244",
+			"Command":      "244",
+			"Storage":      "#56B4E9",
+			"Equipment":    "#E69F00",
+			"Character":    "#CC79A7",
+			"Quests":       "#F0E442",
+			"Achievements": "#E69F00",
+		},
+	},
+}
+
+// themes holds every theme by name; current is the one views draw with
+var (
+	themes  = make(map[string]Theme)
+	current Theme
+)
+
+func init() {
+	for _, theme := range builtinThemes {
+		themes[theme.Name] = theme
+	}
+	current = themes[DefaultTheme]
+}
+
+// CurrentTheme returns the theme views draw with
+func CurrentTheme() Theme {
+	return current
+}
+
+// ThemeNames returns the names of the built-in themes, in order, followed
+// by loaded themes sorted by name
+func ThemeNames() []string {
+	var names, loaded []string
+	for _, theme := range builtinThemes {
+		names = append(names, theme.Name)
+	}
+	for name := range themes {
+		if !slices.Contains(names, name) {
+			loaded = append(loaded, name)
+		}
+	}
+	slices.Sort(loaded)
+	return append(names, loaded...)
+}
+
+// SetTheme switches to a theme by name
+func SetTheme(name string) error {
+	theme, ok := themes[strings.ToLower(name)]
+	if !ok {
+		return fmt.Errorf("unknown theme %q (try %s)", name, strings.Join(ThemeNames(), ", "))
+	}
+	current = theme
+	return nil
+}
+
+// CategoryColor returns the activity log color for a category
+func CategoryColor(category string) lipgloss.Color {
+	if color, ok := current.Categories[category]; ok {
+		return color
+	}
+	return current.Text
+}
+
+// ThemeDir returns the directory holding theme files. TBRPG_THEMES
+// overrides the default location under the user config directory.
+func ThemeDir() (string, error) {
+	if dir := os.Getenv("TBRPG_THEMES"); dir != "" {
+		return dir, nil
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locating themes: %w", err)
+	}
+	return filepath.Join(configDir, "tbrpg", "themes"), nil
+}
+
+// LoadThemeDir adds every theme file in dir. A missing directory holds no
+// themes. Files that fail to load are skipped and reported together.
+func LoadThemeDir(dir string) error {
+	if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, path := range paths {
+		if _, err := LoadThemeFile(path); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// LoadThemeFile adds the theme in a JSON file and returns it. Colors the
+// file leaves out are taken from the default theme, and the name defaults
+// to the file name. A theme may replace a built-in one of the same name.
+func LoadThemeFile(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, err
+	}
+
+	theme := themes[DefaultTheme]
+	theme.Name = ""
+	theme.Categories = maps.Clone(theme.Categories)
+	if err := json.Unmarshal(data, &theme); err != nil {
+		return Theme{}, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	if theme.Name == "" {
+		theme.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	theme.Name = strings.ToLower(theme.Name)
+	if err := theme.check(); err != nil {
+		return Theme{}, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+
+	themes[theme.Name] = theme
+	// Reloading the current theme takes effect right away
+	if current.Name == theme.Name {
+		current = theme
+	}
+	return theme, nil
+}
+
+// hexColor matches "#RGB" and "#RRGGBB"
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// check reports every color in the theme that terminals cannot show
+func (t Theme) check() error {
+	colors := map[string]lipgloss.Color{
+		"focused": t.Focused, "unfocused": t.Unfocused, "title": t.Title,
+		"dim": t.Dim, "border": t.Border, "ready": t.Ready, "error": t.Error,
+		"command": t.Command, "selected_text": t.SelectedText,
+		"backdrop": t.Backdrop, "text": t.Text,
+	}
+	for category, color := range t.Categories {
+		colors["categories."+category] = color
+	}
+
+	var problems []string
+	for _, field := range slices.Sorted(maps.Keys(colors)) {
+		if !validColor(string(colors[field])) {
+			problems = append(problems, fmt.Sprintf("%s: invalid color %q", field, colors[field]))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// validColor reports whether s is an ANSI color number or a hex color
+func validColor(s string) bool {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n >= 0 && n <= 255
+	}
+	return hexColor.MatchString(s)
+}
@@ -15,27 +15,6 @@ const (
 	ActivityViewportH = 5
 )
 
-// Colors
-const (
-	FocusedColor   = "#27F576"
-	UnfocusedColor = "#9E9E9E"
-	WhiteColor     = "#FFFFFF"
-	BlackColor     = "#000000"
-	BorderColor    = "240"
-)
-
-// CategoryColors for activity log
-var CategoryColors = map[string]lipgloss.Color{
-	"Combat":      "196",
-	"Woodcutting": "34",
-	"Fishing":     "33",
-	"Market":      "226",
-	"Navigation":  "205",
-	"System":      "240",
-	"Command":     "240",
-	"Storage":     "33",
-}
-
 type styles struct {
 	Height int
 	Width  int
@@ -113,43 +92,81 @@ func GetWindowSizes(terminalWidth int, terminalHeight int) *WindowStyles {
 	}
 }
 
-// GetCategoryColor returns the color for a category, or white if not found
-func GetCategoryColor(category string) lipgloss.Color {
-	if color, ok := CategoryColors[category]; ok {
-		return color
-	}
-	return "255" // Default white
-}
-
 // FocusedBorderStyle return common styles
 func FocusedBorderStyle() lipgloss.Style {
 	return lipgloss.NewStyle().
-		BorderForeground(lipgloss.Color(FocusedColor))
+		BorderForeground(current.Focused)
 }
 
 func UnfocusedBorderStyle() lipgloss.Style {
 	return lipgloss.NewStyle().
-		BorderForeground(lipgloss.Color(UnfocusedColor))
+		BorderForeground(current.Unfocused)
+}
+
+// PaneBorderColor returns the border color of a pane
+func PaneBorderColor(focused bool) lipgloss.Color {
+	if focused {
+		return current.Focused
+	}
+	return current.Unfocused
 }
 
 func SelectedItemStyle() lipgloss.Style {
 	return lipgloss.NewStyle().
-		Foreground(lipgloss.Color(FocusedColor)).
+		Foreground(current.Focused).
 		Bold(true)
 }
 
+// TitleStyle is for view and modal titles
+func TitleStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Bold(true).
+		Foreground(current.Title)
+}
+
+// DimStyle is for hints and secondary text
+func DimStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(current.Dim)
+}
+
+// ReadyStyle marks things that can be done now
+func ReadyStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(current.Ready)
+}
+
+// ErrorStyle is for error messages
+func ErrorStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(current.Error)
+}
+
+// HighlightStyle marks search matches
+func HighlightStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(current.SelectedText).
+		Background(current.Focused)
+}
+
 // GetTableSelectedStyle table styles
 func GetTableSelectedStyle() lipgloss.Style {
 	return lipgloss.NewStyle().
-		Foreground(lipgloss.Color(BlackColor)).
-		Background(lipgloss.Color(FocusedColor)).
+		Foreground(current.SelectedText).
+		Background(current.Focused).
 		Bold(false)
 }
 
 func GetTableHeaderStyle() lipgloss.Style {
 	return lipgloss.NewStyle().
 		BorderStyle(lipgloss.NormalBorder()).
-		BorderForeground(lipgloss.Color(BorderColor)).
+		BorderForeground(current.Border).
 		BorderBottom(true).
 		Bold(false)
 }
+
+// CommandStyle highlights echoed command text in the activity log
+func CommandStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(current.Command)
+}
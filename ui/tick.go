@@ -0,0 +1,29 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jexxer/tbrpg/game"
+)
+
+// tickMsg drives the game clock
+type tickMsg time.Time
+
+// tickCmd schedules the next game tick
+func tickCmd() tea.Cmd {
+	return tea.Tick(game.TickInterval, func(t time.Time) tea.Msg {
+		return tickMsg(t)
+	})
+}
+
+// handleTick advances the game by the time since the previous tick
+func (m *Model) handleTick(msg tickMsg) tea.Cmd {
+	now := time.Time(msg)
+	if !m.lastTick.IsZero() {
+		m.GameState.Tick(now.Sub(m.lastTick))
+	}
+	m.lastTick = now
+	m.syncViews()
+	return tickCmd()
+}
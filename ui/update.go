@@ -1,8 +1,12 @@
 package ui
 
 import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
+	"github.com/jexxer/tbrpg/ui/details"
 	"github.com/jexxer/tbrpg/ui/shared"
 	"github.com/jexxer/tbrpg/ui/styles"
 )
@@ -15,6 +19,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 
+	case tickMsg:
+		cmd = m.handleTick(msg)
+		return m, cmd
+
+	case details.ShortcutMsg:
+		m.runShortcut(msg.Action)
+		return m, nil
+
 	case tea.WindowSizeMsg:
 		m.Width = msg.Width
 		m.Height = msg.Height
@@ -23,7 +35,6 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.navigation.UpdateSize(msg.Width, msg.Height)
 		m.storage.UpdateSize(msg.Width, msg.Height)
 		m.activity.UpdateSize(msg.Width, msg.Height)
-		m.detailsList.SetHeight(ws.DetailsPanel.Height - ws.BorderOffset)
 
 		return m, nil
 
@@ -32,7 +43,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.Button == tea.MouseButtonWheelUp || msg.Button == tea.MouseButtonWheelDown {
 			// Check if mouse is over activity log area (7 rows above command line)
 			if msg.Y >= m.Height-10 && msg.Y < m.Height-3 {
-				cmd := m.activity.Update(msg)
+				cmd := m.activity.Update(msg, m.GameState)
 				return m, cmd
 			}
 		}
@@ -73,6 +84,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				} else {
 					m.FocusedView = FocusGameView
+					// Coordinates inside the main panel's border
+					if m.ActiveTab == TabStorage {
+						m.storage.Click(x-ws.LeftPanel.Width-1, y-ws.TopPanel.Height-1, m.Width, m.gameViewHeight(), m.GameState)
+						m.syncViews()
+					}
 				}
 			}
 		}
@@ -83,116 +99,124 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleModalInput(msg)
 		}
 
+		// Command mode handling
+		if m.command.IsActive() {
+			switch {
+			case key.Matches(msg, m.keyMap.Back):
+				m.command.Deactivate()
+				m.FocusedView = FocusGameView
+				return m, nil
+			case key.Matches(msg, m.keyMap.Confirm):
+				cmdText := m.command.GetValue()
+
+				m.command.Reset()
+				m.command.Deactivate()
+				m.FocusedView = FocusGameView
+
+				cmd = m.ExecuteCommand(cmdText)
+				return m, cmd
+			default:
+				cmd = m.command.Update(msg)
+				return m, cmd
+			}
+		}
+
+		// Storage search mode gets raw keys so typing isn't taken as shortcuts
+		if m.FocusedView == FocusGameView && m.ActiveTab == TabStorage && m.storage.IsSearchActive() {
+			cmd = m.storage.Update(msg, m.GameState)
+			m.syncViews()
+			return m, cmd
+		}
+
+		// Log search mode gets raw keys too
+		if m.FocusedView == FocusActivityLog && m.activity.IsSearchActive() {
+			cmd = m.activity.Update(msg, m.GameState)
+			return m, cmd
+		}
+
 		// handle navigating panes via vim keys
+		keyMap := m.keyMap
 		switch m.FocusedView {
 		case FocusLeftTabs:
-			switch msg.String() {
-			case "L":
+			switch {
+			case key.Matches(msg, keyMap.PaneRight):
 				m.FocusedView = FocusGameView
-			case "J":
+			case key.Matches(msg, keyMap.PaneDown):
 				m.FocusedView = FocusActivityLog
 			}
 		case FocusGameView:
-			switch msg.String() {
-			case "H":
+			switch {
+			case key.Matches(msg, keyMap.PaneLeft):
 				m.FocusedView = FocusLeftTabs
-			case "L":
+			case key.Matches(msg, keyMap.PaneRight):
 				m.FocusedView = FocusDetails
-			case "J":
+			case key.Matches(msg, keyMap.PaneDown):
 				m.FocusedView = FocusActivityLog
 			}
 		case FocusDetails:
-			switch msg.String() {
-			case "H":
+			switch {
+			case key.Matches(msg, keyMap.PaneLeft):
 				m.FocusedView = FocusGameView
-			case "J":
+			case key.Matches(msg, keyMap.PaneDown):
 				m.FocusedView = FocusActivityLog
 			}
 		case FocusActivityLog:
-			switch msg.String() {
-			case "K":
+			switch {
+			case key.Matches(msg, keyMap.PaneUp):
 				m.FocusedView = FocusGameView
-			case "J":
+			case key.Matches(msg, keyMap.PaneDown):
 				m.FocusedView = FocusCommandLine
 			}
 		case FocusCommandLine:
-			switch msg.String() {
-			case "K":
+			if key.Matches(msg, keyMap.PaneUp) {
 				m.FocusedView = FocusActivityLog
 			}
 		}
 
-		// Command mode handling
-		if m.command.IsActive() {
-			switch msg.String() {
-			case "esc":
-				m.command.Deactivate()
-				m.FocusedView = FocusGameView
-				return m, nil
-			case "enter":
-				// Execute command (implement later)
-				cmdText := m.command.GetValue()
-				styledText := lipgloss.NewStyle().Foreground(lipgloss.Color("13"))
-				m.AddLogEntry("Command", "Ran: ", styledText.Render(cmdText))
+		// Global keybindings. Tab and storage keys only apply where they
+		// are read, so they may share keys with each other.
+		inStorage := m.FocusedView == FocusGameView && m.ActiveTab == TabStorage
+		switch {
+		case key.Matches(msg, keyMap.Quit):
+			return m, m.quit()
 
-				m.command.Reset()
-				m.command.Deactivate()
-				m.FocusedView = FocusGameView
-				return m, nil
-			default:
-				cmd = m.command.Update(msg)
-				return m, cmd
-			}
-		}
-
-		// Storage search mode handling (delegated to storage component in storage update section below)
-
-		// Global keybindings
-		switch msg.String() {
-		case "q", "ctrl+c":
-			return m, tea.Quit
-
-		case ":":
+		case key.Matches(msg, keyMap.Command):
 			m.FocusedView = FocusCommandLine
 			cmd = m.command.Activate()
 			return m, cmd
 
-		case "?":
-			// Open help modal when in storage view
-			if m.FocusedView == FocusGameView && m.ActiveTab == 1 {
-				m.modal.SetActive(shared.ModalHelp)
-				return m, nil
-			}
+		case key.Matches(msg, keyMap.Help):
+			m.modal.SetActive(shared.ModalHelp)
+			return m, nil
 
-		case "tab":
+		case key.Matches(msg, keyMap.NextPane):
 			m.FocusedView = (m.FocusedView + 1) % 5
 
-		case "enter", " ":
-			if m.FocusedView == FocusLeftTabs {
-				m.ActiveTab = m.navigation.GetSelectedIndex()
-				m.FocusedView = FocusGameView
+		case m.FocusedView == FocusLeftTabs && key.Matches(msg, keyMap.SelectTab):
+			m.combat.Close()
+			m.ActiveTab = m.navigation.GetSelectedIndex()
+			m.FocusedView = FocusGameView
 
-				// TESTING: Add log entry when switching tabs
-				tabName := []string{"Navigation", "Storage", "Equipment", "Gathering", "Processing", "Crafting", "Quests"}[m.ActiveTab]
-				m.AddLogEntry("Navigation", "Switched to "+tabName, "")
-			}
-		case "/":
-			// Handled by storage component
+			// TESTING: Add log entry when switching tabs
+			tabName := shared.TabNames[m.ActiveTab]
+			m.AddLogEntry("Navigation", "Switched to "+tabName, "")
 
-		case "S":
+		case inStorage && key.Matches(msg, keyMap.SaveSearch):
 			// Save current search (handled by storage component but modal needs to be set)
-			if m.FocusedView == FocusGameView && m.ActiveTab == 1 && !m.storage.IsSearchActive() {
-				m.modal.SetActive(shared.ModalSaveSearch)
-				cmd = m.modal.FocusInput()
-				return m, cmd
-			}
+			m.modal.SetActive(shared.ModalSaveSearch)
+			cmd = m.modal.FocusInput()
+			return m, cmd
 
-		case "O":
+		case inStorage && key.Matches(msg, keyMap.LoadSearch):
 			// Load saved search
-			if m.FocusedView == FocusGameView && m.ActiveTab == 1 {
-				m.modal.SetActive(shared.ModalLoadSearch)
-				return m, nil
-			}
+			m.modal.SetActive(shared.ModalLoadSearch)
+			m.modal.SetCursor(0, len(m.GameState.SavedSearches))
+			return m, nil
+
+		case m.FocusedView == FocusActivityLog && key.Matches(msg, keyMap.LogCategories):
+			m.modal.SetActive(shared.ModalLogCategories)
+			m.modal.SetCursor(0, len(m.GameState.LogCategories()))
+			return m, nil
 		}
 
 		// Delegate to focused component
@@ -201,18 +225,53 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmd = m.navigation.Update(msg)
 			cmds = append(cmds, cmd)
 		case FocusDetails:
-			m.detailsList, cmd = m.detailsList.Update(msg)
+			cmd = m.details.Update(msg, m.GameState)
 			cmds = append(cmds, cmd)
 
 		case FocusGameView:
+			if m.combat.IsOpen() {
+				cmd = m.combat.Update(msg, m.GameState)
+				cmds = append(cmds, cmd)
+				break
+			}
+
 			// Storage view specific handling
-			if m.ActiveTab == 1 {
-				cmd = m.storage.Update(msg, m.GameState, m.AddLogEntry)
+			switch m.ActiveTab {
+			case TabNavigation:
+				cmd = m.world.Update(msg, m.GameState)
+				cmds = append(cmds, cmd)
+			case TabStorage:
+				cmd = m.storage.Update(msg, m.GameState)
+				cmds = append(cmds, cmd)
+				// Enter on a row shows the item in the Details panel
+				if key.Matches(msg, keyMap.Open) && m.storage.TableFocused() {
+					if item, ok := m.storage.SelectedItem(); ok {
+						m.details.Show(item)
+						m.FocusedView = FocusDetails
+					}
+				}
+			case TabEquipment:
+				cmd = m.equipment.Update(msg, m.GameState)
+				cmds = append(cmds, cmd)
+			case TabGathering:
+				cmd = m.gathering.Update(msg, m.GameState)
+				cmds = append(cmds, cmd)
+			case TabProcessing:
+				cmd = m.processing.Update(msg, m.GameState)
+				cmds = append(cmds, cmd)
+			case TabCrafting:
+				cmd = m.crafting.Update(msg, m.GameState)
+				cmds = append(cmds, cmd)
+			case TabQuests:
+				cmd = m.quests.Update(msg, m.GameState)
+				cmds = append(cmds, cmd)
+			case TabMarket:
+				cmd = m.market.Update(msg, m.GameState)
 				cmds = append(cmds, cmd)
 			}
 
 		case FocusActivityLog:
-			cmd = m.activity.Update(msg)
+			cmd = m.activity.Update(msg, m.GameState)
 			cmds = append(cmds, cmd)
 		}
 	}
@@ -223,47 +282,200 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, cmd)
 	}
 
+	m.syncViews()
+
 	return m, tea.Batch(cmds...)
 }
 
 // Handle modal input
 func (m Model) handleModalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "esc":
+	switch m.modal.GetActive() {
+	case shared.ModalHelp:
+		if key.Matches(msg, m.keyMap.Back, m.keyMap.Help) {
+			m.modal.Close()
+		}
+		return m, nil
+
+	case shared.ModalSaveSearch:
+		return m.handleSaveSearchInput(msg)
+
+	case shared.ModalLoadSearch:
+		return m.handleLoadSearchInput(msg)
+
+	case shared.ModalRenameSearch:
+		return m.handleRenameSearchInput(msg)
+
+	case shared.ModalLogCategories:
+		return m.handleLogCategoriesInput(msg)
+
+	case shared.ModalOfflineSummary:
+		if key.Matches(msg, m.keyMap.Back, m.keyMap.Confirm, m.keyMap.Toggle) {
+			m.modal.Close()
+		}
+		return m, nil
+	}
+
+	if key.Matches(msg, m.keyMap.Back) {
 		m.modal.Close()
 		m.modal.BlurInput()
+	}
+	return m, nil
+}
+
+func (m Model) handleSaveSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keyMap.Back):
+		m.modal.ResetInput()
+		m.modal.BlurInput()
+		m.modal.Close()
 		return m, nil
 
-	case "?":
-		if m.modal.GetActive() == shared.ModalHelp {
-			m.modal.Close()
+	case key.Matches(msg, m.keyMap.Confirm):
+		searchName := strings.TrimSpace(m.modal.GetInputValue())
+		query := m.storage.Query()
+		if err := m.GameState.SaveSearch(searchName, query, m.GameState.SelectedCategory); err != nil {
+			// Keep the modal open so the name can be corrected
+			m.modal.SetMessage(err.Error())
+			return m, nil
 		}
+
+		m.AddLogEntry("Storage", "Saved search: "+searchName, query)
+		m.modal.ResetInput()
+		m.modal.BlurInput()
+		m.modal.Close()
 		return m, nil
+	}
 
-	case "enter":
-		switch m.modal.GetActive() {
-		case shared.ModalSaveSearch:
-			// Save the search
-			searchName := m.modal.GetInputValue()
-			// Note: We'll need to get the search query from storage component
-			// For now, using a placeholder - this will be fixed when we refactor further
+	cmd := m.modal.Update(msg)
+	return m, cmd
+}
 
-			m.GameState.SaveSearch(searchName, "placeholder")
+func (m Model) handleLoadSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	count := len(m.GameState.SavedSearches)
 
-			m.AddLogEntry("Storage", "Saved search: "+searchName, "")
-			m.modal.ResetInput()
-			m.modal.BlurInput()
-			m.modal.Close()
+	keyMap := m.keyMap
+	switch {
+	case key.Matches(msg, keyMap.Back, keyMap.LoadSearch):
+		m.modal.Close()
+
+	case key.Matches(msg, keyMap.Up):
+		m.modal.MoveCursor(-1, count)
+
+	case key.Matches(msg, keyMap.Down):
+		m.modal.MoveCursor(1, count)
+
+	case key.Matches(msg, keyMap.Confirm):
+		if count > 0 {
+			m.loadSavedSearch(m.modal.Cursor())
+		}
+
+	case key.Matches(msg, keyMap.RenameSearch):
+		if count > 0 {
+			saved := m.GameState.SavedSearches[m.modal.Cursor()]
+			m.modal.SetActive(shared.ModalRenameSearch)
+			m.modal.SetInputValue(saved.Name)
+			return m, m.modal.FocusInput()
+		}
+
+	case key.Matches(msg, keyMap.DeleteSearch):
+		if count > 0 {
+			removed, err := m.GameState.DeleteSearchAt(m.modal.Cursor())
+			if err != nil {
+				m.modal.SetMessage(err.Error())
+				return m, nil
+			}
+			m.modal.SetCursor(m.modal.Cursor(), count-1)
+			m.AddLogEntry("Storage", "Deleted saved search: "+removed.Name, "")
+		}
+
+	case key.Matches(msg, keyMap.LoadNth):
+		m.loadSavedSearch(slices.Index(keyMap.LoadNth.Keys(), msg.String()))
+	}
+
+	return m, nil
+}
+
+func (m Model) handleRenameSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keyMap.Back):
+		m.modal.ResetInput()
+		m.modal.BlurInput()
+		m.modal.SetActive(shared.ModalLoadSearch)
+		return m, nil
+
+	case key.Matches(msg, m.keyMap.Confirm):
+		oldName := m.GameState.SavedSearches[m.modal.Cursor()].Name
+		newName := strings.TrimSpace(m.modal.GetInputValue())
+		if err := m.GameState.RenameSearchAt(m.modal.Cursor(), newName); err != nil {
+			m.modal.SetMessage(err.Error())
 			return m, nil
 		}
 
-	default:
-		// Delegate to modal input if applicable
-		if m.modal.GetActive() == shared.ModalSaveSearch {
-			cmd := m.modal.Update(msg)
-			return m, cmd
+		m.AddLogEntry("Storage", "Renamed saved search: "+oldName+" -> "+newName, "")
+		m.modal.ResetInput()
+		m.modal.BlurInput()
+		m.modal.SetActive(shared.ModalLoadSearch)
+		return m, nil
+	}
+
+	cmd := m.modal.Update(msg)
+	return m, cmd
+}
+
+func (m Model) handleLogCategoriesInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	categories := m.GameState.LogCategories()
+
+	keyMap := m.keyMap
+	switch {
+	case key.Matches(msg, keyMap.Back, keyMap.LogCategories):
+		m.modal.Close()
+
+	case key.Matches(msg, keyMap.Up):
+		m.modal.MoveCursor(-1, len(categories))
+
+	case key.Matches(msg, keyMap.Down):
+		m.modal.MoveCursor(1, len(categories))
+
+	case key.Matches(msg, keyMap.Toggle, keyMap.Confirm):
+		if len(categories) > 0 {
+			category := categories[m.modal.Cursor()]
+			m.GameState.SetLogCategoryHidden(category, !m.GameState.LogCategoryHidden(category))
+			m.activity.UpdateContent(m.GameState)
 		}
+
+	case key.Matches(msg, keyMap.ShowAll):
+		m.GameState.LogHidden = nil
+		m.activity.UpdateContent(m.GameState)
 	}
 
 	return m, nil
 }
+
+// loadSavedSearch applies the saved search at a 0-based position to the
+// storage view and closes the modal, or shows the error in the modal
+func (m *Model) loadSavedSearch(i int) {
+	saved, err := m.GameState.LoadSearchAt(i)
+	if err != nil {
+		m.modal.SetMessage(err.Error())
+		return
+	}
+
+	m.storage.ApplySearch(saved, m.GameState)
+	m.modal.Close()
+	m.AddLogEntry("Storage", "Loaded search: "+saved.Name, saved.Query)
+}
+
+// runShortcut runs a Details panel shortcut by its keymap action name
+func (m *Model) runShortcut(action string) {
+	switch action {
+	case "open_combat":
+		m.combat.Open()
+		m.FocusedView = FocusGameView
+	case "open_gathering":
+		m.switchTab(TabGathering)
+	case "open_crafting":
+		m.switchTab(TabCrafting)
+	case "open_storage":
+		m.switchTab(TabStorage)
+	}
+}
@@ -2,10 +2,24 @@
 package ui
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/charmbracelet/lipgloss"
+	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/combat"
+	"github.com/jexxer/tbrpg/ui/crafting"
+	"github.com/jexxer/tbrpg/ui/details"
+	"github.com/jexxer/tbrpg/ui/equipment"
+	"github.com/jexxer/tbrpg/ui/gathering"
+	"github.com/jexxer/tbrpg/ui/keys"
+	"github.com/jexxer/tbrpg/ui/market"
+	"github.com/jexxer/tbrpg/ui/processing"
+	"github.com/jexxer/tbrpg/ui/quests"
 	"github.com/jexxer/tbrpg/ui/shared"
 	"github.com/jexxer/tbrpg/ui/storage"
 	"github.com/jexxer/tbrpg/ui/styles"
+	"github.com/jexxer/tbrpg/ui/world"
 )
 
 func (m Model) View() string {
@@ -31,7 +45,7 @@ func (m Model) renderBaseView() string {
 		Border(lipgloss.RoundedBorder()).
 		Width(windowStyles.TopPanel.Width - windowStyles.BorderOffset).
 		Align(lipgloss.Center).
-		Render("The Town of Starting")
+		Render(m.renderLocation())
 
 	// Left tabs - use list component
 	leftTabsStyle := lipgloss.NewStyle().
@@ -39,11 +53,7 @@ func (m Model) renderBaseView() string {
 		Width(windowStyles.LeftPanel.Width - windowStyles.BorderOffset).
 		Height(windowStyles.LeftPanel.Height - windowStyles.BorderOffset)
 
-	if m.FocusedView == FocusLeftTabs {
-		leftTabsStyle = leftTabsStyle.BorderForeground(lipgloss.Color(styles.FocusedColor))
-	} else {
-		leftTabsStyle = leftTabsStyle.BorderForeground(lipgloss.Color(styles.UnfocusedColor))
-	}
+	leftTabsStyle = leftTabsStyle.BorderForeground(styles.PaneBorderColor(m.FocusedView == FocusLeftTabs))
 
 	leftTabs := leftTabsStyle.Render(m.navigation.View())
 
@@ -53,11 +63,7 @@ func (m Model) renderBaseView() string {
 		Width(windowStyles.MainPanel.Width - windowStyles.BorderOffset).
 		Height(windowStyles.MainPanel.Height - windowStyles.BorderOffset)
 
-	if m.FocusedView == FocusGameView {
-		gameViewStyle = gameViewStyle.BorderForeground(lipgloss.Color(styles.FocusedColor))
-	} else {
-		gameViewStyle = gameViewStyle.BorderForeground(lipgloss.Color(styles.UnfocusedColor))
-	}
+	gameViewStyle = gameViewStyle.BorderForeground(styles.PaneBorderColor(m.FocusedView == FocusGameView))
 
 	gameView := gameViewStyle.Render(m.renderGameView())
 
@@ -66,56 +72,66 @@ func (m Model) renderBaseView() string {
 		Border(lipgloss.RoundedBorder()).
 		Width(windowStyles.CharacterInfoPanel.Width - windowStyles.BorderOffset).
 		Height(windowStyles.CharacterInfoPanel.Height - windowStyles.BorderOffset).
-		BorderForeground(lipgloss.Color(styles.UnfocusedColor)).
+		BorderForeground(styles.PaneBorderColor(false)).
 		Render(m.renderCharacterInfo())
 
-	// Details - use list component
+	// Details - the picked item or shortcuts
 	detailsStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		Width(windowStyles.DetailsPanel.Width - windowStyles.BorderOffset).
 		Height(windowStyles.DetailsPanel.Height - windowStyles.BorderOffset)
 
-	if m.FocusedView == FocusDetails {
-		detailsStyle = detailsStyle.BorderForeground(lipgloss.Color(styles.FocusedColor))
-	} else {
-		detailsStyle = detailsStyle.BorderForeground(lipgloss.Color(styles.UnfocusedColor))
-	}
+	detailsStyle = detailsStyle.BorderForeground(styles.PaneBorderColor(m.FocusedView == FocusDetails))
 
-	details := detailsStyle.Render(m.detailsList.View())
+	details := detailsStyle.Render(m.details.View(
+		windowStyles.DetailsPanel.Width-windowStyles.BorderOffset,
+		windowStyles.DetailsPanel.Height-windowStyles.BorderOffset,
+		m.FocusedView == FocusDetails,
+		m.GameState,
+	))
 
 	rightSide := lipgloss.JoinVertical(lipgloss.Left, charInfo, details)
 	middleSection := lipgloss.JoinHorizontal(lipgloss.Top, leftTabs, gameView, rightSide)
 
-	// Activity log panel
+	// Activity log panel, with its filters and search in the top border
+	activityFocused := m.FocusedView == FocusActivityLog
 	activityStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
+		BorderTop(false).
 		Width(windowStyles.ActivityPanel.Width - windowStyles.BorderOffset).
 		Height(windowStyles.ActivityPanel.Height - windowStyles.BorderOffset)
 
-	if m.FocusedView == FocusActivityLog {
-		activityStyle = activityStyle.BorderForeground(lipgloss.Color(styles.FocusedColor))
-	} else {
-		activityStyle = activityStyle.BorderForeground(lipgloss.Color(styles.UnfocusedColor))
-	}
+	activityStyle = activityStyle.BorderForeground(styles.PaneBorderColor(activityFocused))
 
-	activityLog := activityStyle.Render(m.activity.View())
+	activityBody := activityStyle.Render(m.activity.View())
+	activityLog := lipgloss.JoinVertical(lipgloss.Left,
+		titledTop(m.activity.Status(m.GameState, activityFocused), lipgloss.Width(activityBody), styles.PaneBorderColor(activityFocused)),
+		activityBody,
+	)
 
 	// Command line
 	commandLineStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		Width(windowStyles.CommandPanel.Width - windowStyles.BorderOffset)
 
-	if m.FocusedView == FocusCommandLine {
-		commandLineStyle = commandLineStyle.BorderForeground(lipgloss.Color(styles.FocusedColor))
-	} else {
-		commandLineStyle = commandLineStyle.BorderForeground(lipgloss.Color(styles.UnfocusedColor))
-	}
+	commandLineStyle = commandLineStyle.BorderForeground(styles.PaneBorderColor(m.FocusedView == FocusCommandLine))
 
 	commandLine := commandLineStyle.Render(m.command.View())
 
 	return lipgloss.JoinVertical(lipgloss.Left, topBar, middleSection, activityLog, commandLine)
 }
 
+// titledTop renders the top edge of a rounded border with a title in it,
+// like "╭─ Activity ───╮"
+func titledTop(title string, width int, color lipgloss.Color) string {
+	border := lipgloss.RoundedBorder()
+	borderStyle := lipgloss.NewStyle().Foreground(color)
+	title = lipgloss.NewStyle().MaxWidth(max(0, width-6)).Render(title)
+	fill := max(0, width-5-lipgloss.Width(title))
+	return borderStyle.Render(border.TopLeft+border.Top+" ") + title +
+		borderStyle.Render(" "+strings.Repeat(border.Top, fill)+border.TopRight)
+}
+
 func (m Model) renderModalOverlay(base string) string {
 	var modalContent string
 
@@ -126,6 +142,12 @@ func (m Model) renderModalOverlay(base string) string {
 		modalContent = m.renderSaveSearchModal()
 	case shared.ModalLoadSearch:
 		modalContent = m.renderLoadSearchModal()
+	case shared.ModalRenameSearch:
+		modalContent = m.renderRenameSearchModal()
+	case shared.ModalOfflineSummary:
+		modalContent = m.renderOfflineSummaryModal()
+	case shared.ModalLogCategories:
+		modalContent = shared.RenderLogCategoriesModal(m.GameState, m.modal.Cursor(), m.keyMap)
 	default:
 		return base
 	}
@@ -133,7 +155,7 @@ func (m Model) renderModalOverlay(base string) string {
 	// Create modal box
 	modal := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("205")).
+		BorderForeground(styles.CurrentTheme().Title).
 		Padding(1, 2).
 		Width(60).
 		Render(modalContent)
@@ -146,27 +168,180 @@ func (m Model) renderModalOverlay(base string) string {
 		lipgloss.Center,
 		modal,
 		lipgloss.WithWhitespaceChars("░"),
-		lipgloss.WithWhitespaceForeground(lipgloss.Color("236")),
+		lipgloss.WithWhitespaceForeground(styles.CurrentTheme().Backdrop),
 	)
 }
 
+// renderHelpModal lists the general keys and those of the focused pane.
+// Storage has its own help with the search syntax.
 func (m Model) renderHelpModal() string {
-	return storage.RenderHelpModal()
+	keyMap := m.keyMap
+	var section keys.Section
+	switch {
+	case m.FocusedView == FocusLeftTabs:
+		section = shared.NavigationHelp(keyMap)
+	case m.FocusedView == FocusDetails:
+		section = details.Help(keyMap)
+	case m.FocusedView == FocusActivityLog:
+		section = shared.ActivityHelp(keyMap)
+	case m.combat.IsOpen():
+		section = combat.Help(keyMap)
+	default:
+		switch m.ActiveTab {
+		case TabNavigation:
+			section = world.Help(keyMap)
+		case TabStorage:
+			return storage.RenderHelpModal(keyMap)
+		case TabEquipment:
+			section = equipment.Help(keyMap)
+		case TabGathering:
+			section = gathering.Help(keyMap)
+		case TabProcessing:
+			section = processing.Help(keyMap)
+		case TabCrafting:
+			section = crafting.Help(keyMap)
+		case TabQuests:
+			section = quests.Help(keyMap)
+		case TabMarket:
+			section = market.Help(keyMap)
+		}
+	}
+	return shared.RenderHelpModal(keyMap, section.Title+" Help", []keys.Section{keyMap.General(), section})
 }
 
 func (m Model) renderSaveSearchModal() string {
-	// TODO: Get search query from storage component
 	return storage.RenderSaveSearchModal(
-		"placeholder",
+		m.storage.Query(),
+		m.GameState.SelectedCategory,
 		m.modal.GetInputView(),
+		m.modal.Message(),
+		m.keyMap,
 	)
 }
 
 func (m Model) renderLoadSearchModal() string {
-	return storage.RenderLoadSearchModal(m.GameState.SavedSearches)
+	return storage.RenderLoadSearchModal(
+		m.GameState.SavedSearches,
+		m.modal.Cursor(),
+		m.modal.Message(),
+		m.keyMap,
+	)
+}
+
+func (m Model) renderRenameSearchModal() string {
+	oldName := ""
+	if saved, err := m.GameState.LoadSearchAt(m.modal.Cursor()); err == nil {
+		oldName = saved.Name
+	}
+	return storage.RenderRenameSearchModal(oldName, m.modal.GetInputView(), m.modal.Message(), m.keyMap)
+}
+
+// actionBarHeight is the number of rows the action status uses in the game view
+const actionBarHeight = 2
+
+func (m Model) renderOfflineSummaryModal() string {
+	summary := m.offlineSummary
+
+	titleStyle := styles.TitleStyle()
+	dimStyle := styles.DimStyle()
+
+	away := "You were away for " + game.FormatDuration(summary.Away)
+	if summary.Simulated < summary.Away {
+		away += " (progress capped at " + game.FormatDuration(summary.Simulated) + ")"
+	}
+
+	var lines []string
+	if len(summary.Items) > 0 {
+		lines = append(lines, "Items:")
+		for _, item := range summary.Items {
+			lines = append(lines, fmt.Sprintf("  %+d %s", item.Quantity, item.Name))
+		}
+	}
+	if len(summary.XP) > 0 {
+		lines = append(lines, "", "Experience:")
+		for _, xp := range summary.XP {
+			lines = append(lines, fmt.Sprintf("  +%d %s XP (level %d)", xp.XP, xp.Skill, xp.Level))
+		}
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		titleStyle.Render("While you were away"),
+		"",
+		dimStyle.Render(away),
+		"",
+		strings.Join(lines, "\n"),
+		dimStyle.Render("\n"+keys.Hint(m.keyMap.Confirm)+" or "+keys.Hint(m.keyMap.Back)+" to continue"),
+	)
 }
 
 func (m Model) renderGameView() string {
+	content := m.renderTabView()
+	if m.combat.IsOpen() {
+		content = m.combat.View(m.Width, m.gameViewHeight(), m.GameState)
+	}
+	if bar := m.renderActionBar(); bar != "" {
+		content = lipgloss.JoinVertical(lipgloss.Left, content, bar)
+	}
+	return content
+}
+
+// renderLocation names where the player is, or where they are headed
+func (m Model) renderLocation() string {
+	location := m.GameState.CurrentLocation().Name
+	action, ok := m.GameState.CurrentAction()
+	if !ok || action.Kind != game.ActionTravel {
+		return location
+	}
+	dest := m.GameState.Catalog.LocationName(m.GameState.Destination())
+	return fmt.Sprintf("%s → %s", location, dest)
+}
+
+// renderActionBar renders the current timed action with its progress, or
+// nothing when idle
+func (m Model) renderActionBar() string {
+	action, ok := m.GameState.CurrentAction()
+	if !ok {
+		return ""
+	}
+
+	ws := styles.GetWindowSizes(m.Width, m.Height)
+	width := ws.MainPanel.Width - ws.BorderOffset
+
+	label := m.GameState.ActionLabel(action)
+	switch {
+	case action.Repeat > 0:
+		label += fmt.Sprintf(" (%d left)", action.Repeat+1)
+	case action.Repeat == game.RepeatForever:
+		label += " (repeating)"
+	}
+	if queued := len(m.GameState.Actions) - 1; queued > 0 {
+		label += fmt.Sprintf(" +%d queued", queued)
+	}
+	if m.GameState.Clock.Paused {
+		label += " [PAUSED]"
+	}
+
+	barWidth := max(10, width-lipgloss.Width(label)-8)
+	bar := shared.RenderProgressBar(barWidth, m.GameState.ActionProgress())
+
+	separator := lipgloss.NewStyle().
+		Foreground(styles.CurrentTheme().Border).
+		Render(strings.Repeat("─", width))
+
+	return separator + "\n" + label + "  " + bar
+}
+
+// gameViewHeight is the terminal height tab views should lay out for,
+// leaving room for the action bar when one is shown
+func (m Model) gameViewHeight() int {
+	if _, ok := m.GameState.CurrentAction(); ok {
+		return m.Height - actionBarHeight
+	}
+	return m.Height
+}
+
+func (m Model) renderTabView() string {
 	switch m.ActiveTab {
 	case 0: // Navigation
 		return m.renderNavigationView()
@@ -182,40 +357,82 @@ func (m Model) renderGameView() string {
 		return m.renderCraftingView()
 	case 6: // Quests
 		return m.renderQuestsView()
+	case 7: // Market
+		return m.renderMarketView()
 	default:
 		return "Unknown view"
 	}
 }
 
 func (m Model) renderStorageView() string {
-	return m.storage.View(m.Width, m.Height)
+	return m.storage.View(m.Width, m.gameViewHeight())
 }
 
 func (m Model) renderCharacterInfo() string {
-	return "Character Info\n\nName: Adventurer\nHealth: 50/50\nMana: 30/30\nLevel: 15\nGold: 1234g"
+	player := m.GameState.Player
+	stats := m.GameState.PlayerStats()
+	// Active buffs take the place of the blank line under the title
+	spacer := ""
+	if buffs := m.GameState.Buffs; len(buffs) > 0 {
+		spacer = renderBuffs(buffs)
+	}
+	return fmt.Sprintf("Character Info\n%s\nName: %s\nLevel: %d\nHP: %d/%d  MP: %d/%d\nATK: %d  DEF: %d\nGold: %dg",
+		spacer,
+		player.Name, stats.Level,
+		player.HP, stats.MaxHP, player.MP, stats.MaxMP,
+		stats.Attack, stats.Defense,
+		player.Gold)
+}
+
+// renderBuffs shows the buff running out soonest, like "ATK +5 0:45", and
+// how many others are active
+func renderBuffs(buffs []game.Buff) string {
+	next := buffs[0]
+	for _, buff := range buffs[1:] {
+		if buff.Remaining < next.Remaining {
+			next = buff
+		}
+	}
+
+	label := strings.ToUpper(next.Stat)
+	amount := fmt.Sprintf("%+d", next.Amount)
+	if next.Stat != game.StatAttack && next.Stat != game.StatDefense {
+		label = strings.ToUpper(next.Stat[:1]) + next.Stat[1:min(4, len(next.Stat))]
+		amount += "%"
+	}
+	secs := int(next.Remaining.Seconds())
+	line := fmt.Sprintf("%s %s %d:%02d", label, amount, secs/60, secs%60)
+	if len(buffs) > 1 {
+		line += fmt.Sprintf(" +%d", len(buffs)-1)
+	}
+	return styles.ReadyStyle().Render(line)
 }
 
 // Keep your other render functions for now
 func (m Model) renderNavigationView() string {
-	return "Navigation View\n\nComing soon..."
+	return m.world.View(m.Width, m.gameViewHeight(), m.GameState)
 }
 
 func (m Model) renderEquipmentView() string {
-	return "Equipment View\n\nComing soon..."
+	return m.equipment.View(m.Width, m.gameViewHeight(), m.GameState)
 }
 
 func (m Model) renderGatheringView() string {
-	return "Gathering View\n\nComing soon..."
+	return m.gathering.View(m.Width, m.gameViewHeight(), m.GameState)
 }
 
 func (m Model) renderProcessingView() string {
-	return "Processing View\n\nComing soon..."
+	return m.processing.View(m.Width, m.gameViewHeight(), m.GameState)
 }
 
 func (m Model) renderCraftingView() string {
-	return "Crafting View\n\nComing soon..."
+	return m.crafting.View(m.Width, m.gameViewHeight(), m.GameState)
+}
+
+func (m Model) renderMarketView() string {
+	return m.market.View(m.Width, m.gameViewHeight(), m.GameState)
 }
 
 func (m Model) renderQuestsView() string {
-	return "Quests View\n\nComing soon..."
+	return m.quests.View(m.Width, m.gameViewHeight(), m.GameState)
 }
@@ -0,0 +1,101 @@
+// Package world provides the navigation tab component: the world map and
+// travel between locations
+package world
+
+import (
+	"sort"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/keys"
+)
+
+type View struct {
+	cursor int
+	keyMap keys.KeyMap
+}
+
+// destination is a location the player can travel to
+type destination struct {
+	location *game.Location
+	route    []game.Route
+	time     time.Duration
+}
+
+// New creates and initializes a new world View
+func New(keyMap keys.KeyMap) View {
+	return View{keyMap: keyMap}
+}
+
+// Help lists the world map's keys for the help modal
+func Help(keyMap keys.KeyMap) keys.Section {
+	return keys.Section{Title: "World Map", Bindings: []key.Binding{
+		keyMap.Up, keyMap.Down, keyMap.Travel, keyMap.Stop,
+	}}
+}
+
+// destinations lists every other reachable location, nearest first
+func destinations(gameState *game.State) []destination {
+	var dests []destination
+	for _, location := range gameState.Catalog.Locations() {
+		route, err := gameState.PlanRoute(location.ID)
+		if err != nil {
+			continue
+		}
+		var total time.Duration
+		for _, leg := range route {
+			total += leg.Duration()
+		}
+		dests = append(dests, destination{location: location, route: route, time: total})
+	}
+	sort.SliceStable(dests, func(i, j int) bool { return dests[i].time < dests[j].time })
+	return dests
+}
+
+// selected returns the destination under the cursor
+func (v *View) selected(gameState *game.State) (destination, bool) {
+	dests := destinations(gameState)
+	if len(dests) == 0 {
+		return destination{}, false
+	}
+	// Arriving somewhere reorders the list, so keep the cursor in range
+	v.cursor = min(v.cursor, len(dests)-1)
+	return dests[v.cursor], true
+}
+
+// Update handles navigation-specific updates
+func (v *View) Update(msg tea.Msg, gameState *game.State) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+
+	keyMap := v.keyMap
+	switch {
+	case key.Matches(keyMsg, keyMap.Up):
+		if v.cursor > 0 {
+			v.cursor--
+		}
+	case key.Matches(keyMsg, keyMap.Down):
+		if v.cursor < len(destinations(gameState))-1 {
+			v.cursor++
+		}
+	case key.Matches(keyMsg, keyMap.Travel):
+		dest, ok := v.selected(gameState)
+		if !ok {
+			return nil
+		}
+		if err := gameState.TravelTo(dest.location.ID); err != nil {
+			gameState.Log("Navigation", "Cannot travel:", err.Error())
+		}
+	case key.Matches(keyMsg, keyMap.Stop):
+		if action, ok := gameState.CurrentAction(); ok && action.Kind == game.ActionTravel {
+			gameState.ClearActions()
+			gameState.Log("Navigation", "Stopped traveling", "staying in "+gameState.CurrentLocation().Name)
+		}
+	}
+
+	return nil
+}
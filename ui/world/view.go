@@ -0,0 +1,105 @@
+package world
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jexxer/tbrpg/game"
+	"github.com/jexxer/tbrpg/ui/keys"
+	"github.com/jexxer/tbrpg/ui/styles"
+)
+
+// View renders the navigation view
+func (v *View) View(width, height int, gameState *game.State) string {
+	titleStyle := styles.TitleStyle()
+	dimStyle := styles.DimStyle()
+	selectedStyle := styles.SelectedItemStyle()
+
+	var b strings.Builder
+
+	// Current location
+	here := gameState.CurrentLocation()
+	b.WriteString(here.Name + dimStyle.Render("  "+string(here.Kind)) + "\n")
+	if here.Description != "" {
+		b.WriteString(dimStyle.Render("  "+here.Description) + "\n")
+	}
+	b.WriteString(describeLocation(here, gameState, dimStyle))
+
+	if action, ok := gameState.CurrentAction(); ok && action.Kind == game.ActionTravel {
+		line := "Traveling to " + gameState.Catalog.LocationName(gameState.Destination())
+		if action.Target != gameState.Destination() {
+			line += ", next stop " + gameState.Catalog.LocationName(action.Target)
+		}
+		b.WriteString("\n" + line + "\n")
+	}
+
+	// Destinations
+	dests := destinations(gameState)
+	selected, _ := v.selected(gameState)
+	b.WriteString("\n" + dimStyle.Render(fmt.Sprintf("  %-22s %-8s %-6s %s", "Destination", "Kind", "Time", "Via")) + "\n")
+	for _, dest := range dests {
+		var via []string
+		for _, leg := range dest.route[:len(dest.route)-1] {
+			via = append(via, gameState.Catalog.LocationName(leg.To))
+		}
+		line := fmt.Sprintf("%-22s %-8s %-6s %s", dest.location.Name, dest.location.Kind,
+			dest.time.Round(time.Second), strings.Join(via, ", "))
+		if dest.location == selected.location {
+			line = selectedStyle.Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(line + "\n")
+	}
+
+	// Selected destination
+	if selected.location != nil {
+		b.WriteString("\n" + selected.location.Name + "\n")
+		b.WriteString(describeLocation(selected.location, gameState, dimStyle))
+	}
+
+	b.WriteString("\n" + dimStyle.Render(fmt.Sprintf("%s = Select  |  %s = Travel  |  %s = Stop traveling",
+		keys.Hint(v.keyMap.Up, v.keyMap.Down), keys.Hint(v.keyMap.Travel), keys.Hint(v.keyMap.Stop))))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Render("World Map"),
+		"",
+		b.String(),
+	)
+}
+
+// describeLocation lists what can be done at a location
+func describeLocation(location *game.Location, gameState *game.State, dimStyle lipgloss.Style) string {
+	var b strings.Builder
+	line := func(label string, names []string) {
+		if len(names) > 0 {
+			b.WriteString(fmt.Sprintf("  %-10s %s\n", label+":", strings.Join(names, ", ")))
+		}
+	}
+
+	var nodes, stations, monsters []string
+	for _, id := range location.Nodes {
+		if node, ok := gameState.Catalog.ResourceNode(id); ok {
+			nodes = append(nodes, node.Name)
+		}
+	}
+	for _, id := range location.Stations {
+		if station, ok := gameState.Catalog.Station(id); ok {
+			stations = append(stations, station.Name)
+		}
+	}
+	for _, id := range location.Monsters {
+		if monster, ok := gameState.Catalog.Monster(id); ok {
+			monsters = append(monsters, monster.Name)
+		}
+	}
+	line("Gather", nodes)
+	line("Stations", stations)
+	line("Monsters", monsters)
+	if b.Len() == 0 {
+		return dimStyle.Render("  Nothing to do here") + "\n"
+	}
+	return b.String()
+}